@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthPrincipal is the authenticated caller of a request, taken from a validated JWT
+type AuthPrincipal struct {
+	UserID  uint64   // ID of the authenticated user
+	Roles   []string // Roles granted to the user
+	TokenID string   // ID (jti) of the token used to authenticate
+}
+
+// HasRole checks if the principal has been granted the given role
+func (p AuthPrincipal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey is the key of the principal in the request context
+type principalKey struct{}
+
+// SetPrincipal stores the principal in the request context of the gin context
+func SetPrincipal(c *gin.Context, principal AuthPrincipal) {
+	c.Request = c.Request.WithContext(NewContext(c.Request.Context(), principal))
+}
+
+// NewContext returns a copy of ctx carrying the given principal
+func NewContext(ctx context.Context, principal AuthPrincipal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of the request, ok is false on unauthenticated requests.
+// It accepts a *gin.Context as well as the request context passed to services.
+func FromContext(ctx context.Context) (principal AuthPrincipal, ok bool) {
+	if c, isGin := ctx.(*gin.Context); isGin {
+		if c.Request == nil {
+			return AuthPrincipal{}, false
+		}
+		ctx = c.Request.Context()
+	}
+	principal, ok = ctx.Value(principalKey{}).(AuthPrincipal)
+	return principal, ok
+}
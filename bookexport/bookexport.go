@@ -0,0 +1,166 @@
+package bookexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+)
+
+// Formats of the exports
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned for a format other than csv, ndjson and xlsx
+var ErrUnsupportedFormat = errors.New("export format must be csv, ndjson or xlsx")
+
+// contentTypes are the content types of the formats
+var contentTypes = map[string]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatNDJSON: "application/x-ndjson",
+	FormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Writer writes the rows of an export one by one, Close must be called after the last row
+type Writer interface {
+	WriteRow(values []interface{}) error // values are strings, integers, floats or string lists, in the order of the columns
+	Close() error                        // flush the buffered rows and finish the file
+}
+
+// ContentType returns the content type of the format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// NewWriter returns a writer of the format to w, the header with the columns is written first
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w, columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Columns returns the columns of the book rows, withOwner adds the owner of the book
+func Columns(withOwner bool) []string {
+	columns := []string{"id", "title", "author", "authors", "price", "currency", "description", "isbn", "categories", "tags", "average_rating", "review_count"}
+	if withOwner {
+		columns = append(columns, "owner_id", "owner_email")
+	}
+	return columns
+}
+
+// BookRow returns the values of the book in the order of Columns
+func BookRow(b entity.Book, withOwner bool) []interface{} {
+	authors := make([]string, len(b.Authors))
+	for i, a := range b.Authors {
+		authors[i] = a.Name
+	}
+	categories := make([]string, len(b.Categories))
+	for i, c := range b.Categories {
+		categories[i] = c.Name
+	}
+	tags := make([]string, len(b.Tags))
+	for i, t := range b.Tags {
+		tags[i] = t.Name
+	}
+	isbn := ""
+	if b.ISBN != nil {
+		isbn = *b.ISBN
+	}
+
+	row := []interface{}{b.ID, b.Title, b.Author, authors, b.Price.Amount, b.Price.Currency, b.Description, isbn, categories, tags, b.AverageRating, b.ReviewCount}
+	if withOwner {
+		email := ""
+		if b.User != nil {
+			email = b.User.Email
+		}
+		row = append(row, b.UserID, email)
+	}
+	return row
+}
+
+// csvWriter writes a CSV file, lists are joined with semicolons like the CSV import expects them
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// newCSVWriter returns a csvWriter with the header written
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := &csvWriter{w: csv.NewWriter(w)}
+	return writer, writer.w.Write(columns)
+}
+
+// WriteRow writes the values as a CSV record
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			record[i] = escapeFormula(v)
+		case []string:
+			record[i] = escapeFormula(strings.Join(v, "; "))
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.w.Write(record)
+}
+
+// Close flushes the buffered records
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// escapeFormula prefixes text that a spreadsheet would run as a formula with a quote
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ndjsonWriter writes one JSON object per row, the keys are the columns in their order
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+// WriteRow writes the values as a JSON object on its own line
+func (nw *ndjsonWriter) WriteRow(values []interface{}) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(nw.columns[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(nw.w, b.String())
+	return err
+}
+
+// Close has nothing to finish, every row is complete
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,211 @@
+package bookimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+)
+
+// Formats of the accepted import files
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrUnsupportedFormat is returned when the content type is neither CSV nor NDJSON
+var ErrUnsupportedFormat = errors.New("import must be text/csv or application/x-ndjson")
+
+// ErrTooManyRows is returned when the file has more rows than allowed
+var ErrTooManyRows = errors.New("import has too many rows")
+
+// listSeparator separates the values of the author_ids, category_ids and tags columns of a CSV file
+const listSeparator = ";"
+
+// maxLineSize limits a single line of an NDJSON file
+const maxLineSize = 1 << 20
+
+// Limits bounds the size of the imports and how they are processed
+type Limits struct {
+	MaxSize  int64 // largest accepted file in bytes
+	MaxRows  int   // most rows in a file
+	SyncRows int   // files with more rows are imported in the background
+	Workers  int   // background imports running at the same time
+}
+
+// Row is a parsed row of an import file, Err is set when the row could not be read
+type Row struct {
+	Line int                      // line of the row in the file, starting at 1
+	Book dto.BookCreateDTORequest // book of the row
+	Err  error                    // why the row could not be read
+}
+
+// FormatFromContentType returns the format of the content type, or ErrUnsupportedFormat
+func FormatFromContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Parse reads the rows of the file in the format, errors of single rows are kept in the rows
+func Parse(r io.Reader, format string, maxRows int) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r, maxRows)
+	case FormatNDJSON:
+		return parseNDJSON(r, maxRows)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+/*
+parseCSV reads a CSV file with a header row naming the columns title, author, author_ids, price, currency,
+description, isbn, category_ids and tags in any order, the list columns are separated by semicolons
+*/
+func parseCSV(r io.Reader, maxRows int) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case "title", "author", "author_ids", "price", "currency", "description", "isbn", "category_ids", "tags":
+		default:
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV file has no title column")
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err // the reader cannot continue after a malformed quote
+		}
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		row := Row{Line: line, Err: err}
+		if err == nil {
+			row.Book, row.Err = csvBook(record, columns)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// csvBook returns the book of a CSV record
+func csvBook(record []string, columns map[string]int) (dto.BookCreateDTORequest, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	book := dto.BookCreateDTORequest{
+		Title:       field("title"),
+		Author:      field("author"),
+		Description: field("description"),
+		Price:       money.New(0, field("currency")),
+		ISBN:        field("isbn"),
+		TagNames:    splitList(field("tags")),
+	}
+	var err error
+	if price := field("price"); price != "" {
+		if book.Price.Amount, err = strconv.ParseInt(price, 10, 64); err != nil {
+			return book, fmt.Errorf("invalid price %q", price)
+		}
+	}
+	if book.AuthorIDs, err = parseIDs(field("author_ids")); err != nil {
+		return book, fmt.Errorf("invalid author_ids: %w", err)
+	}
+	if book.CategoryIDs, err = parseIDs(field("category_ids")); err != nil {
+		return book, fmt.Errorf("invalid category_ids: %w", err)
+	}
+	return book, nil
+}
+
+// parseNDJSON reads one JSON book per line, blank lines are skipped
+func parseNDJSON(r io.Reader, maxRows int) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var rows []Row
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		row := Row{Line: line}
+		if err := json.Unmarshal(data, &row.Book); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// splitList returns the trimmed non-empty values of a list column
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, listSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseIDs returns the ids of a list column
+func parseIDs(value string) ([]uint64, error) {
+	var ids []uint64
+	for _, v := range splitList(value) {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an id", v)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached lookup result, not found results are cached too
+type cacheEntry struct {
+	metadata  BookMetadata
+	err       error
+	expiresAt time.Time
+}
+
+// cachedProvider caches the lookups of another CatalogProvider in memory
+type cachedProvider struct {
+	provider CatalogProvider
+	ttl      time.Duration
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+}
+
+// NewCachedProvider method is used to wrap a CatalogProvider with an in-memory cache
+func NewCachedProvider(provider CatalogProvider, ttl time.Duration) CatalogProvider {
+	return &cachedProvider{provider: provider, ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// LookupISBN returns the cached metadata, or asks the wrapped provider
+func (p *cachedProvider) LookupISBN(ctx context.Context, isbn string) (BookMetadata, error) {
+	now := time.Now()
+
+	p.mu.Lock()
+	entry, ok := p.entries[isbn]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.metadata, entry.err
+	}
+
+	metadata, err := p.provider.LookupISBN(ctx, isbn)
+
+	// Only cache answers of the catalog, not failures to reach it
+	if err == nil || errors.Is(err, ErrNotFound) {
+		p.mu.Lock()
+		p.sweep(now)
+		p.entries[isbn] = cacheEntry{metadata: metadata, err: err, expiresAt: now.Add(p.ttl)}
+		p.mu.Unlock()
+	}
+	return metadata, err
+}
+
+// sweep removes the expired entries
+func (p *cachedProvider) sweep(now time.Time) {
+	for isbn, entry := range p.entries {
+		if now.After(entry.expiresAt) {
+			delete(p.entries, isbn)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the catalog has no book with the ISBN
+var ErrNotFound = errors.New("book not found in catalog")
+
+// BookMetadata is the bibliographic data of an edition returned by a catalog
+type BookMetadata struct {
+	ISBN        string   `json:"isbn"`
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Publisher   string   `json:"publisher"`
+	PageCount   int      `json:"page_count"`
+	Description string   `json:"description"`
+	CoverURL    string   `json:"cover_url"`
+}
+
+// CatalogProvider is a contract of what an external book catalog should be able to do
+type CatalogProvider interface {
+	// LookupISBN returns the metadata of the edition with the ISBN-13, or ErrNotFound
+	LookupISBN(ctx context.Context, isbn string) (BookMetadata, error)
+}
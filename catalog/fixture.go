@@ -0,0 +1,40 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+)
+
+// fixtureProvider looks up books in a local JSON file, used for development and tests
+type fixtureProvider struct {
+	books map[string]BookMetadata
+}
+
+// NewFixtureProvider method is used to create a CatalogProvider from a JSON file holding a list of BookMetadata
+func NewFixtureProvider(path string) (CatalogProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []BookMetadata
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	books := make(map[string]BookMetadata, len(list))
+	for _, b := range list {
+		books[b.ISBN] = b
+	}
+	return &fixtureProvider{books: books}, nil
+}
+
+// LookupISBN returns the metadata of the edition with the ISBN-13, or ErrNotFound
+func (p *fixtureProvider) LookupISBN(ctx context.Context, isbn string) (BookMetadata, error) {
+	book, ok := p.books[isbn]
+	if !ok {
+		return BookMetadata{}, ErrNotFound
+	}
+	return book, nil
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// openLibraryProvider looks up books with the Open Library books API
+type openLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenLibraryProvider method is used to create a CatalogProvider for an Open Library compatible API
+func NewOpenLibraryProvider(baseURL string) CatalogProvider {
+	return &openLibraryProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// openLibraryName is a named entity (author, publisher) of the Open Library response
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+// openLibraryBook is the part of the Open Library "jscmd=data" response used here
+type openLibraryBook struct {
+	Title         string            `json:"title"`
+	Subtitle      string            `json:"subtitle"`
+	Authors       []openLibraryName `json:"authors"`
+	Publishers    []openLibraryName `json:"publishers"`
+	NumberOfPages int               `json:"number_of_pages"`
+	Notes         json.RawMessage   `json:"notes"` // either a string or {"value": "..."}
+	Cover         struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// LookupISBN returns the metadata of the edition with the ISBN-13, or ErrNotFound
+func (p *openLibraryProvider) LookupISBN(ctx context.Context, isbn string) (BookMetadata, error) {
+	bibkey := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {bibkey}, "format": {"json"}, "jscmd": {"data"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return BookMetadata{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Propagate the trace to the catalog
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return BookMetadata{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return BookMetadata{}, fmt.Errorf("catalog returned status %d", res.StatusCode)
+	}
+
+	// The response is an object keyed by bibkey, empty when the book is unknown
+	var body map[string]openLibraryBook
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return BookMetadata{}, err
+	}
+	book, ok := body[bibkey]
+	if !ok {
+		return BookMetadata{}, ErrNotFound
+	}
+
+	metadata := BookMetadata{
+		ISBN:        isbn,
+		Title:       book.Title,
+		PageCount:   book.NumberOfPages,
+		Description: parseNotes(book.Notes),
+		CoverURL:    book.Cover.Large,
+	}
+	if book.Subtitle != "" {
+		metadata.Title = book.Title + ": " + book.Subtitle
+	}
+	if metadata.CoverURL == "" {
+		metadata.CoverURL = book.Cover.Medium
+	}
+	for _, a := range book.Authors {
+		metadata.Authors = append(metadata.Authors, a.Name)
+	}
+	if len(book.Publishers) > 0 {
+		metadata.Publisher = book.Publishers[0].Name
+	}
+	return metadata, nil
+}
+
+// parseNotes reads the notes field, which is either a string or a typed text value
+func parseNotes(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	json.Unmarshal(raw, &typed)
+	return typed.Value
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/catalog"
+)
+
+// SetupCatalogProvider creates the catalog provider configured by CATALOG_PROVIDER (openlibrary or fixture)
+func SetupCatalogProvider() catalog.CatalogProvider {
+	var provider catalog.CatalogProvider
+
+	switch os.Getenv("CATALOG_PROVIDER") { // Load the CATALOG_PROVIDER from the .env file
+	case "fixture":
+		path := os.Getenv("CATALOG_FIXTURE_FILE") // Load the CATALOG_FIXTURE_FILE from the .env file
+		if path == "" {
+			path = "catalog/fixtures/books.json" // If the environment variable is empty, use a default value
+		}
+		p, err := catalog.NewFixtureProvider(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		provider = p
+	default:
+		baseURL := os.Getenv("CATALOG_BASE_URL") // Load the CATALOG_BASE_URL from the .env file
+		if baseURL == "" {
+			baseURL = "https://openlibrary.org" // If the environment variable is empty, use a default value
+		}
+		provider = catalog.NewOpenLibraryProvider(baseURL)
+	}
+
+	ttl := os.Getenv("CATALOG_CACHE_TTL") // Load the CATALOG_CACHE_TTL from the .env file
+	if ttl == "" {
+		ttl = "24h" // If the environment variable is empty, use a default value
+	}
+	cacheTTL, err := time.ParseDuration(ttl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return catalog.NewCachedProvider(provider, cacheTTL)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -35,6 +36,11 @@ func SetupDatabase() *gorm.DB {
 		log.Fatal(err)
 	}
 
+	// Create a span for every SQL statement
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		log.Fatal(err)
+	}
+
 	sqlDB, err := db.DB()
 
 	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
@@ -49,8 +55,23 @@ func SetupDatabase() *gorm.DB {
 	// SetConnIdleTime sets the maximum amount of time a connection may be reused.
 	sqlDB.SetConnMaxLifetime(10 * time.Hour)
 
+	// Prices without currency are in the default currency
+	SetupMoney()
+
 	// Migrate the schema
-	db.AutoMigrate(&entity.User{}, &entity.Book{})
+	db.AutoMigrate(&entity.User{}, &entity.Book{}, &entity.Author{}, &entity.Category{}, &entity.Tag{}, &entity.Review{}, &entity.Shelf{}, &entity.ShelfEntry{}, &entity.BookFile{}, &entity.ImportJob{}, &entity.Loan{}, &entity.WaitlistEntry{}, &entity.BookCopy{}, &entity.CartItem{}, &entity.Order{}, &entity.OrderItem{}, &entity.PaymentEvent{}, &entity.AuditEntry{}, &entity.BookCollaborator{}, &entity.RateLimitBucket{}, &entity.IdempotencyRecord{})
+
+	// Link the books to their authors
+	migrateBookAuthors(db)
+
+	// Create the default shelves of the existing users
+	migrateDefaultShelves(db)
+
+	// Give the prices written before currencies existed their currency
+	legacyCurrency := LegacyPriceCurrency()
+	migrateMoneyColumn(db, &entity.Book{}, "price", legacyCurrency)
+	migrateMoneyColumn(db, &entity.Order{}, "total", legacyCurrency)
+	migrateMoneyColumn(db, &entity.OrderItem{}, "price", legacyCurrency)
 
 	return db
 
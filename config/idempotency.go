@@ -0,0 +1,39 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/idempotency"
+	"gorm.io/gorm"
+)
+
+// SetupIdempotencyStore creates the idempotency store configured by IDEMPOTENCY_STORE (memory or sql)
+func SetupIdempotencyStore(db *gorm.DB) idempotency.Store {
+	switch os.Getenv("IDEMPOTENCY_STORE") { // Load the IDEMPOTENCY_STORE from the .env file
+	case "sql":
+		return idempotency.NewSQLStore(db)
+	default:
+		return idempotency.NewMemoryStore()
+	}
+}
+
+// IdempotencyTTL reads how long idempotency keys are kept from IDEMPOTENCY_TTL
+func IdempotencyTTL() time.Duration {
+	value := os.Getenv("IDEMPOTENCY_TTL") // Load the IDEMPOTENCY_TTL from the .env file
+	if value == "" {
+		value = "24h" // If the environment variable is empty, use a default value
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ttl
+}
+
+// IdempotencyMaxBody reads the largest body buffered to fingerprint a request with an Idempotency-Key from
+// IDEMPOTENCY_MAX_BODY, it defaults to IMPORT_MAX_SIZE since the import is the largest idempotent request
+func IdempotencyMaxBody() int64 {
+	return int64(positiveInt("IDEMPOTENCY_MAX_BODY", envOrDefault("IMPORT_MAX_SIZE", "10485760")))
+}
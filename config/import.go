@@ -0,0 +1,36 @@
+package config
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/bookimport"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+)
+
+// ImportLimits reads the limits of the book imports from IMPORT_MAX_SIZE, IMPORT_MAX_ROWS, IMPORT_SYNC_ROWS and IMPORT_WORKERS
+func ImportLimits() bookimport.Limits {
+	return bookimport.Limits{
+		MaxSize:  int64(positiveInt("IMPORT_MAX_SIZE", "10485760")),
+		MaxRows:  positiveInt("IMPORT_MAX_ROWS", "10000"),
+		SyncRows: positiveInt("IMPORT_SYNC_ROWS", "100"),
+		Workers:  positiveInt("IMPORT_WORKERS", "2"),
+	}
+}
+
+// FailInterruptedImports marks failed the import jobs left unfinished by the previous run, their rows were only held in memory
+func FailInterruptedImports(repo repository.ImportJobRepository) {
+	if _, err := repo.FailUnfinished(context.Background(), "interrupted by a restart"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// positiveInt reads a positive integer from the environment variable, or the default value when it is empty
+func positiveInt(key string, defaultValue string) int {
+	value, err := strconv.Atoi(envOrDefault(key, defaultValue))
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s", key)
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"go.uber.org/zap"
+)
+
+// SetupLogger creates the application logger configured by LOG_LEVEL and LOG_FORMAT
+func SetupLogger() *zap.Logger {
+
+	godotenv.Load() // Load .env file (if any)
+
+	level := os.Getenv("LOG_LEVEL")   // Load the LOG_LEVEL from the .env file (debug, info, warn, error)
+	format := os.Getenv("LOG_FORMAT") // Load the LOG_FORMAT from the .env file (json or console)
+
+	logger, err := logging.New(level, format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return logger
+}
+
+// CloseLogger flushes any buffered log entries
+func CloseLogger(logger *zap.Logger) {
+	logger.Sync() // Sync may fail on stdout, nothing left to do about it
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"log"
+	"math"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+migrateBookAuthors links the books created before authors existed to their authors.
+The free-text Author column is split into names, names with the same key ("J.R.R. Tolkien"
+and "JRR Tolkien") become a single author. The column keeps the original text, every book
+is linked in its own transaction so a failure leaves no author without book.
+Books already linked to an author are skipped, so the migration can run on every start.
+*/
+func migrateBookAuthors(db *gorm.DB) {
+	ctx := context.Background()
+
+	var books []entity.Book
+	result := db.Where("author <> '' AND NOT EXISTS (SELECT 1 FROM book_authors WHERE book_authors.book_id = books.id)").
+		FindInBatches(&books, 100, func(tx *gorm.DB, batch int) error {
+			for i := range books {
+				err := db.Transaction(func(bookTx *gorm.DB) error {
+					authors, err := repository.NewAuthorRepository(bookTx).FindOrCreateByNames(ctx, helper.SplitAuthorNames(books[i].Author))
+					if err != nil || len(authors) == 0 {
+						return err
+					}
+					return bookTx.Model(&books[i]).Association("Authors").Append(authors)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	if result.Error != nil {
+		log.Fatal(result.Error)
+	}
+}
+
+/*
+migrateDefaultShelves creates the default shelves of the users registered before shelves existed.
+Users with at least one shelf are skipped, so the migration can run on every start.
+*/
+func migrateDefaultShelves(db *gorm.DB) {
+	var users []entity.User
+	result := db.Select("id").Where("NOT EXISTS (SELECT 1 FROM shelves WHERE shelves.user_id = users.id)").
+		FindInBatches(&users, 100, func(tx *gorm.DB, batch int) error {
+			for _, u := range users {
+				if err := repository.InsertDefaultShelves(db, u.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	if result.Error != nil {
+		log.Fatal(result.Error)
+	}
+}
+
+/*
+migrateMoneyColumn moves the amounts of the integer column written before prices had a currency into the
+amount and currency columns of the money field with the same name. The old amounts were whole units of
+currencyCode, they are converted to its minor unit (unchanged for a currency without minor unit, like IDR)
+and the old column is dropped. Only the rows without currency are converted, so the migration cannot convert
+an amount twice.
+*/
+func migrateMoneyColumn(db *gorm.DB, model interface{}, column string, currencyCode string) {
+	if !db.Migrator().HasColumn(model, column) {
+		return
+	}
+	amount := interface{}(clause.Column{Name: column})
+	if scale := money.Scale(currencyCode); scale > 0 {
+		amount = gorm.Expr("? * ?", clause.Column{Name: column}, int64(math.Pow10(scale)))
+	}
+	result := db.Model(model).Where(column + "_currency = ''").UpdateColumns(map[string]interface{}{
+		column + "_amount":   amount,
+		column + "_currency": currencyCode,
+	})
+	if result.Error != nil {
+		log.Fatal(result.Error)
+	}
+	if err := db.Migrator().DropColumn(model, column); err != nil {
+		log.Fatal(err)
+	}
+}
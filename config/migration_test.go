@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// legacyBook is a book row as written before prices had a currency, with the columns of the money field added
+type legacyBook struct {
+	ID            uint64
+	Price         int64
+	PriceAmount   int64
+	PriceCurrency string
+}
+
+func (legacyBook) TableName() string { return "books" }
+
+func TestMigrateMoneyColumn(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int64
+	}{
+		{"USD", 10000000},
+		{"IDR", 100000},
+		{"KWD", 100000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.AutoMigrate(&legacyBook{}); err != nil {
+				t.Fatal(err)
+			}
+			db.Create(&legacyBook{Price: 100000})
+
+			migrateMoneyColumn(db, &legacyBook{}, "price", tt.currency)
+
+			if db.Migrator().HasColumn(&legacyBook{}, "price") {
+				t.Error("the legacy column is not dropped")
+			}
+			var got struct {
+				PriceAmount   int64
+				PriceCurrency string
+			}
+			db.Table("books").Select("price_amount", "price_currency").Take(&got)
+			if got.PriceAmount != tt.want || got.PriceCurrency != tt.currency {
+				t.Errorf("price = %d %s, want %d %s", got.PriceAmount, got.PriceCurrency, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMigrateBookAuthors(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entity.User{}, &entity.Book{}, &entity.Author{}); err != nil {
+		t.Fatal(err)
+	}
+	user := entity.User{Name: "owner", Email: "owner@test", Password: "secret"}
+	db.Create(&user)
+	book := entity.Book{Title: "The Hobbit", Author: "Tolkien, J.R.R.", UserID: user.ID}
+	db.Omit("Authors").Create(&book)
+
+	migrateBookAuthors(db)
+
+	var got entity.Book
+	db.Preload("Authors").Take(&got, book.ID)
+	if got.Author != "Tolkien, J.R.R." {
+		t.Errorf("author = %q, want the original text", got.Author)
+	}
+	if len(got.Authors) != 1 || got.Authors[0].Name != "J.R.R. Tolkien" {
+		t.Errorf("authors = %+v, want J.R.R. Tolkien", got.Authors)
+	}
+}
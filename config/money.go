@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"golang.org/x/text/language"
+)
+
+// SetupMoney sets the currency of the prices given without currency from DEFAULT_CURRENCY and the locale of the formatted prices from MONEY_LOCALE
+func SetupMoney() {
+	currency := envOrDefault("DEFAULT_CURRENCY", "USD")
+	if !money.IsValidCurrency(currency) {
+		log.Fatal("invalid DEFAULT_CURRENCY")
+	}
+	locale, err := language.Parse(envOrDefault("MONEY_LOCALE", "en-US"))
+	if err != nil {
+		log.Fatal("invalid MONEY_LOCALE")
+	}
+	money.SetDefaults(currency, locale)
+}
+
+// LegacyPriceCurrency reads the currency of the prices written before prices had a currency from LEGACY_PRICE_CURRENCY, the DEFAULT_CURRENCY when empty
+func LegacyPriceCurrency() string {
+	currency := envOrDefault("LEGACY_PRICE_CURRENCY", money.DefaultCurrency())
+	if !money.IsValidCurrency(currency) {
+		log.Fatal("invalid LEGACY_PRICE_CURRENCY")
+	}
+	return currency
+}
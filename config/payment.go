@@ -0,0 +1,29 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+	"go.uber.org/zap"
+)
+
+// SetupPaymentGateway creates the payment gateway configured by PAYMENT_GATEWAY, only the fake gateway exists for now
+func SetupPaymentGateway(logger *zap.Logger) payment.PaymentGateway {
+	switch envOrDefault("PAYMENT_GATEWAY", "fake") {
+	case "fake":
+		return payment.NewFakeGateway(envOrDefault("PAYMENT_WEBHOOK_URL", "http://localhost:8080/api/payments/webhook"), PaymentWebhookSecret(), logger)
+	default:
+		log.Fatal("invalid PAYMENT_GATEWAY")
+		return nil
+	}
+}
+
+// PaymentWebhookSecret reads the secret signing the webhook bodies from PAYMENT_WEBHOOK_SECRET
+func PaymentWebhookSecret() string {
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET is required")
+	}
+	return secret
+}
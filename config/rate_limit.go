@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ratelimit"
+	"gorm.io/gorm"
+)
+
+// SetupRateLimitStore creates the rate limit store configured by RATE_LIMIT_STORE (memory or sql)
+func SetupRateLimitStore(db *gorm.DB) ratelimit.Store {
+	switch os.Getenv("RATE_LIMIT_STORE") { // Load the RATE_LIMIT_STORE from the .env file
+	case "sql":
+		return ratelimit.NewSQLStore(db)
+	default:
+		return ratelimit.NewMemoryStore()
+	}
+}
+
+// RateLimit reads the limit of a route group from the given environment variable, for example RATE_LIMIT_BOOKS=60/1m
+func RateLimit(env string, defaultLimit string) ratelimit.Limit {
+	value := os.Getenv(env) // Load the limit from the .env file
+	if value == "" {
+		value = defaultLimit // If the environment variable is empty, use a default value
+	}
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return limit
+}
+
+// TrustedProxies reads the comma separated addresses or CIDRs of the reverse proxies whose X-Forwarded-For header
+// is trusted from TRUSTED_PROXIES, no proxy is trusted when it is empty so the client IP is the remote address
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") { // Load the TRUSTED_PROXIES from the .env file
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"log"
+	"time"
+	_ "time/tzdata" // time zones for images without the zoneinfo files
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"gorm.io/gorm"
+)
+
+// SetupReportRepository creates the report repository with its results cached for REPORT_CACHE_TTL
+func SetupReportRepository(db *gorm.DB) repository.ReportRepository {
+	ttl, err := time.ParseDuration(envOrDefault("REPORT_CACHE_TTL", "5m"))
+	if err != nil || ttl < 0 {
+		log.Fatal("invalid REPORT_CACHE_TTL")
+	}
+	return repository.NewCachedReportRepository(repository.NewReportRepository(db), ttl)
+}
+
+// ReportTimezone reads from REPORT_TIMEZONE the time zone the reports cut their days and weeks in
+func ReportTimezone() *time.Location {
+	location, err := time.LoadLocation(envOrDefault("REPORT_TIMEZONE", "UTC"))
+	if err != nil {
+		log.Fatal("invalid REPORT_TIMEZONE")
+	}
+	return location
+}
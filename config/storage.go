@@ -0,0 +1,80 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/storage"
+)
+
+// SetupBlobStore creates the file storage configured by STORAGE_DRIVER (local or s3)
+func SetupBlobStore(signer *storage.Signer) storage.BlobStore {
+	switch os.Getenv("STORAGE_DRIVER") { // Load the STORAGE_DRIVER from the .env file
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    envOrDefault("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	default:
+		store, err := storage.NewLocalStore(LocalStorageDir(), envOrDefault("STORAGE_PUBLIC_URL", "http://localhost:8080/files"),
+			envOrDefault("STORAGE_DOWNLOAD_URL", "http://localhost:8080/api/files/download"), signer)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return store
+	}
+}
+
+// SetupSigner creates the signer of the download URLs (STORAGE_SIGNING_KEY, default the JWT secret key)
+func SetupSigner() *storage.Signer {
+	return storage.NewSigner(envOrDefault("STORAGE_SIGNING_KEY", os.Getenv("JWT_SECRET_KEY")))
+}
+
+// LocalStorageDir returns the directory of the local storage, empty when the files are stored elsewhere
+func LocalStorageDir() string {
+	if os.Getenv("STORAGE_DRIVER") == "s3" {
+		return ""
+	}
+	return envOrDefault("STORAGE_LOCAL_DIR", "uploads")
+}
+
+// CoverMaxSize returns the largest accepted cover image in bytes (COVER_MAX_SIZE, default 5 MiB)
+func CoverMaxSize() int64 {
+	size, err := strconv.ParseInt(envOrDefault("COVER_MAX_SIZE", "5242880"), 10, 64)
+	if err != nil || size <= 0 {
+		log.Fatal("invalid COVER_MAX_SIZE")
+	}
+	return size
+}
+
+// EbookMaxSize returns the largest accepted e-book file in bytes (EBOOK_MAX_SIZE, default 50 MiB)
+func EbookMaxSize() int64 {
+	size, err := strconv.ParseInt(envOrDefault("EBOOK_MAX_SIZE", "52428800"), 10, 64)
+	if err != nil || size <= 0 {
+		log.Fatal("invalid EBOOK_MAX_SIZE")
+	}
+	return size
+}
+
+// SignedURLTTL returns how long a signed download URL stays valid (SIGNED_URL_TTL, default 15m)
+func SignedURLTTL() time.Duration {
+	ttl, err := time.ParseDuration(envOrDefault("SIGNED_URL_TTL", "15m"))
+	if err != nil || ttl <= 0 {
+		log.Fatal("invalid SIGNED_URL_TTL")
+	}
+	return ttl
+}
+
+// envOrDefault returns the environment variable, or the default value when it is empty
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
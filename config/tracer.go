@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// SetupTracer creates the tracer provider and registers it as the global provider
+func SetupTracer() *sdktrace.TracerProvider {
+
+	godotenv.Load() // Load .env file (if any)
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME") // Load the OTEL_SERVICE_NAME from the .env file
+	if serviceName == "" {
+		serviceName = "gin-gorm-jwt-mysql" // If the environment variable is empty, use a default value
+	}
+
+	// Describe this service in every exported span
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	// Create the exporter configured by OTEL_TRACES_EXPORTER (stdout, file, otlp or none)
+	exporter, err := newTraceExporter(os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(options...)
+
+	// Register the tracer provider and the W3C traceparent propagator globally
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp
+}
+
+// newTraceExporter creates a span exporter by name, returns nil when tracing export is disabled
+func newTraceExporter(name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		path := os.Getenv("OTEL_TRACES_FILE") // Load the OTEL_TRACES_FILE from the .env file
+		if path == "" {
+			path = "traces.json" // If the environment variable is empty, use a default value
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return stdouttrace.New(stdouttrace.WithWriter(f))
+	case "otlp":
+		// The endpoint is read from OTEL_EXPORTER_OTLP_ENDPOINT by the exporter itself
+		return otlptracehttp.New(context.Background())
+	default:
+		return nil, nil
+	}
+}
+
+// CloseTracer flushes the remaining spans and shuts down the tracer provider
+func CloseTracer(tp *sdktrace.TracerProvider) {
+	if err := tp.Shutdown(context.Background()); err != nil {
+		log.Println(err)
+	}
+}
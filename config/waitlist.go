@@ -0,0 +1,15 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// WaitlistOfferTTL reads from WAITLIST_OFFER_TTL how long a book stays offered to the first person of its waitlist
+func WaitlistOfferTTL() time.Duration {
+	ttl, err := time.ParseDuration(envOrDefault("WAITLIST_OFFER_TTL", "48h"))
+	if err != nil || ttl <= 0 {
+		log.Fatal("invalid WAITLIST_OFFER_TTL")
+	}
+	return ttl
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create AuditController interface for AuditController
+type AuditController interface {
+	BookHistory(c *gin.Context) // Get The History Of Data Book
+	RevertBook(c *gin.Context)  // Revert Data Book To A Version Of Its History
+	Search(c *gin.Context)      // Search The Audit Log For An Admin
+}
+
+// Create auditController struct for AuditController interface with AuditService and Logger
+type auditController struct {
+	auditService services.AuditService // AuditService for the audit log
+	logger       *zap.Logger           // Logger for structured logging
+}
+
+// Create New AuditController with AuditService and Logger dependency injection for AuditController interface
+func NewAuditController(auditServ services.AuditService, logger *zap.Logger) AuditController {
+	return &auditController{auditService: auditServ, logger: logger}
+}
+
+// BookHistory function for get the changes of data book newest first, the owner or an editor is checked by BookEditor
+func (c *auditController) BookHistory(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var entries []entity.AuditEntry = c.auditService.GetBookHistory(ctx.Request.Context(), bookID)
+
+	// Return success response with status code 200 and the history
+	response := helper.SuccessResponse(http.StatusOK, "Get Book History", entries)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// RevertBook function for put data book back to the version of a history entry, the owner or an editor is checked by BookEditor
+func (c *auditController) RevertBook(ctx *gin.Context) {
+
+	// Get id and entryId from url parameters
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	entryID, err := strconv.ParseUint(ctx.Param("entryId"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "History Entry Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book, err := c.auditService.RevertBook(ctx.Request.Context(), bookID, entryID)
+	switch {
+	case errors.Is(err, services.ErrAuditEntryNotFound):
+		response := helper.ErrorsResponse(http.StatusNotFound, "History Entry Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	case errors.Is(err, services.ErrNotRevertable), errors.Is(err, services.ErrDuplicateISBN):
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Revert Data Book", book)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Search function for get the entries of the audit log matching the filter, the RequireRole middleware has already checked the admin role
+func (c *auditController) Search(ctx *gin.Context) {
+
+	// Bind the filter from the query string
+	var filter dto.AuditFilterDTORequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid filter", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var entries []entity.AuditEntry = c.auditService.Search(ctx.Request.Context(), filter)
+
+	// Return success response with status code 200 and the entries
+	response := helper.SuccessResponse(http.StatusOK, "Search Audit Log", entries)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
 )
 
 // Auth Controller interface is a contract for all auth controller
@@ -21,15 +23,17 @@ type AuthController interface {
 type authController struct {
 	authService services.AuthService // inject auth service
 	jwtService  services.JWTService  // inject jwt service
+	logger      *zap.Logger          // inject logger
 }
 
 /*
 Create a new instance of Auth Controller with auth service and jwt service injected as dependency
 */
-func NewAuthController(authService services.AuthService, jwtService services.JWTService) AuthController {
+func NewAuthController(authService services.AuthService, jwtService services.JWTService, logger *zap.Logger) AuthController {
 	return &authController{
 		authService: authService, // inject auth service
 		jwtService:  jwtService,  // inject jwt service
+		logger:      logger,      // inject logger
 	}
 }
 
@@ -48,11 +52,11 @@ func (c *authController) Login(ctx *gin.Context) {
 	}
 
 	// Check if the email and password is valid
-	authResult := c.authService.VerifyCredential(loginDTO.Email, loginDTO.Password)
+	authResult := c.authService.VerifyCredential(ctx.Request.Context(), loginDTO.Email, loginDTO.Password)
 
 	// Check if the email and password is valid
 	if v, ok := authResult.(entity.User); ok {
-		generatedToken := c.jwtService.GenerateToken(strconv.Itoa(int(v.ID)))
+		generatedToken := c.jwtService.GenerateToken(strconv.Itoa(int(v.ID)), []string{v.Role})
 		v.Token = generatedToken
 		response := helper.SuccessResponse(http.StatusOK, "Login Success", v)
 		ctx.JSON(http.StatusOK, response)
@@ -60,6 +64,7 @@ func (c *authController) Login(ctx *gin.Context) {
 	}
 
 	// If the email and password is not valid
+	logging.With(ctx.Request.Context(), c.logger).Warn("Invalid credential")
 	response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", "Invalid Credential", helper.EmptyObject{})
 	ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
 }
@@ -81,7 +86,8 @@ func (c *authController) Register(ctx *gin.Context) {
 	}
 
 	// Check if the email is valid and unique in the database
-	if !c.authService.IsDuplicateEmail(registerDTO.Email) {
+	if !c.authService.IsDuplicateEmail(ctx.Request.Context(), registerDTO.Email) {
+		logging.With(ctx.Request.Context(), c.logger).Info("Register rejected: email already registered")
 		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Email already registered", helper.EmptyObject{})
 		ctx.AbortWithStatusJSON(http.StatusConflict, response)
 		return
@@ -89,10 +95,15 @@ func (c *authController) Register(ctx *gin.Context) {
 		/*
 			if the email is valid and unique in the database then register the user
 		*/
-		createdUser := c.authService.CreateUser(registerDTO) // create new user
+		createdUser, err := c.authService.CreateUser(ctx.Request.Context(), registerDTO) // create new user
+		if err != nil {
+			response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+			return
+		}
 
 		// generate token
-		token := c.jwtService.GenerateToken(strconv.Itoa(int(createdUser.ID)))
+		token := c.jwtService.GenerateToken(strconv.Itoa(int(createdUser.ID)), []string{createdUser.Role})
 
 		// set token to the user
 		createdUser.Token = token
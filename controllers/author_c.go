@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create AuthorController interface for AuthorController
+type AuthorController interface {
+	GetAll(c *gin.Context)       // Get All Data Author
+	GetByID(c *gin.Context)      // Get Data Author By ID With Their Books
+	CreateAuthor(c *gin.Context) // Create Data Author
+	UpdateAuthor(c *gin.Context) // Update Data Author
+	DeleteAuthor(c *gin.Context) // Delete Data Author
+}
+
+// Create authorController struct for AuthorController interface with AuthorService and Logger
+type authorController struct {
+	authorService services.AuthorService // AuthorService for CRUD Author
+	logger        *zap.Logger            // Logger for structured logging
+}
+
+// Create New AuthorController with AuthorService and Logger dependency injection for AuthorController interface
+func NewAuthorController(authorServ services.AuthorService, logger *zap.Logger) AuthorController {
+	return &authorController{authorService: authorServ, logger: logger}
+}
+
+// GetAll function for get all data author
+func (c *authorController) GetAll(ctx *gin.Context) {
+	var authors []entity.Author = c.authorService.GetAll(ctx.Request.Context())
+
+	// Return success response with status code 200 and data authors
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Author", authors)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data author by id with their books
+func (c *authorController) GetByID(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	authorID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Author Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var author entity.Author = c.authorService.GetByID(ctx.Request.Context(), authorID)
+
+	if author.ID == 0 { // Check author is empty or not
+		response := helper.ErrorsResponse(http.StatusNotFound, "Author Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and data author
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Author", author)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateAuthor function for create data author
+func (c *authorController) CreateAuthor(ctx *gin.Context) {
+
+	// Create authorCreateDTO variable for binding data from request body
+	var authorCreateDTO dto.AuthorCreateDTORequest
+
+	// Bind data from request body to authorCreateDTO variable
+	errDTO := ctx.ShouldBind(&authorCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if the author already exists (names are compared without case and punctuation)
+	if c.authorService.IsDuplicateName(ctx.Request.Context(), authorCreateDTO.Name, 0) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Author already exists", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	result := c.authorService.CreateAuthor(ctx.Request.Context(), authorCreateDTO)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Author", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateAuthor function for update data author, the names of their books are updated as well
+func (c *authorController) UpdateAuthor(ctx *gin.Context) {
+
+	// Create authorUpdateDTO variable for binding data from request body
+	var authorUpdateDTO dto.AuthorUpdateDTORequest
+
+	// Bind data from request body to authorUpdateDTO variable
+	errDTO := ctx.ShouldBind(&authorUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Get id from url parameter with key id
+	authorID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Author Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check author exists
+	if c.authorService.GetByID(ctx.Request.Context(), authorID).ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Author Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Check if the new name belongs to another author
+	if c.authorService.IsDuplicateName(ctx.Request.Context(), authorUpdateDTO.Name, authorID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Author already exists", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	authorUpdateDTO.ID = authorID // The author of the url, ignore the id of the body
+
+	result, err := c.authorService.UpdateAuthor(ctx.Request.Context(), authorUpdateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Author", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteAuthor function for delete data author without books
+func (c *authorController) DeleteAuthor(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	authorID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Author Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Authors of a book cannot be deleted, the books would lose them
+	if c.authorService.HasBooks(ctx.Request.Context(), authorID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Author still has books", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	author := entity.Author{ID: authorID}
+	c.authorService.DeleteAuthor(ctx.Request.Context(), author)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Author", author)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
@@ -1,50 +1,75 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/bookexport"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/catalog"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
 )
 
 // Create BookController interface for BookController
 type BookController interface {
 	GetAll(c *gin.Context)       // Get All Data Book
 	GetByID(c *gin.Context)      // Get Data Book By ID
+	GetByISBN(c *gin.Context)    // Get Data Book By ISBN
+	GetShared(c *gin.Context)    // Get Data Book By Share Link
 	GetAllMyBook(c *gin.Context) // Get All Data Book By User
 	CreateMyBook(c *gin.Context) // Create Data Book By User
 	UpdateMyBook(c *gin.Context) // Update Data Book By User
 	DeleteMyBook(c *gin.Context) // Delete Data Book By User
+	FromISBN(c *gin.Context)     // Pre-fill Data Book From The Catalog
+	UploadCover(c *gin.Context)  // Upload The Cover Image Of Data Book
+	DeleteCover(c *gin.Context)  // Delete The Cover Image Of Data Book
+	Export(c *gin.Context)       // Export Data Book By User
+	ExportAll(c *gin.Context)    // Export All Data Book For An Admin
 }
 
 /*
 Create bookController struct for BookController interface with
-BookService and JWTService
+BookService, CatalogService, CoverService, BookFileService and Logger
 */
 type bookController struct {
-	bookService services.BookService // BookService for CRUD Book
-	jwtService  services.JWTService  // JWTService for validate token
+	bookService    services.BookService     // BookService for CRUD Book
+	catalogService services.CatalogService  // CatalogService for book metadata
+	coverService   services.CoverService    // CoverService for cover images
+	fileService    services.BookFileService // BookFileService for e-book files
+	logger         *zap.Logger              // Logger for structured logging
 }
 
 /*
-Create New BookController with BookService and JWTService dependency injection for BookController interface
+Create New BookController with BookService, CatalogService, CoverService, BookFileService and Logger dependency injection for BookController interface
 */
-func NewBookController(bookServ services.BookService, jwtServ services.JWTService) BookController {
-	return &bookController{bookService: bookServ, jwtService: jwtServ}
+func NewBookController(bookServ services.BookService, catalogServ services.CatalogService, coverServ services.CoverService, fileServ services.BookFileService, logger *zap.Logger) BookController {
+	return &bookController{bookService: bookServ, catalogService: catalogServ, coverService: coverServ, fileService: fileServ, logger: logger}
 }
 
 // GetAll function for get all data book
 func (c *bookController) GetAll(ctx *gin.Context) {
+	// Bind the category and tag filter from the query string
+	var filter dto.BookFilterDTORequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid filter", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
 	/*
 		Get All Data Book from BookService and assign to books variable for get all data book
 	*/
-	var books []entity.Book = c.bookService.GetAll()
+	var books []entity.Book = c.bookService.GetAll(ctx.Request.Context(), filter)
 
 	// Return success response with status code 200 and data books
 	result := helper.SuccessResponse(http.StatusOK, "Get All Data Book", books)
@@ -68,9 +93,9 @@ func (c *bookController) GetByID(ctx *gin.Context) {
 	/*
 		Get data book by id from BookService and assign to book variable for get data book by id from BookService and assign to book variable
 	*/
-	var book entity.Book = c.bookService.GetByID(bookID)
+	var book entity.Book = c.bookService.GetByID(ctx.Request.Context(), bookID)
 
-	if book == (entity.Book{}) { // Check book is empty or not
+	if book.ID == 0 { // Check book is empty or not
 		// Return error response with status code 404 and message book not found
 		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
 
@@ -88,11 +113,53 @@ func (c *bookController) GetByID(ctx *gin.Context) {
 	}
 }
 
+// GetShared function for get the unlisted data book of a share link
+func (c *bookController) GetShared(ctx *gin.Context) {
+	var book entity.Book = c.bookService.GetByShareToken(ctx.Request.Context(), ctx.Param("token"))
+	if book.ID == 0 { // The link is unknown, or the book is no longer unlisted
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and data book
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Book", book)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByISBN function for get all data book with an ISBN-10 or ISBN-13
+func (c *bookController) GetByISBN(ctx *gin.Context) {
+
+	// Check the isbn from url parameter with key isbn
+	isbn := ctx.Param("isbn")
+	if !helper.IsValidISBN(isbn) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", "Invalid ISBN", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Get all data book with the isbn, a book can be owned by several users
+	var books []entity.Book = c.bookService.GetByISBN(ctx.Request.Context(), isbn)
+
+	if len(books) == 0 { // Check books is empty or not
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and data books
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Book", books)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
 // GetAllMyBook function for get all data book by user
 func (c *bookController) GetAllMyBook(ctx *gin.Context) {
 
 	//Get All Data Book By User from BookService and assign to books variable
-	var book []entity.Book = c.bookService.GetAllMyBook()
+	var book []entity.Book = c.bookService.GetAllMyBook(ctx.Request.Context())
 
 	// Return success response with status code 200 and data books
 	response := helper.SuccessResponse(http.StatusOK, "Get All Data Book", book)
@@ -117,22 +184,29 @@ func (c *bookController) CreateMyBook(ctx *gin.Context) {
 		return
 	}
 
-	// Get Authorization from header
-	authHeader := ctx.GetHeader("Authorization")
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
 
-	// Get token from Authorization
-	userID := c.getUserIDByToken(authHeader)
+	// The book always belongs to the authenticated user
+	bookCreateDTO.UserID = principal.UserID
 
-	// Create Book variable for binding data from bookCreateDTO variable to Book
-	id, err := strconv.ParseUint(userID, 10, 64)
+	// Check the linked authors and categories exist
+	if !c.isValidLinks(ctx, bookCreateDTO.AuthorIDs, bookCreateDTO.CategoryIDs) {
+		return
+	}
 
-	// Check error from strconv.ParseUint
-	if err == nil {
-		bookCreateDTO.UserID = id
+	// Check if the user already has a book with this ISBN
+	if c.bookService.IsDuplicateISBN(ctx.Request.Context(), principal.UserID, bookCreateDTO.ISBN, 0) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You already have a book with this ISBN", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
 	}
 
 	// Create Book variable for binding data from bookCreateDTO variable to Book
-	result := c.bookService.CreateMyBook(bookCreateDTO)
+	result, err := c.bookService.CreateMyBook(ctx.Request.Context(), bookCreateDTO)
+	if !c.isSaved(ctx, err) {
+		return
+	}
 
 	// response variable for return response with status code and message
 	response := helper.SuccessResponse(http.StatusCreated, "Create Data Book", result)
@@ -142,7 +216,10 @@ func (c *bookController) CreateMyBook(ctx *gin.Context) {
 
 }
 
-// UpdateMyBook function for update data book by user
+/*
+UpdateMyBook function for update data book by user,
+the BookEditor middleware has already checked that the user owns or edits the book
+*/
 func (c *bookController) UpdateMyBook(ctx *gin.Context) {
 
 	// Create bookUpdateDTO variable for binding data from request body
@@ -158,53 +235,62 @@ func (c *bookController) UpdateMyBook(ctx *gin.Context) {
 		return
 	}
 
-	// Get Authorization from header
-	authHeader := ctx.GetHeader("Authorization")
-
-	// Get token from Authorization
-	token, errToken := c.jwtService.ValidateToken(authHeader)
+	// Get id from url parameter with key id
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 
-	// Check error from jwtService.ValidateToken
-	if errToken != nil {
-		panic(errToken.Error())
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
 	}
 
-	// Get userID from token
-	claims := token.Claims.(jwt.MapClaims)
+	// The owner keeps the book when an editor updates it
+	current := c.bookService.GetByID(ctx.Request.Context(), id)
+	ownerID := current.UserID
 
-	// Get userID from token and assign to userID variable
-	userID := fmt.Sprintf("%v", claims["user_id"])
+	// Only the owner shares the book, an editor cannot change its visibility
+	principal, _ := auth.FromContext(ctx)
+	if bookUpdateDTO.Visibility != "" && bookUpdateDTO.Visibility != current.Visibility &&
+		!c.bookService.IsAllowedActionBook(ctx.Request.Context(), principal.UserID, id, services.BookActionManage) {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "Only the owner can change the visibility of the book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
 
-	// Create Book variable for binding data from bookUpdateDTO variable to Book
-	if c.bookService.IsAllowedActionBook(userID, bookUpdateDTO.ID) {
+	// The book of the url is the one checked by BookEditor, ignore the id of the body
+	bookUpdateDTO.ID = id
+	bookUpdateDTO.UserID = ownerID
 
-		id, errID := strconv.ParseUint(userID, 10, 64) // Parse userID to uint64
+	// Check the linked authors and categories exist
+	if !c.isValidLinks(ctx, bookUpdateDTO.AuthorIDs, bookUpdateDTO.CategoryIDs) {
+		return
+	}
 
-		// Check error from strconv.ParseUint
-		if errID == nil {
-			bookUpdateDTO.UserID = id
-		}
+	// Check if the owner already has another book with this ISBN
+	if c.bookService.IsDuplicateISBN(ctx.Request.Context(), ownerID, bookUpdateDTO.ISBN, id) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "The owner already has a book with this ISBN", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
 
-		// Update data book by user
-		result := c.bookService.UpdateMyBook(bookUpdateDTO)
+	// Update data book by user
+	result, err := c.bookService.UpdateMyBook(ctx.Request.Context(), bookUpdateDTO)
+	if !c.isSaved(ctx, err) {
+		return
+	}
 
-		// response variable for return response with status code and message
-		response := helper.SuccessResponse(http.StatusOK, "Update Data Book", result)
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Book", result)
 
-		// Return Response
-		ctx.JSON(http.StatusOK, response)
-	} else {
-		/*
-			If user is not allowed to update data book
-			Return error response with status code 403 and message user is not allowed to update data book
-		*/
-		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to update this book", helper.EmptyObject{})
-		// Return Response
-		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
-	}
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
 }
 
-// DeleteMyBook function for delete data book by user
+/*
+DeleteMyBook function for delete data book by user,
+the BookOwner middleware has already checked that the user owns the book
+*/
 func (c *bookController) DeleteMyBook(ctx *gin.Context) {
 
 	var book entity.Book // Create Book variable from entity.Book
@@ -221,57 +307,244 @@ func (c *bookController) DeleteMyBook(ctx *gin.Context) {
 
 	book.ID = id // Assign id to Book.ID
 
-	// Get Authorization from header
-	authHeader := ctx.GetHeader("Authorization")
+	// Remove the cover images and e-book files first, the rows keep their keys until then
+	if _, err := c.coverService.DeleteCover(ctx.Request.Context(), c.bookService.GetByID(ctx.Request.Context(), id)); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+	if err := c.fileService.DeleteAll(ctx.Request.Context(), id); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Delete data book by user
+	if err := c.bookService.DeleteMyBook(ctx.Request.Context(), book); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Book", book)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// FromISBN function for pre-fill data book from the catalog, the book is not created
+func (c *bookController) FromISBN(ctx *gin.Context) {
+
+	// Create fromISBNDTO variable for binding data from request body
+	var fromISBNDTO dto.BookFromISBNDTORequest
+
+	// Bind data from request body to fromISBNDTO variable
+	errDTO := ctx.ShouldBind(&fromISBNDTO)
 
-	// Get token from Authorization
-	token, errToken := c.jwtService.ValidateToken(authHeader)
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
 
-	// Check error from jwtService.ValidateToken
-	if errToken != nil {
-		panic(errToken.Error())
+	// Fetch the metadata from the catalog
+	result, err := c.catalogService.PrefillBook(ctx.Request.Context(), fromISBNDTO.ISBN)
+
+	if errors.Is(err, catalog.ErrNotFound) { // The catalog does not know the book
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil { // The catalog could not be reached
+		response := helper.ErrorsResponse(http.StatusBadGateway, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadGateway, response)
+		return
 	}
 
-	// Get userID from token
-	claims := token.Claims.(jwt.MapClaims)
+	// Return success response with status code 200 and the pre-filled book
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Book From Catalog", result)
 
-	// Get userID from token and assign to userID variable
-	userID := fmt.Sprintf("%v", claims["user_id"])
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
 
-	// Check if user is allowed to delete data book
-	if c.bookService.IsAllowedActionBook(userID, book.ID) {
+/*
+UploadCover function for upload the cover image of data book from the multipart field "cover",
+the BookEditor middleware has already checked that the user owns or edits the book
+*/
+func (c *bookController) UploadCover(ctx *gin.Context) {
 
-		c.bookService.DeleteMyBook(book) // Delete data book by user
+	// Limit the request body, with some room for the multipart envelope
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.coverService.MaxSize()+1<<20)
 
-		// response variable for return response with status code and message
-		response := helper.SuccessResponse(http.StatusOK, "Delete Data Book", book)
+	// Get the file from the multipart form with key cover
+	file, header, err := ctx.Request.FormFile("cover")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to upload cover", services.ErrCoverTooLarge.Error(), helper.EmptyObject{})
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+			return
+		}
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to upload cover", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	defer file.Close()
 
-		// Return Response
-		ctx.JSON(http.StatusOK, response)
-	} else { // If user is not allowed to delete data book
+	if header.Size > c.coverService.MaxSize() {
+		response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to upload cover", services.ErrCoverTooLarge.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+		return
+	}
 
-		// response variable for return response with status code and message
-		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to delete this book", helper.EmptyObject{})
+	// Get id from url parameter with key id
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
 
-		// Return Response
-		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+	result, err := c.coverService.UploadCover(ctx.Request.Context(), c.bookService.GetByID(ctx.Request.Context(), id), file)
+	switch {
+	case errors.Is(err, services.ErrCoverTooLarge):
+		response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to upload cover", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+		return
+	case errors.Is(err, services.ErrUnsupportedImage), errors.Is(err, services.ErrImageTooManyPixels):
+		response := helper.ErrorsResponse(http.StatusUnsupportedMediaType, "Failed to upload cover", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, response)
+		return
+	case err != nil:
+		logging.With(ctx.Request.Context(), c.logger).Error("Failed to upload cover", zap.Uint64("book_id", id), zap.Error(err))
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to upload cover", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
 	}
+
+	// Return success response with status code 200 and data book
+	response := helper.SuccessResponse(http.StatusOK, "Upload Cover Book", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
 }
 
-// GetMyBookByID function for get data book by user
-func (c *bookController) getUserIDByToken(token string) string {
+/*
+DeleteCover function for delete the cover image of data book,
+the BookEditor middleware has already checked that the user owns or edits the book
+*/
+func (c *bookController) DeleteCover(ctx *gin.Context) {
 
-	// Get token from Authorization
-	myToken, err := c.jwtService.ValidateToken(token)
+	// Get id from url parameter with key id
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
 
-	// Check error from jwtService.ValidateToken
+	result, err := c.coverService.DeleteCover(ctx.Request.Context(), c.bookService.GetByID(ctx.Request.Context(), id))
 	if err != nil {
-		panic(err.Error())
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
 	}
 
-	// Get userID from token
-	claims := myToken.Claims.(jwt.MapClaims)
+	// Return success response with status code 200 and data book
+	response := helper.SuccessResponse(http.StatusOK, "Delete Cover Book", result)
 
-	// Get userID from token and assign to userID variable
-	return claims["user_id"].(string)
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Export function for stream the books of the authenticated user as a CSV, NDJSON or XLSX file
+func (c *bookController) Export(ctx *gin.Context) {
+	principal, _ := auth.FromContext(ctx)
+	c.export(ctx, principal.UserID)
+}
+
+// ExportAll function for stream the books of every user with their owner, the RequireRole middleware has already checked the admin role
+func (c *bookController) ExportAll(ctx *gin.Context) {
+	c.export(ctx, 0)
+}
+
+// export streams the books of the user (all users when 0) matching the query string in the requested format
+func (c *bookController) export(ctx *gin.Context, userID uint64) {
+
+	// Bind the format and the filters from the query string
+	var exportDTO dto.BookExportDTORequest
+	if err := ctx.ShouldBindQuery(&exportDTO); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid filter", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if exportDTO.MinPrice != nil && exportDTO.MaxPrice != nil && *exportDTO.MinPrice > *exportDTO.MaxPrice {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid filter", "min_price is greater than max_price", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if exportDTO.Format == "" {
+		exportDTO.Format = bookexport.FormatCSV
+	}
+	exportDTO.UserID = userID
+
+	// The headers are sent with the first rows, errors after that can only be logged
+	fileName := fmt.Sprintf("books-%s.%s", time.Now().UTC().Format("20060102"), exportDTO.Format)
+	ctx.Header("Content-Type", bookexport.ContentType(exportDTO.Format))
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+
+	withOwner := userID == 0
+	writer, err := bookexport.NewWriter(exportDTO.Format, ctx.Writer, bookexport.Columns(withOwner))
+	if err == nil {
+		err = c.bookService.Export(ctx.Request.Context(), exportDTO, func(books []entity.Book) error {
+			for _, book := range books {
+				if err := writer.WriteRow(bookexport.BookRow(book, withOwner)); err != nil {
+					return err
+				}
+			}
+			ctx.Writer.Flush() // send every batch as soon as it is read
+			return nil
+		})
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		logging.With(ctx.Request.Context(), c.logger).Error("Export interrupted", zap.Uint64("user_id", userID), zap.String("format", exportDTO.Format), zap.Error(err))
+	}
+}
+
+// isValidLinks checks that every author and category id exists, abort with 400 if not
+func (c *bookController) isValidLinks(ctx *gin.Context, authorIDs []uint64, categoryIDs []uint64) bool {
+	if !c.bookService.IsValidAuthorIDs(ctx.Request.Context(), authorIDs) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Unknown author id", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return false
+	}
+	if !c.bookService.IsValidCategoryIDs(ctx.Request.Context(), categoryIDs) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Unknown category id", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return false
+	}
+	return true
+}
+
+// isSaved checks the book was saved, abort with 409 when the owner already has another book with the isbn or 500 on other errors
+func (c *bookController) isSaved(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrDuplicateISBN):
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "The owner already has a book with this ISBN", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return false
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return false
+	}
+	return true
 }
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create BookCopyController interface for BookCopyController
+type BookCopyController interface {
+	GetAll(c *gin.Context)     // Get All Data Copy Of A Book
+	GetByID(c *gin.Context)    // Get Data Copy By ID
+	CreateCopy(c *gin.Context) // Create Data Copy Of A Book
+	UpdateCopy(c *gin.Context) // Update Data Copy Of A Book
+	DeleteCopy(c *gin.Context) // Delete Data Copy Of A Book
+}
+
+// Create bookCopyController struct for BookCopyController interface with BookCopyService, BookService and Logger
+type bookCopyController struct {
+	bookCopyService services.BookCopyService // BookCopyService for CRUD Copy
+	bookService     services.BookService     // BookService for the books of the copies
+	logger          *zap.Logger              // Logger for structured logging
+}
+
+// Create New BookCopyController with BookCopyService, BookService and Logger dependency injection for BookCopyController interface
+func NewBookCopyController(bookCopyServ services.BookCopyService, bookServ services.BookService, logger *zap.Logger) BookCopyController {
+	return &bookCopyController{bookCopyService: bookCopyServ, bookService: bookServ, logger: logger}
+}
+
+// GetAll function for get all data copy of the book ordered by barcode, filtered by status
+func (c *bookCopyController) GetAll(ctx *gin.Context) {
+
+	// Create filter variable for binding data from query string
+	var filter dto.BookCopyFilterDTORequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	var copies []entity.BookCopy = c.bookCopyService.GetByBook(ctx.Request.Context(), book.ID, filter)
+
+	// Return success response with status code 200 and data copies
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Copy", copies)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data copy by id
+func (c *bookCopyController) GetByID(ctx *gin.Context) {
+	bookCopy, ok := c.copy(ctx)
+	if !ok {
+		return
+	}
+
+	// Return success response with status code 200 and data copy
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Copy", bookCopy)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// CreateCopy function for create data copy, the owner is checked by BookOwner
+func (c *bookCopyController) CreateCopy(ctx *gin.Context) {
+
+	// Create copyCreateDTO variable for binding data from request body
+	var copyCreateDTO dto.BookCopyCreateDTORequest
+
+	// Bind data from request body to copyCreateDTO variable
+	errDTO := ctx.ShouldBind(&copyCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	// Check if the barcode belongs to another copy
+	if c.bookCopyService.IsDuplicateBarcode(ctx.Request.Context(), copyCreateDTO.Barcode, 0) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Barcode already exists", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	copyCreateDTO.BookID = book.ID // The copy always belongs to the book of the url
+
+	result, err := c.bookCopyService.CreateCopy(ctx.Request.Context(), copyCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Copy", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateCopy function for update data copy, the owner is checked by BookOwner
+func (c *bookCopyController) UpdateCopy(ctx *gin.Context) {
+
+	// Create copyUpdateDTO variable for binding data from request body
+	var copyUpdateDTO dto.BookCopyUpdateDTORequest
+
+	// Bind data from request body to copyUpdateDTO variable
+	errDTO := ctx.ShouldBind(&copyUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	bookCopy, ok := c.copy(ctx)
+	if !ok {
+		return
+	}
+
+	// Check if the barcode belongs to another copy
+	if c.bookCopyService.IsDuplicateBarcode(ctx.Request.Context(), copyUpdateDTO.Barcode, bookCopy.ID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Barcode already exists", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	copyUpdateDTO.ID = bookCopy.ID // The copy of the url, ignore the id of the body
+
+	result, err := c.bookCopyService.UpdateCopy(ctx.Request.Context(), copyUpdateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Copy", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteCopy function for delete data copy, the owner is checked by BookOwner
+func (c *bookCopyController) DeleteCopy(ctx *gin.Context) {
+	bookCopy, ok := c.copy(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.bookCopyService.DeleteCopy(ctx.Request.Context(), bookCopy); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Copy", bookCopy)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// book gets the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *bookCopyController) book(ctx *gin.Context) (entity.Book, bool) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Book{}, false
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), bookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Book{}, false
+	}
+	return book, true
+}
+
+// copy gets the copy of the :copyId parameter that belongs to the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *bookCopyController) copy(ctx *gin.Context) (entity.BookCopy, bool) {
+	bookID, errBook := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	copyID, errCopy := strconv.ParseUint(ctx.Param("copyId"), 10, 64)
+	if errBook != nil || errCopy != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Copy Not Found", "Invalid id", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.BookCopy{}, false
+	}
+
+	bookCopy := c.bookCopyService.GetByID(ctx.Request.Context(), copyID)
+	if bookCopy.ID == 0 || bookCopy.BookID != bookID {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Copy Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.BookCopy{}, false
+	}
+	return bookCopy, true
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ebook"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/storage"
+	"go.uber.org/zap"
+)
+
+// Create BookFileController interface for BookFileController
+type BookFileController interface {
+	GetAll(c *gin.Context)      // Get All E-book File Of A Book
+	UploadFile(c *gin.Context)  // Upload An EPUB Or PDF File Of A Book
+	DownloadURL(c *gin.Context) // Get A Signed Download URL Of An E-book File
+	DeleteFile(c *gin.Context)  // Delete An E-book File Of A Book
+	Download(c *gin.Context)    // Serve A File Of The Local Storage From A Signed URL
+}
+
+/*
+Create bookFileController struct for BookFileController interface with
+BookFileService, BookService, BlobStore, Signer and Logger
+*/
+type bookFileController struct {
+	bookFileService services.BookFileService // BookFileService for the e-book files
+	bookService     services.BookService     // BookService for the books of the files
+	store           storage.BlobStore        // BlobStore read by the signed downloads
+	signer          *storage.Signer          // Signer checking the signed downloads
+	logger          *zap.Logger              // Logger for structured logging
+}
+
+// Create New BookFileController with BookFileService, BookService, BlobStore, Signer and Logger dependency injection for BookFileController interface
+func NewBookFileController(bookFileServ services.BookFileService, bookServ services.BookService, store storage.BlobStore, signer *storage.Signer, logger *zap.Logger) BookFileController {
+	return &bookFileController{bookFileService: bookFileServ, bookService: bookServ, store: store, signer: signer, logger: logger}
+}
+
+/*
+GetAll function for get all e-book file of the book,
+the BookOwner middleware has already checked that the user owns the book
+*/
+func (c *bookFileController) GetAll(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	var files []entity.BookFile = c.bookFileService.GetByBook(ctx.Request.Context(), book.ID)
+
+	// Return success response with status code 200 and data files
+	response := helper.SuccessResponse(http.StatusOK, "Get All E-book File", files)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+/*
+UploadFile function for upload an EPUB or PDF file of the book from the multipart field "file",
+the metadata of an EPUB is returned with the book filled from it,
+the BookOwner middleware has already checked that the user owns the book
+*/
+func (c *bookFileController) UploadFile(ctx *gin.Context) {
+
+	// Limit the request body, with some room for the multipart envelope
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.bookFileService.MaxSize()+1<<20)
+
+	// Get the file from the multipart form with key file
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to upload file", services.ErrEbookTooLarge.Error(), helper.EmptyObject{})
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+			return
+		}
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to upload file", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	defer file.Close()
+
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.bookFileService.UploadFile(ctx.Request.Context(), book, file, header.Size, header.Filename)
+	switch {
+	case errors.Is(err, services.ErrEbookTooLarge):
+		response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to upload file", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+		return
+	case errors.Is(err, ebook.ErrUnsupportedFormat):
+		response := helper.ErrorsResponse(http.StatusUnsupportedMediaType, "Failed to upload file", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, response)
+		return
+	case errors.Is(err, ebook.ErrInvalidEPUB):
+		response := helper.ErrorsResponse(http.StatusUnprocessableEntity, "Failed to upload file", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	case err != nil:
+		logging.With(ctx.Request.Context(), c.logger).Error("Failed to upload e-book", zap.Uint64("book_id", book.ID), zap.Error(err))
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to upload file", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Return success response with status code 201 and the stored file
+	response := helper.SuccessResponse(http.StatusCreated, "Upload E-book File", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+/*
+DownloadURL function for get a signed URL downloading the e-book file until it expires,
+the BookOwner middleware has already checked that the user owns the book
+*/
+func (c *bookFileController) DownloadURL(ctx *gin.Context) {
+	file, ok := c.file(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.bookFileService.DownloadURL(ctx.Request.Context(), file)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Return success response with status code 200 and the signed URL
+	response := helper.SuccessResponse(http.StatusOK, "Get Download URL", result)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+/*
+DeleteFile function for delete the e-book file of the book,
+the BookOwner middleware has already checked that the user owns the book
+*/
+func (c *bookFileController) DeleteFile(ctx *gin.Context) {
+	file, ok := c.file(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.bookFileService.DeleteFile(ctx.Request.Context(), file); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Return success response with status code 200 and the deleted file
+	response := helper.SuccessResponse(http.StatusOK, "Delete E-book File", file)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// Download function for serve a file of the local storage, the URL must carry a valid signature that has not expired
+func (c *bookFileController) Download(ctx *gin.Context) {
+	key, fileName, err := c.signer.Verify(ctx.Request.URL.Query())
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	blob, err := c.store.Get(ctx.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		response := helper.ErrorsResponse(http.StatusNotFound, "File Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+	defer blob.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(fileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, blob); err != nil {
+		logging.With(ctx.Request.Context(), c.logger).Warn("Download interrupted", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// book gets the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *bookFileController) book(ctx *gin.Context) (entity.Book, bool) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Book{}, false
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), bookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Book{}, false
+	}
+	return book, true
+}
+
+// file gets the file of the :fileId parameter that belongs to the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *bookFileController) file(ctx *gin.Context) (entity.BookFile, bool) {
+	bookID, errBook := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	fileID, errFile := strconv.ParseUint(ctx.Param("fileId"), 10, 64)
+	if errBook != nil || errFile != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "File Not Found", "Invalid id", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.BookFile{}, false
+	}
+
+	file := c.bookFileService.GetByID(ctx.Request.Context(), fileID)
+	if file.ID == 0 || file.BookID != bookID {
+		response := helper.ErrorsResponse(http.StatusNotFound, "File Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.BookFile{}, false
+	}
+	return file, true
+}
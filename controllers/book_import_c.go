@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/bookimport"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create BookImportController interface for BookImportController
+type BookImportController interface {
+	Import(c *gin.Context) // Import Data Book From A CSV Or NDJSON Body
+	GetJob(c *gin.Context) // Get The Status And Report Of An Import
+}
+
+// Create bookImportController struct for BookImportController interface with BookImportService and Logger
+type bookImportController struct {
+	bookImportService services.BookImportService // BookImportService for the imports
+	logger            *zap.Logger                // Logger for structured logging
+}
+
+// Create New BookImportController with BookImportService and Logger dependency injection for BookImportController interface
+func NewBookImportController(bookImportServ services.BookImportService, logger *zap.Logger) BookImportController {
+	return &bookImportController{bookImportService: bookImportServ, logger: logger}
+}
+
+/*
+Import function for import data book from the request body, a CSV file (text/csv) or one JSON book
+per line (application/x-ndjson). Every row is checked with the rules of the create book endpoint,
+small imports answer 200 with the report and larger ones 202 with a job to poll
+*/
+func (c *bookImportController) Import(ctx *gin.Context) {
+	limits := c.bookImportService.Limits()
+
+	// Get the format from the content type of the request
+	format, err := bookimport.FormatFromContentType(ctx.GetHeader("Content-Type"))
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusUnsupportedMediaType, "Failed to import books", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, response)
+		return
+	}
+
+	// Read the rows, the body is limited to the maximum import size
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limits.MaxSize)
+	rows, err := bookimport.Parse(body, format, limits.MaxRows)
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to import books", "import is too large", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+		return
+	case errors.Is(err, bookimport.ErrTooManyRows):
+		response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to import books", fmt.Sprintf("%s, at most %d", err.Error(), limits.MaxRows), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+		return
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to import books", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	case len(rows) == 0:
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to import books", "import has no rows", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Validate every row like ctx.ShouldBind validates a single book
+	for i := range rows {
+		if rows[i].Err == nil {
+			rows[i].Err = binding.Validator.ValidateStruct(&rows[i].Book)
+		}
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	job, err := c.bookImportService.Import(ctx.Request.Context(), principal.UserID, format, rows)
+	if err != nil {
+		logging.With(ctx.Request.Context(), c.logger).Error("Failed to import books", zap.Uint64("user_id", principal.UserID), zap.Error(err))
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to import books", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if job.Status == entity.ImportPending {
+		// Return accepted response with status code 202 and the job to poll
+		ctx.Header("Location", fmt.Sprintf("/api/books/import/%d", job.ID))
+		response := helper.SuccessResponse(http.StatusAccepted, "Import Queued", job)
+		ctx.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	// Return success response with status code 200 and the job with its report
+	response := helper.SuccessResponse(http.StatusOK, "Import Books", job)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetJob function for get the status, progress and report of an import of the authenticated user
+func (c *bookImportController) GetJob(ctx *gin.Context) {
+
+	// Get id from url parameter with key jobId
+	jobID, err := strconv.ParseUint(ctx.Param("jobId"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Import Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	// The imports of other users are not found
+	job := c.bookImportService.GetJob(ctx.Request.Context(), jobID)
+	if job.ID == 0 || job.UserID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Import Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and the job
+	response := helper.SuccessResponse(http.StatusOK, "Get Import", job)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
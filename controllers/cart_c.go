@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Create CartController interface for CartController
+type CartController interface {
+	GetCart(c *gin.Context)    // Get The Cart Of The User
+	AddItem(c *gin.Context)    // Add A Book To The Cart
+	RemoveItem(c *gin.Context) // Remove A Book From The Cart
+	ClearCart(c *gin.Context)  // Remove Every Book From The Cart
+	Checkout(c *gin.Context)   // Turn The Cart Into Orders
+}
+
+// Create cartController struct for CartController interface with CartService, OrderService, BookService and Logger
+type cartController struct {
+	cartService  services.CartService  // CartService for the carts
+	orderService services.OrderService // OrderService for the checkout
+	bookService  services.BookService  // BookService for the books added to the carts
+	logger       *zap.Logger           // Logger for structured logging
+}
+
+// Create New CartController with CartService, OrderService, BookService and Logger dependency injection for CartController interface
+func NewCartController(cartServ services.CartService, orderServ services.OrderService, bookServ services.BookService, logger *zap.Logger) CartController {
+	return &cartController{cartService: cartServ, orderService: orderServ, bookService: bookServ, logger: logger}
+}
+
+// GetCart function for get the books in the cart of the authenticated user and their total price
+func (c *cartController) GetCart(ctx *gin.Context) {
+	principal, _ := auth.FromContext(ctx)
+
+	cart := c.cartService.GetCart(ctx.Request.Context(), principal.UserID)
+
+	// Return success response with status code 200 and data cart
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Cart", cart)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// AddItem function for add a book for sale to the cart, never their own book and once
+func (c *cartController) AddItem(ctx *gin.Context) {
+
+	// Create itemCreateDTO variable for binding data from request body
+	var itemCreateDTO dto.CartItemCreateDTORequest
+
+	// Bind data from request body to itemCreateDTO variable
+	errDTO := ctx.ShouldBind(&itemCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), itemCreateDTO.BookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	// Owners cannot buy their own books
+	if book.UserID == principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You cannot buy your own book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// A sold book is not for sale anymore
+	if book.OrderID != nil {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", repository.ErrBookSold.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	// Check if the book is already in the cart
+	if c.cartService.IsInCart(ctx.Request.Context(), principal.UserID, book.ID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "The book is already in your cart", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	itemCreateDTO.UserID = principal.UserID // The cart always belongs to the authenticated user
+
+	result, err := c.cartService.AddItem(ctx.Request.Context(), itemCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Add Book To Cart", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// RemoveItem function for remove the book of the :bookId parameter from the cart
+func (c *cartController) RemoveItem(ctx *gin.Context) {
+
+	// Get bookId from url parameter with key bookId
+	bookID, err := strconv.ParseUint(ctx.Param("bookId"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	err = c.cartService.RemoveItem(ctx.Request.Context(), principal.UserID, bookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "The book is not in your cart", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Remove Book From Cart", c.cartService.GetCart(ctx.Request.Context(), principal.UserID))
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ClearCart function for remove every book from the cart
+func (c *cartController) ClearCart(ctx *gin.Context) {
+	principal, _ := auth.FromContext(ctx)
+	if err := c.cartService.ClearCart(ctx.Request.Context(), principal.UserID); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Clear Cart", helper.EmptyObject{})
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Checkout function for turn the cart into one pending order per seller, nothing is ordered when a book was sold meanwhile
+func (c *cartController) Checkout(ctx *gin.Context) {
+	principal, _ := auth.FromContext(ctx)
+
+	orders, err := c.orderService.Checkout(ctx.Request.Context(), principal.UserID)
+	if errors.Is(err, repository.ErrCartEmpty) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if errors.Is(err, repository.ErrBookSold) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Checkout", orders)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create CategoryController interface for CategoryController
+type CategoryController interface {
+	GetAll(c *gin.Context)         // Get All Data Category With Their Book Count
+	GetByID(c *gin.Context)        // Get Data Category By ID Or Slug
+	CreateCategory(c *gin.Context) // Create Data Category
+	UpdateCategory(c *gin.Context) // Update Data Category
+	MoveCategory(c *gin.Context)   // Move Data Category Below Another Parent
+	DeleteCategory(c *gin.Context) // Delete Data Category
+}
+
+// Create categoryController struct for CategoryController interface with CategoryService and Logger
+type categoryController struct {
+	categoryService services.CategoryService // CategoryService for CRUD Category
+	logger          *zap.Logger              // Logger for structured logging
+}
+
+// Create New CategoryController with CategoryService and Logger dependency injection for CategoryController interface
+func NewCategoryController(categoryServ services.CategoryService, logger *zap.Logger) CategoryController {
+	return &categoryController{categoryService: categoryServ, logger: logger}
+}
+
+// GetAll function for get all data category sorted by path with their book count
+func (c *categoryController) GetAll(ctx *gin.Context) {
+	var categories []entity.Category = c.categoryService.GetAll(ctx.Request.Context())
+
+	// Return success response with status code 200 and data categories
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Category", categories)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data category by id or slug
+func (c *categoryController) GetByID(ctx *gin.Context) {
+	var category entity.Category = c.categoryService.GetByIDOrSlug(ctx.Request.Context(), ctx.Param("id"))
+
+	if category.ID == 0 { // Check category is empty or not
+		response := helper.ErrorsResponse(http.StatusNotFound, "Category Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and data category
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Category", category)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateCategory function for create data category, the slug defaults to the name
+func (c *categoryController) CreateCategory(ctx *gin.Context) {
+
+	// Create categoryCreateDTO variable for binding data from request body
+	var categoryCreateDTO dto.CategoryCreateDTORequest
+
+	// Bind data from request body to categoryCreateDTO variable
+	errDTO := ctx.ShouldBind(&categoryCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check the parent exists
+	if categoryCreateDTO.ParentID != nil && c.categoryService.GetByID(ctx.Request.Context(), *categoryCreateDTO.ParentID).ID == 0 {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Unknown parent category", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	categoryCreateDTO.Slug = c.slug(ctx, categoryCreateDTO.Name, categoryCreateDTO.Slug, 0)
+	if categoryCreateDTO.Slug == "" {
+		return
+	}
+
+	result, err := c.categoryService.CreateCategory(ctx.Request.Context(), categoryCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Category", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateCategory function for update name and slug of data category
+func (c *categoryController) UpdateCategory(ctx *gin.Context) {
+
+	// Create categoryUpdateDTO variable for binding data from request body
+	var categoryUpdateDTO dto.CategoryUpdateDTORequest
+
+	// Bind data from request body to categoryUpdateDTO variable
+	errDTO := ctx.ShouldBind(&categoryUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	category, ok := c.category(ctx)
+	if !ok {
+		return
+	}
+
+	categoryUpdateDTO.ID = category.ID // The category of the url, ignore the id of the body
+	categoryUpdateDTO.Slug = c.slug(ctx, categoryUpdateDTO.Name, categoryUpdateDTO.Slug, category.ID)
+	if categoryUpdateDTO.Slug == "" {
+		return
+	}
+
+	result := c.categoryService.UpdateCategory(ctx.Request.Context(), categoryUpdateDTO)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Category", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// MoveCategory function for move data category with its subcategories below another parent
+func (c *categoryController) MoveCategory(ctx *gin.Context) {
+
+	// Create categoryMoveDTO variable for binding data from request body
+	var categoryMoveDTO dto.CategoryMoveDTORequest
+
+	// Bind data from request body to categoryMoveDTO variable
+	errDTO := ctx.ShouldBind(&categoryMoveDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	category, ok := c.category(ctx)
+	if !ok {
+		return
+	}
+
+	// Check the new parent exists
+	if categoryMoveDTO.ParentID != nil && c.categoryService.GetByID(ctx.Request.Context(), *categoryMoveDTO.ParentID).ID == 0 {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Unknown parent category", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := c.categoryService.MoveCategory(ctx.Request.Context(), category, categoryMoveDTO.ParentID)
+	if errors.Is(err, repository.ErrCategoryCycle) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Move Data Category", c.categoryService.GetByID(ctx.Request.Context(), category.ID))
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteCategory function for delete data category without subcategories, its books are kept
+func (c *categoryController) DeleteCategory(ctx *gin.Context) {
+	category, ok := c.category(ctx)
+	if !ok {
+		return
+	}
+
+	// Subcategories would lose their parent, they must be moved or deleted first
+	if c.categoryService.HasChildren(ctx.Request.Context(), category.ID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Category still has subcategories", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	if err := c.categoryService.DeleteCategory(ctx.Request.Context(), category); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Category", category)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// category gets the category of the :id parameter, abort with 400 or 404 if there is none
+func (c *categoryController) category(ctx *gin.Context) (entity.Category, bool) {
+
+	// Get id from url parameter with key id
+	categoryID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Category Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Category{}, false
+	}
+
+	category := c.categoryService.GetByID(ctx.Request.Context(), categoryID)
+	if category.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Category Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Category{}, false
+	}
+	return category, true
+}
+
+// slug returns the slugified slug (or name when no slug is given), abort with 400 or 409 and return "" if it cannot be used
+func (c *categoryController) slug(ctx *gin.Context, name string, slug string, categoryID uint64) string {
+	if slug == "" {
+		slug = name
+	}
+	slug = helper.Slugify(slug)
+
+	if slug == "" {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Slug must contain letters or digits", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return ""
+	}
+
+	// Check if the slug belongs to another category
+	if c.categoryService.IsDuplicateSlug(ctx.Request.Context(), slug, categoryID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Category slug already exists", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return ""
+	}
+	return slug
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create CollaboratorController interface for CollaboratorController
+type CollaboratorController interface {
+	GetAll(c *gin.Context)         // Get All Collaborators Of A Book
+	Invite(c *gin.Context)         // Invite A User To A Book
+	Revoke(c *gin.Context)         // Remove A Collaborator From A Book
+	TransferBook(c *gin.Context)   // Give A Book To A Collaborator
+	GetInvitations(c *gin.Context) // Get The Invitations Of The User
+	Accept(c *gin.Context)         // Accept An Invitation
+	Decline(c *gin.Context)        // Decline An Invitation
+}
+
+// Create collaboratorController struct for CollaboratorController interface with CollaboratorService and Logger
+type collaboratorController struct {
+	collaboratorService services.CollaboratorService // CollaboratorService for the collaborators of the books
+	logger              *zap.Logger                  // Logger for structured logging
+}
+
+// Create New CollaboratorController with CollaboratorService and Logger dependency injection for CollaboratorController interface
+func NewCollaboratorController(collaboratorServ services.CollaboratorService, logger *zap.Logger) CollaboratorController {
+	return &collaboratorController{collaboratorService: collaboratorServ, logger: logger}
+}
+
+// GetAll function for get all collaborators of the book with their role and status, the owner is checked by BookOwner
+func (c *collaboratorController) GetAll(ctx *gin.Context) {
+	bookID, ok := c.bookID(ctx)
+	if !ok {
+		return
+	}
+
+	var collaborators []entity.BookCollaborator = c.collaboratorService.GetByBook(ctx.Request.Context(), bookID)
+
+	// Return success response with status code 200 and the collaborators
+	response := helper.SuccessResponse(http.StatusOK, "Get All Collaborators", collaborators)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// Invite function for invite the user of the email to the book as viewer or editor, the owner is checked by BookOwner
+func (c *collaboratorController) Invite(ctx *gin.Context) {
+
+	// Create inviteDTO variable for binding data from request body
+	var inviteDTO dto.CollaboratorInviteDTORequest
+	if err := ctx.ShouldBind(&inviteDTO); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	bookID, ok := c.bookID(ctx)
+	if !ok {
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	// The book of the url is the one checked by BookOwner, ignore the ids of the body
+	inviteDTO.BookID = bookID
+	inviteDTO.InviterID = principal.UserID
+
+	result, err := c.collaboratorService.Invite(ctx.Request.Context(), inviteDTO)
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		response := helper.ErrorsResponse(http.StatusNotFound, "User Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	case errors.Is(err, services.ErrInviteOwner):
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	case errors.Is(err, repository.ErrAlreadyCollaborator):
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Invite Collaborator", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Revoke function for remove the collaborator from the book or cancel their invitation, the owner is checked by BookOwner
+func (c *collaboratorController) Revoke(ctx *gin.Context) {
+	bookID, ok := c.bookID(ctx)
+	if !ok {
+		return
+	}
+
+	// Get collaboratorId from url parameter, the collaborator must be one of the book
+	collaboratorID, err := strconv.ParseUint(ctx.Param("collaboratorId"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Collaborator Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	collaborator := c.collaboratorService.GetByID(ctx.Request.Context(), collaboratorID)
+	if collaborator.ID == 0 || collaborator.BookID != bookID {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Collaborator Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	if err := c.collaboratorService.Revoke(ctx.Request.Context(), collaborator); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Revoke Collaborator", collaborator)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// TransferBook function for give the book to a collaborator who accepted the invitation, the owner is checked by BookOwner
+func (c *collaboratorController) TransferBook(ctx *gin.Context) {
+
+	// Create transferDTO variable for binding data from request body
+	var transferDTO dto.BookTransferDTORequest
+	if err := ctx.ShouldBind(&transferDTO); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	bookID, ok := c.bookID(ctx)
+	if !ok {
+		return
+	}
+
+	book, err := c.collaboratorService.TransferBook(ctx.Request.Context(), bookID, transferDTO.UserID)
+	switch {
+	case errors.Is(err, repository.ErrNotCollaborator), errors.Is(err, services.ErrDuplicateISBN):
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Transfer Data Book", book)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetInvitations function for get the invitations waiting for the answer of the authenticated user
+func (c *collaboratorController) GetInvitations(ctx *gin.Context) {
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	var invitations []entity.BookCollaborator = c.collaboratorService.GetInvitations(ctx.Request.Context(), principal.UserID)
+
+	// Return success response with status code 200 and the invitations
+	response := helper.SuccessResponse(http.StatusOK, "Get All Invitations", invitations)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// Accept function for accept an invitation of the authenticated user
+func (c *collaboratorController) Accept(ctx *gin.Context) {
+	c.answer(ctx, "Accept Invitation", c.collaboratorService.Accept)
+}
+
+// Decline function for decline an invitation of the authenticated user
+func (c *collaboratorController) Decline(ctx *gin.Context) {
+	c.answer(ctx, "Decline Invitation", c.collaboratorService.Decline)
+}
+
+// answer answers the invitation of the :id parameter, abort with 404 if it is not waiting for the answer of the user
+func (c *collaboratorController) answer(ctx *gin.Context, message string, answer func(ctx context.Context, collaboratorID uint64, userID uint64) (entity.BookCollaborator, error)) {
+	collaboratorID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invitation Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	result, err := answer(ctx.Request.Context(), collaboratorID, principal.UserID)
+	switch {
+	case errors.Is(err, repository.ErrNoInvitation):
+		response := helper.ErrorsResponse(http.StatusNotFound, "Invitation Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	case err != nil:
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Return success response with status code 200 and the answered invitation
+	response := helper.SuccessResponse(http.StatusOK, message, result)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// bookID gets the book of the :id parameter, abort with 400 if it is not a number
+func (c *collaboratorController) bookID(ctx *gin.Context) (uint64, bool) {
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return 0, false
+	}
+	return bookID, true
+}
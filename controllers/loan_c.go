@@ -0,0 +1,322 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create LoanController interface for LoanController
+type LoanController interface {
+	RequestLoan(c *gin.Context) // Ask The Owner To Lend A Book
+	GetBorrowed(c *gin.Context) // Get All Data Loan Of The User As Borrower
+	GetLent(c *gin.Context)     // Get All Data Loan Of The User As Lender
+	GetByID(c *gin.Context)     // Get Data Loan By Its Borrower Or Lender
+	GetByBook(c *gin.Context)   // Get The Loan History Of A Book
+	ApproveLoan(c *gin.Context) // Lend The Book By The Lender
+	RejectLoan(c *gin.Context)  // Decline The Request By The Lender
+	CancelLoan(c *gin.Context)  // Withdraw The Request By The Borrower
+	ReturnLoan(c *gin.Context)  // Record The Return By The Lender
+}
+
+// Create loanController struct for LoanController interface with LoanService, BookService and Logger
+type loanController struct {
+	loanService services.LoanService // LoanService for the loans
+	bookService services.BookService // BookService for the lent books
+	logger      *zap.Logger          // Logger for structured logging
+}
+
+// Create New LoanController with LoanService, BookService and Logger dependency injection for LoanController interface
+func NewLoanController(loanServ services.LoanService, bookServ services.BookService, logger *zap.Logger) LoanController {
+	return &loanController{loanService: loanServ, bookService: bookServ, logger: logger}
+}
+
+// RequestLoan function for ask the owner to lend a book, never their own book and once while the request is open
+func (c *loanController) RequestLoan(ctx *gin.Context) {
+
+	// Create loanCreateDTO variable for binding data from request body
+	var loanCreateDTO dto.LoanCreateDTORequest
+
+	// Bind data from request body to loanCreateDTO variable
+	errDTO := ctx.ShouldBind(&loanCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// The proposed return date must be in the future
+	if loanCreateDTO.DueAt != nil && !loanCreateDTO.DueAt.After(time.Now()) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "due_at must be in the future", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), loanCreateDTO.BookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	// Owners cannot borrow their own books
+	if book.UserID == principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You cannot borrow your own book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Check if the user already asked for or has the book
+	if c.loanService.HasOpenLoan(ctx.Request.Context(), book.ID, principal.UserID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You already requested or borrowed this book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	loanCreateDTO.BorrowerID = principal.UserID // The loan always belongs to the authenticated user
+
+	result, err := c.loanService.RequestLoan(ctx.Request.Context(), book, loanCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Loan", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetBorrowed function for get all data loan of the authenticated user as borrower, newest first
+func (c *loanController) GetBorrowed(ctx *gin.Context) {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	var loans []entity.Loan = c.loanService.GetBorrowed(ctx.Request.Context(), principal.UserID, filter)
+
+	// Return success response with status code 200 and data loans
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Borrowed Loan", loans)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetLent function for get all data loan of the authenticated user as lender, newest first
+func (c *loanController) GetLent(ctx *gin.Context) {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	var loans []entity.Loan = c.loanService.GetLent(ctx.Request.Context(), principal.UserID, filter)
+
+	// Return success response with status code 200 and data loans
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Lent Loan", loans)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data loan by its borrower or lender
+func (c *loanController) GetByID(ctx *gin.Context) {
+	loan, ok := c.loan(ctx)
+	if !ok {
+		return
+	}
+
+	// Only the borrower and the lender can see the loan
+	principal, _ := auth.FromContext(ctx)
+	if loan.BorrowerID != principal.UserID && loan.LenderID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to see this loan", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Return success response with status code 200 and data loan
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Loan", loan)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByBook function for get the loan history of the book, newest first, the owner is checked by BookOwner
+func (c *loanController) GetByBook(ctx *gin.Context) {
+
+	// Get id from url parameter with key id, BookOwner already checked it
+	bookID, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	var loans []entity.Loan = c.loanService.GetByBook(ctx.Request.Context(), bookID)
+
+	// Return success response with status code 200 and data loans
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Loan Of Book", loans)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// ApproveLoan function for lend the book of the requested loan, by the lender until the due date
+func (c *loanController) ApproveLoan(ctx *gin.Context) {
+
+	// Create loanApproveDTO variable for binding data from request body
+	var loanApproveDTO dto.LoanApproveDTORequest
+
+	// Bind data from request body to loanApproveDTO variable
+	errDTO := ctx.ShouldBind(&loanApproveDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	loan, ok := c.lenderLoan(ctx)
+	if !ok {
+		return
+	}
+
+	// The lender decides the due date, the one proposed by the borrower otherwise
+	dueAt := loanApproveDTO.DueAt
+	if dueAt == nil {
+		dueAt = loan.DueAt
+	}
+	if dueAt == nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "due_at is required", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if !dueAt.After(time.Now()) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "due_at must be in the future", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	result, err := c.loanService.ApproveLoan(ctx.Request.Context(), loan, *dueAt)
+	c.respond(ctx, "Approve Data Loan", result, err)
+}
+
+// RejectLoan function for decline the requested loan by the lender
+func (c *loanController) RejectLoan(ctx *gin.Context) {
+	loan, ok := c.lenderLoan(ctx)
+	if !ok {
+		return
+	}
+	result, err := c.loanService.RejectLoan(ctx.Request.Context(), loan)
+	c.respond(ctx, "Reject Data Loan", result, err)
+}
+
+// CancelLoan function for withdraw the requested loan by the borrower
+func (c *loanController) CancelLoan(ctx *gin.Context) {
+	loan, ok := c.loan(ctx)
+	if !ok {
+		return
+	}
+
+	// Only the borrower can withdraw the request
+	principal, _ := auth.FromContext(ctx)
+	if loan.BorrowerID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to cancel this loan", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	result, err := c.loanService.CancelLoan(ctx.Request.Context(), loan)
+	c.respond(ctx, "Cancel Data Loan", result, err)
+}
+
+// ReturnLoan function for record the return of the book of the active loan by the lender
+func (c *loanController) ReturnLoan(ctx *gin.Context) {
+	loan, ok := c.lenderLoan(ctx)
+	if !ok {
+		return
+	}
+	result, err := c.loanService.ReturnLoan(ctx.Request.Context(), loan)
+	c.respond(ctx, "Return Data Loan", result, err)
+}
+
+// respond writes the loan after a status change, 409 when the loan or the book is not in the expected state
+func (c *loanController) respond(ctx *gin.Context, message string, loan entity.Loan, err error) {
+	if errors.Is(err, repository.ErrLoanStatusChanged) || errors.Is(err, repository.ErrBookOnLoan) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, message, loan)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// filter binds the status and overdue query parameters, abort with 400 if they are invalid
+func (c *loanController) filter(ctx *gin.Context) (dto.LoanFilterDTORequest, bool) {
+	var filter dto.LoanFilterDTORequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return filter, false
+	}
+	return filter, true
+}
+
+// lenderLoan gets the loan of the :id parameter, abort with 403 if the authenticated user is not its lender
+func (c *loanController) lenderLoan(ctx *gin.Context) (entity.Loan, bool) {
+	loan, ok := c.loan(ctx)
+	if !ok {
+		return loan, false
+	}
+
+	// Only the owner of the book decides about the loan
+	principal, _ := auth.FromContext(ctx)
+	if loan.LenderID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to change this loan", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return entity.Loan{}, false
+	}
+	return loan, true
+}
+
+// loan gets the loan of the :id parameter, abort with 400 or 404 if there is none
+func (c *loanController) loan(ctx *gin.Context) (entity.Loan, bool) {
+
+	// Get id from url parameter with key id
+	loanID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Loan Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Loan{}, false
+	}
+
+	loan := c.loanService.GetByID(ctx.Request.Context(), loanID)
+	if loan.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Loan Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Loan{}, false
+	}
+	return loan, true
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create OrderController interface for OrderController
+type OrderController interface {
+	GetPurchases(c *gin.Context) // Get All Data Order Of The User As Buyer
+	GetSales(c *gin.Context)     // Get All Data Order Of The User As Seller
+	GetByID(c *gin.Context)      // Get Data Order By Its Buyer Or Seller
+	ChangeStatus(c *gin.Context) // Move The Order To Another Status
+}
+
+// Create orderController struct for OrderController interface with OrderService and Logger
+type orderController struct {
+	orderService services.OrderService // OrderService for the orders
+	logger       *zap.Logger           // Logger for structured logging
+}
+
+// Create New OrderController with OrderService and Logger dependency injection for OrderController interface
+func NewOrderController(orderServ services.OrderService, logger *zap.Logger) OrderController {
+	return &orderController{orderService: orderServ, logger: logger}
+}
+
+// GetPurchases function for get all data order of the authenticated user as buyer, newest first
+func (c *orderController) GetPurchases(ctx *gin.Context) {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	var orders []entity.Order = c.orderService.GetPurchases(ctx.Request.Context(), principal.UserID, filter)
+
+	// Return success response with status code 200 and data orders
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Purchase", orders)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetSales function for get all data order of the authenticated user as seller, newest first
+func (c *orderController) GetSales(ctx *gin.Context) {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	var orders []entity.Order = c.orderService.GetSales(ctx.Request.Context(), principal.UserID, filter)
+
+	// Return success response with status code 200 and data orders
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Sale", orders)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data order by its buyer or seller
+func (c *orderController) GetByID(ctx *gin.Context) {
+	order, ok := c.order(ctx)
+	if !ok {
+		return
+	}
+
+	// Only the buyer and the seller can see the order
+	principal, _ := auth.FromContext(ctx)
+	if order.BuyerID != principal.UserID && order.SellerID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to see this order", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Return success response with status code 200 and data order
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Order", order)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// ChangeStatus function for move the order on, the seller marks it paid, shipped or cancelled and the buyer cancels it while it is pending
+func (c *orderController) ChangeStatus(ctx *gin.Context) {
+
+	// Create statusDTO variable for binding data from request body
+	var statusDTO dto.OrderStatusDTORequest
+
+	// Bind data from request body to statusDTO variable
+	errDTO := ctx.ShouldBind(&statusDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	order, ok := c.order(ctx)
+	if !ok {
+		return
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	isSeller := order.SellerID == principal.UserID
+	isBuyer := order.BuyerID == principal.UserID && statusDTO.Status == entity.OrderCancelled && order.Status == entity.OrderPending
+	if !isSeller && !isBuyer {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to change this order", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	if !order.CanChangeTo(statusDTO.Status) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "A "+order.Status+" order cannot be "+statusDTO.Status, helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	// Only the payment gateway marks an order with a payment intent paid, once it captured the payment
+	if order.PaymentIntentID != nil && statusDTO.Status == entity.OrderPaid {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "This order is paid through the payment gateway", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	// The money of an order paid through the payment gateway goes back with a refund
+	if order.PaymentIntentID != nil && order.Status != entity.OrderPending && statusDTO.Status == entity.OrderCancelled {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "Refund this order instead of cancelling it", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	result, err := c.orderService.ChangeStatus(ctx.Request.Context(), order, statusDTO.Status)
+	if errors.Is(err, repository.ErrOrderStatusChanged) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Order Status", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// filter binds the status query parameter, abort with 400 if it is invalid
+func (c *orderController) filter(ctx *gin.Context) (dto.OrderFilterDTORequest, bool) {
+	var filter dto.OrderFilterDTORequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return filter, false
+	}
+	return filter, true
+}
+
+// order gets the order of the :id parameter, abort with 400 or 404 if there is none
+func (c *orderController) order(ctx *gin.Context) (entity.Order, bool) {
+
+	// Get id from url parameter with key id
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Order Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Order{}, false
+	}
+
+	order := c.orderService.GetByID(ctx.Request.Context(), orderID)
+	if order.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Order Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Order{}, false
+	}
+	return order, true
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxWebhookSize is the largest webhook body accepted
+const maxWebhookSize = 1 << 20
+
+// Create PaymentController interface for PaymentController
+type PaymentController interface {
+	Purchase(c *gin.Context) // Buy A Book Through The Payment Gateway
+	Refund(c *gin.Context)   // Pay An Order Back Through The Payment Gateway
+	Webhook(c *gin.Context)  // Receive The Events Of The Payment Gateway
+}
+
+// Create paymentController struct for PaymentController interface with PaymentService, BookService, OrderService, webhook secret and Logger
+type paymentController struct {
+	paymentService services.PaymentService // PaymentService for the payments
+	bookService    services.BookService    // BookService for the purchased books
+	orderService   services.OrderService   // OrderService for the refunded orders
+	webhookSecret  string                  // Secret the gateway signs the webhook bodies with
+	logger         *zap.Logger             // Logger for structured logging
+}
+
+// Create New PaymentController with PaymentService, BookService, OrderService, webhook secret and Logger dependency injection for PaymentController interface
+func NewPaymentController(paymentServ services.PaymentService, bookServ services.BookService, orderServ services.OrderService, webhookSecret string, logger *zap.Logger) PaymentController {
+	return &paymentController{paymentService: paymentServ, bookService: bookServ, orderService: orderServ, webhookSecret: webhookSecret, logger: logger}
+}
+
+// Purchase function for buy the book of the :id parameter at its current price, never their own book
+func (c *paymentController) Purchase(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), bookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Owners cannot buy their own books
+	principal, _ := auth.FromContext(ctx)
+	if book.UserID == principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You cannot buy your own book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	order, intent, err := c.paymentService.Purchase(ctx.Request.Context(), book, principal.UserID)
+	if errors.Is(err, repository.ErrBookSold) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if errors.Is(err, services.ErrPaymentFailed) {
+		response := helper.ErrorsResponse(http.StatusBadGateway, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadGateway, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	purchase := dto.PurchaseDTOResponse{Order: order}
+	if intent.ID != "" {
+		purchase.Payment = &intent // free books are not paid through the gateway
+	}
+	response := helper.SuccessResponse(http.StatusCreated, "Purchase Book", purchase)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Refund function for pay the paid or shipped order back by its seller, the order is refunded once the gateway confirms it
+func (c *paymentController) Refund(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Order Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	order := c.orderService.GetByID(ctx.Request.Context(), orderID)
+	if order.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Order Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Only the seller gives the money back
+	principal, _ := auth.FromContext(ctx)
+	if order.SellerID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to refund this order", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	result, err := c.paymentService.Refund(ctx.Request.Context(), order)
+	if errors.Is(err, services.ErrNotRefundable) || errors.Is(err, payment.ErrInvalidState) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadGateway, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadGateway, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusAccepted, "Refund Requested", result)
+
+	// Return Response
+	ctx.JSON(http.StatusAccepted, response)
+}
+
+// Webhook function for apply the signed event of the payment gateway, a retried event is acknowledged without being applied again
+func (c *paymentController) Webhook(ctx *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookSize))
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	event, err := payment.VerifyWebhook(c.webhookSecret, ctx.GetHeader(payment.SignatureHeader), body, time.Now())
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		c.logger.Warn("Webhook with an invalid signature", zap.String("client_ip", ctx.ClientIP()))
+		response := helper.ErrorsResponse(http.StatusUnauthorized, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+	if err != nil || event.ID == "" || event.IntentID == "" {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "Invalid event", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	_, applied, err := c.paymentService.HandleEvent(ctx.Request.Context(), event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Order Not Found", "No order has this payment intent", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Receive Payment Event", dto.PaymentEventDTOResponse{EventID: event.ID, Applied: applied})
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/bookexport"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create ReportController interface for ReportController
+type ReportController interface {
+	BooksCreated(c *gin.Context)      // Count The Books Created Per Day Or Week
+	ActiveUsers(c *gin.Context)       // Count The Active Users Per Day Or Week
+	TopAuthors(c *gin.Context)        // Get The Authors With The Most Books
+	PriceDistribution(c *gin.Context) // Count The Books Per Price Range
+	BooksPerOwner(c *gin.Context)     // Get The Users Owning The Most Books
+}
+
+// Create reportController struct for ReportController interface with ReportService and Logger
+type reportController struct {
+	reportService services.ReportService // ReportService for the reports
+	logger        *zap.Logger            // Logger for structured logging
+}
+
+// Create New ReportController with ReportService and Logger dependency injection for ReportController interface
+func NewReportController(reportServ services.ReportService, logger *zap.Logger) ReportController {
+	return &reportController{reportService: reportServ, logger: logger}
+}
+
+// BooksCreated function for count the books created per day or week, the RequireRole middleware has already checked the admin role
+func (c *reportController) BooksCreated(ctx *gin.Context) {
+	c.periodReport(ctx, "books-created", c.reportService.BooksCreated)
+}
+
+// ActiveUsers function for count the users who added a book, reviewed, borrowed or bought per day or week
+func (c *reportController) ActiveUsers(ctx *gin.Context) {
+	c.periodReport(ctx, "active-users", c.reportService.ActiveUsers)
+}
+
+// TopAuthors function for get the authors with the most books
+func (c *reportController) TopAuthors(ctx *gin.Context) {
+	var query dto.ReportRankingDTORequest
+	if !c.bindQuery(ctx, &query) {
+		return
+	}
+	authors := c.reportService.TopAuthors(ctx.Request.Context(), query)
+	rows := make([][]interface{}, len(authors))
+	for i, a := range authors {
+		rows[i] = []interface{}{a.AuthorID, a.Name, a.Books, a.Reviews}
+	}
+	c.send(ctx, "top-authors", query.Format, authors, []string{"author_id", "name", "books", "reviews"}, rows)
+}
+
+// PriceDistribution function for count the books per price range of every currency
+func (c *reportController) PriceDistribution(ctx *gin.Context) {
+	var query dto.ReportPriceDTORequest
+	if !c.bindQuery(ctx, &query) {
+		return
+	}
+	buckets := c.reportService.PriceDistribution(ctx.Request.Context(), query)
+	rows := make([][]interface{}, len(buckets))
+	for i, b := range buckets {
+		rows[i] = []interface{}{b.From.Currency, b.From.Amount, b.To.Amount, b.Books}
+	}
+	c.send(ctx, "price-distribution", query.Format, buckets, []string{"currency", "from", "to", "books"}, rows)
+}
+
+// BooksPerOwner function for get the users owning the most books
+func (c *reportController) BooksPerOwner(ctx *gin.Context) {
+	var query dto.ReportRankingDTORequest
+	if !c.bindQuery(ctx, &query) {
+		return
+	}
+	owners := c.reportService.BooksPerOwner(ctx.Request.Context(), query)
+	rows := make([][]interface{}, len(owners))
+	for i, o := range owners {
+		rows[i] = []interface{}{o.UserID, o.Name, o.Email, o.Books}
+	}
+	c.send(ctx, "books-per-owner", query.Format, owners, []string{"user_id", "name", "email", "books"}, rows)
+}
+
+// periodReport answers the counts per day or week of the report
+func (c *reportController) periodReport(ctx *gin.Context, name string, report func(ctx context.Context, q dto.ReportPeriodDTORequest) ([]repository.PeriodCount, error)) {
+	var query dto.ReportPeriodDTORequest
+	if !c.bindQuery(ctx, &query) {
+		return
+	}
+	counts, err := report(ctx.Request.Context(), query)
+	if errors.Is(err, services.ErrInvalidRange) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	rows := make([][]interface{}, len(counts))
+	for i, p := range counts {
+		rows[i] = []interface{}{p.Start.Format(time.RFC3339), p.End.Format(time.RFC3339), p.Count}
+	}
+	c.send(ctx, name, query.Format, counts, []string{"start", "end", "count"}, rows)
+}
+
+// bindQuery binds the options of a report from the query string, abort with 400 if they are invalid
+func (c *reportController) bindQuery(ctx *gin.Context, query interface{}) bool {
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return false
+	}
+	return true
+}
+
+// send answers the report as JSON, or downloads its rows as a CSV file when the format is csv
+func (c *reportController) send(ctx *gin.Context, name string, format string, data interface{}, columns []string, rows [][]interface{}) {
+	if format != bookexport.FormatCSV {
+		response := helper.SuccessResponse(http.StatusOK, "Report", data)
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%s.csv", name, time.Now().UTC().Format("20060102"))
+	ctx.Header("Content-Type", bookexport.ContentType(bookexport.FormatCSV))
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Status(http.StatusOK)
+
+	writer, err := bookexport.NewWriter(bookexport.FormatCSV, ctx.Writer, columns)
+	for i := 0; err == nil && i < len(rows); i++ {
+		err = writer.WriteRow(rows[i])
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		logging.With(ctx.Request.Context(), c.logger).Error("Report download interrupted", zap.String("report", name), zap.Error(err))
+	}
+}
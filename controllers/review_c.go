@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create ReviewController interface for ReviewController
+type ReviewController interface {
+	GetAll(c *gin.Context)       // Get All Data Review Of A Book
+	CreateReview(c *gin.Context) // Create Data Review Of A Book
+	UpdateReview(c *gin.Context) // Update Data Review By Its Author
+	DeleteReview(c *gin.Context) // Delete Data Review By Its Author Or An Admin
+}
+
+// Create reviewController struct for ReviewController interface with ReviewService, BookService and Logger
+type reviewController struct {
+	reviewService services.ReviewService // ReviewService for CRUD Review
+	bookService   services.BookService   // BookService for the reviewed books
+	logger        *zap.Logger            // Logger for structured logging
+}
+
+// Create New ReviewController with ReviewService, BookService and Logger dependency injection for ReviewController interface
+func NewReviewController(reviewServ services.ReviewService, bookServ services.BookService, logger *zap.Logger) ReviewController {
+	return &reviewController{reviewService: reviewServ, bookService: bookServ, logger: logger}
+}
+
+// GetAll function for get all data review of the book, newest first
+func (c *reviewController) GetAll(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	var reviews []entity.Review = c.reviewService.GetByBook(ctx.Request.Context(), book.ID)
+
+	// Return success response with status code 200 and data reviews
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Review", reviews)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// CreateReview function for create data review, a user reviews a book once and never their own book
+func (c *reviewController) CreateReview(ctx *gin.Context) {
+
+	// Create reviewCreateDTO variable for binding data from request body
+	var reviewCreateDTO dto.ReviewCreateDTORequest
+
+	// Bind data from request body to reviewCreateDTO variable
+	errDTO := ctx.ShouldBind(&reviewCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
+
+	// Owners cannot rate their own books
+	if book.UserID == principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You cannot review your own book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Check if the user already reviewed the book
+	if c.reviewService.IsDuplicateReview(ctx.Request.Context(), book.ID, principal.UserID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You already reviewed this book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	// The review always belongs to the book of the url and the authenticated user
+	reviewCreateDTO.BookID = book.ID
+	reviewCreateDTO.UserID = principal.UserID
+
+	result, err := c.reviewService.CreateReview(ctx.Request.Context(), reviewCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Review", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateReview function for update data review by its author
+func (c *reviewController) UpdateReview(ctx *gin.Context) {
+
+	// Create reviewUpdateDTO variable for binding data from request body
+	var reviewUpdateDTO dto.ReviewUpdateDTORequest
+
+	// Bind data from request body to reviewUpdateDTO variable
+	errDTO := ctx.ShouldBind(&reviewUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	review, ok := c.review(ctx)
+	if !ok {
+		return
+	}
+
+	// Only the author of the review can change it
+	principal, _ := auth.FromContext(ctx)
+	if review.UserID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to change this review", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	reviewUpdateDTO.ID = review.ID // The review of the url, ignore the id of the body
+
+	result, err := c.reviewService.UpdateReview(ctx.Request.Context(), reviewUpdateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Review", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteReview function for delete data review by its author or an admin
+func (c *reviewController) DeleteReview(ctx *gin.Context) {
+	review, ok := c.review(ctx)
+	if !ok {
+		return
+	}
+
+	// Only the author of the review or an admin can delete it
+	principal, _ := auth.FromContext(ctx)
+	if review.UserID != principal.UserID && !principal.HasRole(entity.RoleAdmin) {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to delete this review", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	if err := c.reviewService.DeleteReview(ctx.Request.Context(), review); err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Review", review)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// book gets the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *reviewController) book(ctx *gin.Context) (entity.Book, bool) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Book{}, false
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), bookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Book{}, false
+	}
+	return book, true
+}
+
+// review gets the review of the :reviewId parameter that belongs to the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *reviewController) review(ctx *gin.Context) (entity.Review, bool) {
+	bookID, errBook := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	reviewID, errReview := strconv.ParseUint(ctx.Param("reviewId"), 10, 64)
+	if errBook != nil || errReview != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Review Not Found", "Invalid id", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Review{}, false
+	}
+
+	review := c.reviewService.GetByID(ctx.Request.Context(), reviewID)
+	if review.ID == 0 || review.BookID != bookID {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Review Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Review{}, false
+	}
+	return review, true
+}
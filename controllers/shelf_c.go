@@ -0,0 +1,372 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create ShelfController interface for ShelfController
+type ShelfController interface {
+	GetAll(c *gin.Context)          // Get All Data Shelf Of The User
+	GetByID(c *gin.Context)         // Get Data Shelf Of The User With Its Books
+	CreateShelf(c *gin.Context)     // Create Data Shelf
+	UpdateShelf(c *gin.Context)     // Update Name And Privacy Of Data Shelf
+	DeleteShelf(c *gin.Context)     // Delete Data Shelf
+	AddBook(c *gin.Context)         // Put A Book On Data Shelf
+	UpdateBook(c *gin.Context)      // Update Reading Progress Of A Book On Data Shelf
+	RemoveBook(c *gin.Context)      // Remove A Book From Data Shelf
+	GetPublicByUser(c *gin.Context) // Get All Public Data Shelf Of A User
+	GetPublicByID(c *gin.Context)   // Get Public Data Shelf With Its Books
+}
+
+// Create shelfController struct for ShelfController interface with ShelfService, BookService and Logger
+type shelfController struct {
+	shelfService services.ShelfService // ShelfService for CRUD Shelf
+	bookService  services.BookService  // BookService for the books put on shelves
+	logger       *zap.Logger           // Logger for structured logging
+}
+
+// Create New ShelfController with ShelfService, BookService and Logger dependency injection for ShelfController interface
+func NewShelfController(shelfServ services.ShelfService, bookServ services.BookService, logger *zap.Logger) ShelfController {
+	return &shelfController{shelfService: shelfServ, bookService: bookServ, logger: logger}
+}
+
+// GetAll function for get all data shelf of the authenticated user with their book count
+func (c *shelfController) GetAll(ctx *gin.Context) {
+	principal, _ := auth.FromContext(ctx)
+
+	var shelves []entity.Shelf = c.shelfService.GetByUser(ctx.Request.Context(), principal.UserID, false)
+
+	// Return success response with status code 200 and data shelves
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Shelf", shelves)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetByID function for get data shelf of the authenticated user with its books
+func (c *shelfController) GetByID(ctx *gin.Context) {
+	shelf, ok := c.ownShelf(ctx)
+	if !ok {
+		return
+	}
+
+	// Return success response with status code 200 and data shelf
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Shelf", shelf)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// CreateShelf function for create data shelf of the authenticated user
+func (c *shelfController) CreateShelf(ctx *gin.Context) {
+
+	// Create shelfCreateDTO variable for binding data from request body
+	var shelfCreateDTO dto.ShelfCreateDTORequest
+
+	// Bind data from request body to shelfCreateDTO variable
+	errDTO := ctx.ShouldBind(&shelfCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// The shelf always belongs to the authenticated user
+	principal, _ := auth.FromContext(ctx)
+	shelfCreateDTO.UserID = principal.UserID
+
+	// Check if the user already has a shelf with this name
+	if c.shelfService.IsDuplicateName(ctx.Request.Context(), principal.UserID, shelfCreateDTO.Name, 0) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You already have a shelf with this name", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	result := c.shelfService.CreateShelf(ctx.Request.Context(), shelfCreateDTO)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Create Data Shelf", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateShelf function for update name and privacy of data shelf of the authenticated user
+func (c *shelfController) UpdateShelf(ctx *gin.Context) {
+
+	// Create shelfUpdateDTO variable for binding data from request body
+	var shelfUpdateDTO dto.ShelfUpdateDTORequest
+
+	// Bind data from request body to shelfUpdateDTO variable
+	errDTO := ctx.ShouldBind(&shelfUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	shelf, ok := c.ownShelf(ctx)
+	if !ok {
+		return
+	}
+
+	// Check if the new name belongs to another shelf of the user
+	if c.shelfService.IsDuplicateName(ctx.Request.Context(), shelf.UserID, shelfUpdateDTO.Name, shelf.ID) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You already have a shelf with this name", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	shelfUpdateDTO.ID = shelf.ID // The shelf of the url, ignore the id of the body
+
+	result := c.shelfService.UpdateShelf(ctx.Request.Context(), shelfUpdateDTO)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Data Shelf", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteShelf function for delete data shelf of the authenticated user, the books are kept
+func (c *shelfController) DeleteShelf(ctx *gin.Context) {
+	shelf, ok := c.ownShelf(ctx)
+	if !ok {
+		return
+	}
+
+	c.shelfService.DeleteShelf(ctx.Request.Context(), shelf)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Delete Data Shelf", helper.EmptyObject{})
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// AddBook function for put a book on data shelf of the authenticated user
+func (c *shelfController) AddBook(ctx *gin.Context) {
+
+	// Create entryCreateDTO variable for binding data from request body
+	var entryCreateDTO dto.ShelfEntryCreateDTORequest
+
+	// Bind data from request body to entryCreateDTO variable
+	errDTO := ctx.ShouldBind(&entryCreateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !isValidReadingDates(ctx, entryCreateDTO.StartedAt, entryCreateDTO.FinishedAt) {
+		return
+	}
+
+	shelf, ok := c.ownShelf(ctx)
+	if !ok {
+		return
+	}
+
+	// Check the book exists
+	if c.bookService.GetByID(ctx.Request.Context(), entryCreateDTO.BookID).ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Check the book is not already on the shelf
+	if c.shelfService.GetEntry(ctx.Request.Context(), shelf.ID, entryCreateDTO.BookID).ID != 0 {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "The book is already on this shelf", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	result, err := c.shelfService.AddBook(ctx.Request.Context(), shelf.ID, entryCreateDTO)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Add Book To Shelf", result)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateBook function for update the reading progress and dates of a book on data shelf of the authenticated user
+func (c *shelfController) UpdateBook(ctx *gin.Context) {
+
+	// Create entryUpdateDTO variable for binding data from request body
+	var entryUpdateDTO dto.ShelfEntryUpdateDTORequest
+
+	// Bind data from request body to entryUpdateDTO variable
+	errDTO := ctx.ShouldBind(&entryUpdateDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !isValidReadingDates(ctx, entryUpdateDTO.StartedAt, entryUpdateDTO.FinishedAt) {
+		return
+	}
+
+	entry, ok := c.entry(ctx)
+	if !ok {
+		return
+	}
+
+	result := c.shelfService.UpdateEntry(ctx.Request.Context(), entry, entryUpdateDTO)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Book On Shelf", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RemoveBook function for remove a book from data shelf of the authenticated user
+func (c *shelfController) RemoveBook(ctx *gin.Context) {
+	entry, ok := c.entry(ctx)
+	if !ok {
+		return
+	}
+
+	c.shelfService.RemoveBook(ctx.Request.Context(), entry)
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Remove Book From Shelf", helper.EmptyObject{})
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetPublicByUser function for get all public data shelf of the user of the :id parameter
+func (c *shelfController) GetPublicByUser(ctx *gin.Context) {
+
+	// Get id from url parameter with key id
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "User Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var shelves []entity.Shelf = c.shelfService.GetByUser(ctx.Request.Context(), userID, true)
+
+	// Return success response with status code 200 and data shelves
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Shelf", shelves)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// GetPublicByID function for get public data shelf with its books, private shelves are not found
+func (c *shelfController) GetPublicByID(ctx *gin.Context) {
+	shelf, ok := c.shelf(ctx)
+	if !ok {
+		return
+	}
+
+	if shelf.Privacy != entity.ShelfPublic {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Shelf Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Return success response with status code 200 and data shelf
+	response := helper.SuccessResponse(http.StatusOK, "Get Data Shelf", shelf)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// shelf gets the shelf of the :id parameter, abort with 400 or 404 if there is none
+func (c *shelfController) shelf(ctx *gin.Context) (entity.Shelf, bool) {
+
+	// Get id from url parameter with key id
+	shelfID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Shelf Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Shelf{}, false
+	}
+
+	shelf := c.shelfService.GetByID(ctx.Request.Context(), shelfID)
+	if shelf.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Shelf Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Shelf{}, false
+	}
+	return shelf, true
+}
+
+// ownShelf gets the shelf of the :id parameter, abort with 403 if it does not belong to the authenticated user
+func (c *shelfController) ownShelf(ctx *gin.Context) (entity.Shelf, bool) {
+	shelf, ok := c.shelf(ctx)
+	if !ok {
+		return shelf, false
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	if shelf.UserID != principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to access this shelf", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return entity.Shelf{}, false
+	}
+	return shelf, true
+}
+
+// entry gets the entry of the :bookId parameter on the own shelf of the :id parameter, abort with 400, 403 or 404 if there is none
+func (c *shelfController) entry(ctx *gin.Context) (entity.ShelfEntry, bool) {
+	shelf, ok := c.ownShelf(ctx)
+	if !ok {
+		return entity.ShelfEntry{}, false
+	}
+
+	// Get book id from url parameter with key bookId
+	bookID, err := strconv.ParseUint(ctx.Param("bookId"), 10, 64)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.ShelfEntry{}, false
+	}
+
+	entry := c.shelfService.GetEntry(ctx.Request.Context(), shelf.ID, bookID)
+	if entry.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "The book is not on this shelf", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.ShelfEntry{}, false
+	}
+	return entry, true
+}
+
+// isValidReadingDates checks a book is not finished before it was started, abort with 400 if it is
+func isValidReadingDates(ctx *gin.Context, startedAt *time.Time, finishedAt *time.Time) bool {
+	if startedAt != nil && finishedAt != nil && finishedAt.Before(*startedAt) {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", "finished_at must not be before started_at", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+)
+
+// Create TagController interface for TagController
+type TagController interface {
+	Autocomplete(c *gin.Context) // Get The Most Used Tags Starting With ?q=
+}
+
+// Create tagController struct for TagController interface with TagService and Logger
+type tagController struct {
+	tagService services.TagService // TagService for searching tags
+	logger     *zap.Logger         // Logger for structured logging
+}
+
+// Create New TagController with TagService and Logger dependency injection for TagController interface
+func NewTagController(tagServ services.TagService, logger *zap.Logger) TagController {
+	return &tagController{tagService: tagServ, logger: logger}
+}
+
+// Autocomplete function for get the most used tags starting with the q query parameter, at most limit (default 10, max 50)
+func (c *tagController) Autocomplete(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid limit", "limit must be between 1 and 50", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var tags []entity.Tag = c.tagService.Autocomplete(ctx.Request.Context(), ctx.Query("q"), limit)
+
+	// Return success response with status code 200 and data tags
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Tag", tags)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
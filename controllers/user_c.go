@@ -2,13 +2,13 @@ package controllers
 
 import (
 	"net/http"
-	"strconv"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
 )
 
 // UserController is a struct for user controller
@@ -21,17 +21,17 @@ type UserController interface {
 type userController struct {
 	// userService is a new instance of UserService
 	userService services.UserService
-	// jwtService is a new instance of JWTService
-	jwtService services.JWTService
+	// logger is the structured logger
+	logger *zap.Logger
 }
 
 // NewUserController is a function for create new instance of UserController
-func NewUserController(userService services.UserService, jwtService services.JWTService) UserController {
+func NewUserController(userService services.UserService, logger *zap.Logger) UserController {
 	return &userController{
 		// userService is a new instance of UserService
 		userService: userService,
-		// jwtService is a new instance of JWTService
-		jwtService: jwtService,
+		// logger is the structured logger
+		logger: logger,
 	}
 }
 
@@ -51,35 +51,18 @@ func (c *userController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	// Get the token from the header of the request
-	authHeader := ctx.GetHeader("Authorization")
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
 
-	// Validate the token
-	token, errToken := c.jwtService.ValidateToken(authHeader)
+	userUpdateDTO.ID = principal.UserID // Users can only update their own profile
 
-	// Check if there is any error in validating token
-	if errToken != nil {
-		response := helper.ErrorsResponse(http.StatusUnauthorized, "Failed to process request", errToken.Error(), helper.EmptyObject{})
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	claims := token.Claims.(jwt.MapClaims) // Get the claims of the token
-
-	// Get the user id from the claims
-	userId, err := strconv.ParseUint(claims["user_id"].(string), 10, 64)
-
-	// Check if there is any error in parsing user id
+	user, err := c.userService.UpdateUser(ctx.Request.Context(), userUpdateDTO) // Update the user
 	if err != nil {
-		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", err.Error(), helper.EmptyObject{})
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	userUpdateDTO.ID = userId // Get the user from the database
-
-	user := c.userService.UpdateUser(userUpdateDTO) // Update the user
-
 	response := helper.SuccessResponse(http.StatusOK, "Update User Success", user) // Create the response for the user
 
 	ctx.JSON(http.StatusOK, response) // Return the response
@@ -88,34 +71,11 @@ func (c *userController) UpdateUser(ctx *gin.Context) {
 // GetUser is a function for get user
 func (c *userController) GetUser(ctx *gin.Context) {
 
-	// Get the token from the header of the request
-	authHeader := ctx.GetHeader("Authorization")
-
-	// Validate the token
-	token, errToken := c.jwtService.ValidateToken(authHeader)
-
-	// Check if there is any error in validating token
-	if errToken != nil {
-		response := helper.ErrorsResponse(http.StatusUnauthorized, "Failed to process request", errToken.Error(), helper.EmptyObject{})
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Get the claims of the token
-	claims := token.Claims.(jwt.MapClaims)
-
-	// Get the user id from the claims
-	userId, err := strconv.ParseUint(claims["user_id"].(string), 10, 64)
-
-	// Check if there is any error in parsing user id
-	if err != nil {
-		response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", err.Error(), helper.EmptyObject{})
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
-		return
-	}
+	// Get the authenticated user set by AuthorizeJWT
+	principal, _ := auth.FromContext(ctx)
 
 	// Get the user from the database with the user id
-	user := c.userService.GetUser(int64(userId))
+	user := c.userService.GetUser(ctx.Request.Context(), int64(principal.UserID))
 
 	// Create the response for the user
 	response := helper.SuccessResponse(http.StatusOK, "Get User Success", user)
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Create WaitlistController interface for WaitlistController
+type WaitlistController interface {
+	GetAll(c *gin.Context)          // Get The Waitlist Of A Book In Order
+	Join(c *gin.Context)            // Join The Waitlist Of A Book
+	Leave(c *gin.Context)           // Leave The Waitlist Of A Book
+	Accept(c *gin.Context)          // Take The Book Offered To The User
+	SetAvailability(c *gin.Context) // Mark The Book Available Or In Use By Its Owner
+}
+
+// Create waitlistController struct for WaitlistController interface with WaitlistService, BookService and Logger
+type waitlistController struct {
+	waitlistService services.WaitlistService // WaitlistService for the queues
+	bookService     services.BookService     // BookService for the awaited books
+	logger          *zap.Logger              // Logger for structured logging
+}
+
+// Create New WaitlistController with WaitlistService, BookService and Logger dependency injection for WaitlistController interface
+func NewWaitlistController(waitlistServ services.WaitlistService, bookServ services.BookService, logger *zap.Logger) WaitlistController {
+	return &waitlistController{waitlistService: waitlistServ, bookService: bookServ, logger: logger}
+}
+
+// GetAll function for get the waiting and offered entries of the book in order with their position
+func (c *waitlistController) GetAll(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	entries, err := c.waitlistService.GetQueue(ctx.Request.Context(), book.ID)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Return success response with status code 200 and data entries
+	response := helper.SuccessResponse(http.StatusOK, "Get All Data Waitlist", entries)
+
+	ctx.JSON(http.StatusOK, response) // Return Response
+}
+
+// Join function for add the authenticated user at the end of the waitlist, never for their own book and once at a time
+func (c *waitlistController) Join(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	// Owners do not wait for their own books
+	principal, _ := auth.FromContext(ctx)
+	if book.UserID == principal.UserID {
+		response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You cannot wait for your own book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	entry, err := c.waitlistService.Join(ctx.Request.Context(), book.ID, principal.UserID)
+	if errors.Is(err, repository.ErrAlreadyQueued) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "You are already in the waitlist of this book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusCreated, "Join Waitlist", entry)
+
+	// Return Response
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Leave function for remove the authenticated user from the waitlist, an offer they held moves on to the next person
+func (c *waitlistController) Leave(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	err := c.waitlistService.Leave(ctx.Request.Context(), book.ID, principal.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Failed to process request", "You are not in the waitlist of this book", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Leave Waitlist", helper.EmptyObject{})
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Accept function for take the book offered to the authenticated user before the offer expires
+func (c *waitlistController) Accept(ctx *gin.Context) {
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+	principal, _ := auth.FromContext(ctx)
+
+	entry, err := c.waitlistService.Accept(ctx.Request.Context(), book.ID, principal.UserID)
+	if errors.Is(err, repository.ErrNoOffer) {
+		response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Accept Waitlist Offer", entry)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// SetAvailability function for mark the book available or in use, the owner is checked by BookOwner
+func (c *waitlistController) SetAvailability(ctx *gin.Context) {
+
+	// Create availabilityDTO variable for binding data from request body
+	var availabilityDTO dto.BookAvailabilityDTORequest
+
+	// Bind data from request body to availabilityDTO variable
+	errDTO := ctx.ShouldBind(&availabilityDTO)
+
+	// Check error from ctx.ShouldBind
+	if errDTO != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Invalid data", errDTO.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	book, ok := c.book(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.waitlistService.SetAvailability(ctx.Request.Context(), book, availabilityDTO.Availability)
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusInternalServerError, "Failed to process request", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// response variable for return response with status code and message
+	response := helper.SuccessResponse(http.StatusOK, "Update Book Availability", result)
+
+	// Return Response
+	ctx.JSON(http.StatusOK, response)
+}
+
+// book gets the book of the :id parameter, abort with 400 or 404 if there is none
+func (c *waitlistController) book(ctx *gin.Context) (entity.Book, bool) {
+
+	// Get id from url parameter with key id
+	bookID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+
+	// Check error from strconv.ParseUint
+	if err != nil {
+		response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return entity.Book{}, false
+	}
+
+	book := c.bookService.GetByID(ctx.Request.Context(), bookID)
+	if book.ID == 0 {
+		response := helper.ErrorsResponse(http.StatusNotFound, "Book Not Found", "", helper.EmptyObject{})
+		ctx.AbortWithStatusJSON(http.StatusNotFound, response)
+		return entity.Book{}, false
+	}
+	return book, true
+}
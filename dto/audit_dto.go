@@ -0,0 +1,14 @@
+package dto
+
+// Create Audit Filter DTO Request when an admin searches the audit log
+type AuditFilterDTORequest struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=book user"`
+	EntityID   uint64 `form:"entity_id"`
+	ActorID    uint64 `form:"actor_id"`
+	Action     string `form:"action" binding:"omitempty,oneof=create update delete revert"`
+	RequestID  string `form:"request_id" binding:"max=64"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // changes at or after the time
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`   // changes before the time
+	BeforeID   uint64 `form:"before_id"`                                                   // id of the last entry of the previous page
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=200"`                     // 50 when empty
+}
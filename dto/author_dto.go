@@ -0,0 +1,14 @@
+package dto
+
+// Create Author Create DTO Request when user create author
+type AuthorCreateDTORequest struct {
+	Name string `json:"name" form:"name" binding:"required,max=255"`
+	Bio  string `json:"bio" form:"bio"`
+}
+
+// Create Author Update DTO Request when user update author
+type AuthorUpdateDTORequest struct {
+	ID   uint64 `json:"id" form:"id"`
+	Name string `json:"name" form:"name" binding:"required,max=255"`
+	Bio  string `json:"bio" form:"bio"`
+}
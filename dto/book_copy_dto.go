@@ -0,0 +1,24 @@
+package dto
+
+// Create Book Copy Create DTO Request when the owner adds a physical copy of a book
+type BookCopyCreateDTORequest struct {
+	Barcode   string `json:"barcode" form:"barcode" binding:"required,max=64"`
+	Condition string `json:"condition" form:"condition" binding:"required,oneof=new good fair poor"`
+	Location  string `json:"location" form:"location" binding:"max=255"`
+	Status    string `json:"status" form:"status" binding:"omitempty,oneof=available in_use lost repair"` // available when empty
+	BookID    uint64 `json:"book_id,omitempty" form:"book_id,omitempty"`
+}
+
+// Create Book Copy Update DTO Request when the owner changes a physical copy of a book
+type BookCopyUpdateDTORequest struct {
+	ID        uint64 `json:"id" form:"id"`
+	Barcode   string `json:"barcode" form:"barcode" binding:"required,max=64"`
+	Condition string `json:"condition" form:"condition" binding:"required,oneof=new good fair poor"`
+	Location  string `json:"location" form:"location" binding:"max=255"`
+	Status    string `json:"status" form:"status" binding:"required,oneof=available in_use lost repair"`
+}
+
+// Create Book Copy Filter DTO Request when user lists the copies of a book
+type BookCopyFilterDTORequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=available in_use lost repair"`
+}
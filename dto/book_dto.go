@@ -1,20 +1,50 @@
 package dto
 
+import "github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+
 // Create Book Update DTO Request when user update book
 type BookUpdateDTORequest struct {
-	ID          uint64 `json:"id" form:"id"`
-	Title       string `json:"title" form:"title" binding:"required"`
-	Author      string `json:"author" form:"author" binding:"required"`
-	Price       int64  `json:"price" form:"price" binding:"required"`
-	Description string `json:"description" form:"description" binding:"required"`
-	UserID      uint64 `json:"user_id,omnitempty" form:"user_id,omitempty"`
+	ID          uint64      `json:"id" form:"id"`
+	Title       string      `json:"title" form:"title" binding:"required"`
+	Author      string      `json:"author" form:"author" binding:"required_without=AuthorIDs"`
+	AuthorIDs   []uint64    `json:"author_ids" form:"author_ids" binding:"omitempty,max=20"`
+	Price       money.Money `json:"price" form:"price"`
+	Description string      `json:"description" form:"description" binding:"required"`
+	ISBN        string      `json:"isbn" form:"isbn" binding:"omitempty,book_isbn"`
+	CategoryIDs []uint64    `json:"category_ids" form:"category_ids" binding:"omitempty,max=20"`
+	TagNames    []string    `json:"tags" form:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	Visibility  string      `json:"visibility" form:"visibility" binding:"omitempty,oneof=private unlisted public"` // unchanged when empty, only the owner changes it
+	UserID      uint64      `json:"user_id,omnitempty" form:"user_id,omitempty"`
 }
 
 // Create Book Create DTO Request when user create book
 type BookCreateDTORequest struct {
-	Title       string `json:"title" form:"title" binding:"required"`
-	Author      string `json:"author" form:"author" binding:"required"`
-	Price       int64  `json:"price" form:"price" binding:"required"`
-	Description string `json:"description" form:"description" binding:"required"`
-	UserID      uint64 `json:"user_id,omnitempty" form:"user_id,omitempty"`
+	Title       string      `json:"title" form:"title" binding:"required"`
+	Author      string      `json:"author" form:"author" binding:"required_without=AuthorIDs"`
+	AuthorIDs   []uint64    `json:"author_ids" form:"author_ids" binding:"omitempty,max=20"`
+	Price       money.Money `json:"price" form:"price"`
+	Description string      `json:"description" form:"description" binding:"required"`
+	ISBN        string      `json:"isbn" form:"isbn" binding:"omitempty,book_isbn"`
+	CategoryIDs []uint64    `json:"category_ids" form:"category_ids" binding:"omitempty,max=20"`
+	TagNames    []string    `json:"tags" form:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	Visibility  string      `json:"visibility" form:"visibility" binding:"omitempty,oneof=private unlisted public"` // public when empty
+	UserID      uint64      `json:"user_id,omnitempty" form:"user_id,omitempty"`
+}
+
+// Create Book Filter DTO Request when user filter the public book list
+type BookFilterDTORequest struct {
+	Category string `form:"category"`                                      // id or slug of a category
+	Tag      string `form:"tag"`                                           // name of a tag
+	Sort     string `form:"sort" binding:"omitempty,oneof=rating reviews"` // rating (best rated first) or reviews (most reviewed first)
+}
+
+// Create Book Export DTO Request when user exports books
+type BookExportDTORequest struct {
+	Format   string `form:"format" binding:"omitempty,oneof=csv ndjson xlsx"` // csv when empty
+	Author   string `form:"author" binding:"max=255"`                         // part of the author names
+	Title    string `form:"title" binding:"max=255"`                          // part of the title
+	MinPrice *int64 `form:"min_price" binding:"omitempty,min=0"`              // lowest price in minor units, inclusive
+	MaxPrice *int64 `form:"max_price" binding:"omitempty,min=0"`              // highest price in minor units, inclusive
+	Currency string `form:"currency" binding:"omitempty,currency"`            // currency of the prices, all currencies when empty
+	UserID   uint64 `form:"-"`                                                // owner of the books, all owners when 0
 }
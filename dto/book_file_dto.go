@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ebook"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+)
+
+// Create Book File Upload DTO Response with the stored file and, for an EPUB, its metadata and the book filled from it
+type BookFileUploadDTOResponse struct {
+	File     entity.BookFile       `json:"file"`
+	Metadata *ebook.Metadata       `json:"metadata,omitempty"`
+	Book     *BookUpdateDTORequest `json:"book,omitempty"` // the current book with the metadata applied, ready to be sent as update
+}
+
+// Create Book File Download DTO Response with the signed URL of the file
+type BookFileDownloadDTOResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
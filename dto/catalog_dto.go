@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/catalog"
+
+// Create Book From ISBN DTO Request when user asks to pre-fill a book from the catalog
+type BookFromISBNDTORequest struct {
+	ISBN string `json:"isbn" form:"isbn" binding:"required,book_isbn"`
+}
+
+// Create Book From ISBN DTO Response with the pre-filled book and the full catalog metadata
+type BookFromISBNDTOResponse struct {
+	Book     BookCreateDTORequest `json:"book"`
+	Metadata catalog.BookMetadata `json:"metadata"`
+}
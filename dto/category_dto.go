@@ -0,0 +1,20 @@
+package dto
+
+// Create Category Create DTO Request when admin create category
+type CategoryCreateDTORequest struct {
+	Name     string  `json:"name" form:"name" binding:"required,max=100"`
+	Slug     string  `json:"slug" form:"slug" binding:"omitempty,max=100"`
+	ParentID *uint64 `json:"parent_id" form:"parent_id"`
+}
+
+// Create Category Update DTO Request when admin update category
+type CategoryUpdateDTORequest struct {
+	ID   uint64 `json:"id" form:"id"`
+	Name string `json:"name" form:"name" binding:"required,max=100"`
+	Slug string `json:"slug" form:"slug" binding:"omitempty,max=100"`
+}
+
+// Create Category Move DTO Request when admin move category below another parent
+type CategoryMoveDTORequest struct {
+	ParentID *uint64 `json:"parent_id" form:"parent_id"` // null moves the category to the root
+}
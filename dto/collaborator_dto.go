@@ -0,0 +1,14 @@
+package dto
+
+// Create Collaborator Invite DTO Request when the owner invites a user to a book
+type CollaboratorInviteDTORequest struct {
+	Email     string `json:"email" form:"email" binding:"required,email"`
+	Role      string `json:"role" form:"role" binding:"required,oneof=viewer editor"`
+	BookID    uint64 `json:"book_id,omitempty" form:"book_id,omitempty"`
+	InviterID uint64 `json:"inviter_id,omitempty" form:"inviter_id,omitempty"`
+}
+
+// Create Book Transfer DTO Request when the owner gives a book to a collaborator
+type BookTransferDTORequest struct {
+	UserID uint64 `json:"user_id" form:"user_id" binding:"required"`
+}
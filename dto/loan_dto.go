@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// Create Loan Create DTO Request when user asks to borrow a book
+type LoanCreateDTORequest struct {
+	BookID     uint64     `json:"book_id" form:"book_id" binding:"required"`
+	DueAt      *time.Time `json:"due_at" form:"due_at"` // proposed return date, the lender decides
+	Note       string     `json:"note" form:"note" binding:"max=255"`
+	BorrowerID uint64     `json:"borrower_id,omitempty" form:"borrower_id,omitempty"`
+}
+
+// Create Loan Approve DTO Request when the lender lends the book
+type LoanApproveDTORequest struct {
+	DueAt *time.Time `json:"due_at" form:"due_at"` // return date, the one proposed by the borrower when empty
+}
+
+// Create Loan Filter DTO Request when user lists their loans
+type LoanFilterDTORequest struct {
+	Status  string `form:"status" binding:"omitempty,oneof=requested rejected cancelled active returned"`
+	Overdue bool   `form:"overdue"` // only the active loans past their due date
+}
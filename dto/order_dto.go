@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+)
+
+// Create Cart Item Create DTO Request when user adds a book to their cart
+type CartItemCreateDTORequest struct {
+	BookID uint64 `json:"book_id" form:"book_id" binding:"required"`
+	UserID uint64 `json:"user_id,omitempty" form:"user_id,omitempty"`
+}
+
+// Create Cart DTO Response with the items of the cart and the sums of their current prices, one per currency
+type CartDTOResponse struct {
+	Items  []entity.CartItem `json:"items"`
+	Totals []money.Money     `json:"totals"`
+}
+
+// Create Order Status DTO Request when the buyer or the seller moves an order on
+type OrderStatusDTORequest struct {
+	Status string `json:"status" form:"status" binding:"required,oneof=paid shipped cancelled"`
+}
+
+// Create Order Filter DTO Request when user lists their orders
+type OrderFilterDTORequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending paid shipped cancelled"`
+}
+
+// Create Purchase DTO Response with the order of the purchased book and its payment
+type PurchaseDTOResponse struct {
+	Order   entity.Order    `json:"order"`
+	Payment *payment.Intent `json:"payment"` // null for a free book
+}
+
+// Create Payment Event DTO Response acknowledging an event of the payment gateway
+type PaymentEventDTOResponse struct {
+	EventID string `json:"event_id"`
+	Applied bool   `json:"applied"` // false when the event was already applied before
+}
@@ -0,0 +1,22 @@
+package dto
+
+// Create Report Period DTO Request when an admin counts something per day or week
+type ReportPeriodDTORequest struct {
+	Period string `form:"period" binding:"omitempty,oneof=day week"`    // day when empty
+	From   string `form:"from" binding:"omitempty,datetime=2006-01-02"` // first day, 30 days or 12 weeks before to when empty
+	To     string `form:"to" binding:"omitempty,datetime=2006-01-02"`   // last day, today when empty
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`    // json when empty
+}
+
+// Create Report Ranking DTO Request when an admin lists the first rows of a ranking
+type ReportRankingDTORequest struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"` // 10 when empty
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// Create Report Price DTO Request when an admin counts the books per price range
+type ReportPriceDTORequest struct {
+	Currency string `form:"currency" binding:"omitempty,currency"` // all currencies when empty
+	Width    int64  `form:"width" binding:"omitempty,min=1"`       // width of a range in minor units, 1000 when empty
+	Format   string `form:"format" binding:"omitempty,oneof=json csv"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// Create Review Create DTO Request when user review a book
+type ReviewCreateDTORequest struct {
+	Rating uint8  `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" form:"text" binding:"max=2000"`
+	BookID uint64 `json:"book_id,omitempty" form:"book_id,omitempty"`
+	UserID uint64 `json:"user_id,omitempty" form:"user_id,omitempty"`
+}
+
+// Create Review Update DTO Request when user update their review
+type ReviewUpdateDTORequest struct {
+	ID     uint64 `json:"id" form:"id"`
+	Rating uint8  `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" form:"text" binding:"max=2000"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// Create Shelf Create DTO Request when user create shelf
+type ShelfCreateDTORequest struct {
+	Name    string `json:"name" form:"name" binding:"required,max=100"`
+	Privacy string `json:"privacy" form:"privacy" binding:"omitempty,oneof=private public"`
+	UserID  uint64 `json:"user_id,omitempty" form:"user_id,omitempty"`
+}
+
+// Create Shelf Update DTO Request when user update shelf
+type ShelfUpdateDTORequest struct {
+	ID      uint64 `json:"id" form:"id"`
+	Name    string `json:"name" form:"name" binding:"required,max=100"`
+	Privacy string `json:"privacy" form:"privacy" binding:"required,oneof=private public"`
+}
+
+// Create Shelf Entry Create DTO Request when user put a book on a shelf
+type ShelfEntryCreateDTORequest struct {
+	BookID     uint64     `json:"book_id" form:"book_id" binding:"required"`
+	Progress   uint8      `json:"progress" form:"progress" binding:"max=100"`
+	StartedAt  *time.Time `json:"started_at" form:"started_at"`
+	FinishedAt *time.Time `json:"finished_at" form:"finished_at"`
+}
+
+// Create Shelf Entry Update DTO Request when user update the reading progress of a book
+type ShelfEntryUpdateDTORequest struct {
+	Progress   uint8      `json:"progress" form:"progress" binding:"max=100"`
+	StartedAt  *time.Time `json:"started_at" form:"started_at"`
+	FinishedAt *time.Time `json:"finished_at" form:"finished_at"`
+}
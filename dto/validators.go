@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+)
+
+// RegisterValidators registers the custom validation tags used by the DTOs on the gin validator
+func RegisterValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// book_isbn accepts an ISBN-10 or ISBN-13 (with or without hyphens) with a valid check digit
+	v.RegisterValidation("book_isbn", func(fl validator.FieldLevel) bool {
+		return helper.IsValidISBN(fl.Field().String())
+	})
+
+	// currency accepts an upper case ISO 4217 currency code
+	v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.IsValidCurrency(fl.Field().String())
+	})
+}
@@ -0,0 +1,6 @@
+package dto
+
+// Create Book Availability DTO Request when the owner marks the book available or in use
+type BookAvailabilityDTORequest struct {
+	Availability string `json:"availability" form:"availability" binding:"required,oneof=available in_use"`
+}
@@ -0,0 +1,168 @@
+package ebook
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// Formats of the supported e-book files
+const (
+	FormatEPUB = "epub"
+	FormatPDF  = "pdf"
+)
+
+// ErrUnsupportedFormat is returned when the file is neither an EPUB nor a PDF
+var ErrUnsupportedFormat = errors.New("file must be an EPUB or a PDF")
+
+// ErrInvalidEPUB is returned when an EPUB has no readable package document
+var ErrInvalidEPUB = errors.New("invalid EPUB file")
+
+// maxXMLSize limits the container and package documents read from an EPUB
+const maxXMLSize = 1 << 20
+
+// Metadata is the Dublin Core metadata of the package document (OPF) of an EPUB
+type Metadata struct {
+	Title      string   `json:"title"`
+	Creators   []string `json:"creators"`
+	Language   string   `json:"language"`
+	Identifier string   `json:"identifier"`
+}
+
+// DetectFormat returns the format of the file from its first bytes, or ErrUnsupportedFormat
+func DetectFormat(r io.ReaderAt, size int64) (string, error) {
+	head := make([]byte, 64)
+	n, _ := r.ReadAt(head, 0)
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return FormatPDF, nil
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		// An EPUB is a ZIP archive whose "mimetype" entry holds application/epub+zip
+		archive, err := zip.NewReader(r, size)
+		if err != nil {
+			return "", ErrUnsupportedFormat
+		}
+		for _, f := range archive.File {
+			if f.Name == "mimetype" {
+				data, err := readEntry(f)
+				if err == nil && strings.TrimSpace(string(data)) == "application/epub+zip" {
+					return FormatEPUB, nil
+				}
+				break
+			}
+		}
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// container is the part of META-INF/container.xml locating the package document
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// opfPackage is the part of the package document holding the metadata
+type opfPackage struct {
+	UniqueIdentifier string `xml:"unique-identifier,attr"`
+	Metadata         struct {
+		Titles      []string `xml:"title"`
+		Creators    []string `xml:"creator"`
+		Languages   []string `xml:"language"`
+		Identifiers []struct {
+			ID    string `xml:"id,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"identifier"`
+	} `xml:"metadata"`
+}
+
+// ParseEPUB reads the title, creators, language and identifier from the package document of the EPUB
+func ParseEPUB(r io.ReaderAt, size int64) (Metadata, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return Metadata{}, ErrInvalidEPUB
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	// The container names the package document
+	containerFile, ok := files["META-INF/container.xml"]
+	if !ok {
+		return Metadata{}, ErrInvalidEPUB
+	}
+	var c container
+	if err := decodeEntry(containerFile, &c); err != nil {
+		return Metadata{}, ErrInvalidEPUB
+	}
+	var opfPath string
+	for _, rootfile := range c.Rootfiles {
+		if rootfile.MediaType == "" || rootfile.MediaType == "application/oebps-package+xml" {
+			opfPath = path.Clean(rootfile.FullPath)
+			break
+		}
+	}
+	opfFile, ok := files[opfPath]
+	if !ok {
+		return Metadata{}, ErrInvalidEPUB
+	}
+	var pkg opfPackage
+	if err := decodeEntry(opfFile, &pkg); err != nil {
+		return Metadata{}, ErrInvalidEPUB
+	}
+
+	metadata := Metadata{Title: first(pkg.Metadata.Titles), Language: first(pkg.Metadata.Languages)}
+	for _, creator := range pkg.Metadata.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			metadata.Creators = append(metadata.Creators, creator)
+		}
+	}
+
+	// Prefer the identifier referenced as unique identifier of the package
+	for _, id := range pkg.Metadata.Identifiers {
+		if metadata.Identifier == "" || id.ID == pkg.UniqueIdentifier {
+			metadata.Identifier = strings.TrimSpace(id.Value)
+		}
+	}
+	return metadata, nil
+}
+
+// decodeEntry decodes the XML entry of the archive into v
+func decodeEntry(f *zip.File, v interface{}) error {
+	data, err := readEntry(f)
+	if err != nil {
+		return err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity // package documents written as XHTML may use named entities
+	return decoder.Decode(v)
+}
+
+// readEntry reads the entry of the archive, at most maxXMLSize bytes
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, maxXMLSize))
+}
+
+// first returns the first non-empty trimmed value
+func first(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Audited records
+const (
+	AuditBook = "book"
+	AuditUser = "user"
+)
+
+// Actions of the audit log
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditRevert = "revert" // a book was put back to an earlier version
+)
+
+// AuditRedacted replaces the values that must never be written to the audit log
+const AuditRedacted = "[REDACTED]"
+
+// Create AuditEntry struct representing the audit_entries table in the database, entries are only ever inserted
+type AuditEntry struct {
+	ID         uint64        `gorm:"primary_key;auto_increment" json:"id"`                                // Primary key, auto-increment id with json tag id for json marshalling
+	EntityType string        `gorm:"type:varchar(20);not null;index:idx_audit_entity" json:"entity_type"` // book or user
+	EntityID   uint64        `gorm:"not null;index:idx_audit_entity" json:"entity_id"`                    // Changed record, kept after it is deleted
+	Action     string        `gorm:"type:varchar(20);not null;index" json:"action"`                       // create, update, delete or revert
+	ActorID    *uint64       `gorm:"index" json:"actor_id"`                                               // Authenticated user of the change, null when there was none
+	RequestID  string        `gorm:"type:varchar(64);index" json:"request_id"`                            // Request of the change, to find it in the logs
+	RevertedID *uint64       `json:"reverted_id,omitempty"`                                               // Entry whose version a revert put back
+	Changes    AuditChanges  `gorm:"type:text" json:"changes"`                                            // Fields changed with their old and new values
+	Snapshot   AuditSnapshot `gorm:"type:text" json:"snapshot"`                                           // Fields after the change, before it for a delete
+	CreatedAt  time.Time     `gorm:"index" json:"created_at"`                                             // Time of the change
+}
+
+// AuditChange is the value of a field before and after a change, null when the record did not exist
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditChanges are the changed fields of an audit entry by name, stored as JSON
+type AuditChanges map[string]AuditChange
+
+// AuditSnapshot are the audited fields of a record by name, stored as JSON
+type AuditSnapshot map[string]interface{}
+
+// Value stores the changes as JSON
+func (c AuditChanges) Value() (driver.Value, error) {
+	return marshalAudit(c)
+}
+
+// Scan reads the changes from JSON
+func (c *AuditChanges) Scan(value interface{}) error {
+	return unmarshalAudit(value, c)
+}
+
+// Value stores the snapshot as JSON
+func (s AuditSnapshot) Value() (driver.Value, error) {
+	return marshalAudit(s)
+}
+
+// Scan reads the snapshot from JSON
+func (s *AuditSnapshot) Scan(value interface{}) error {
+	return unmarshalAudit(value, s)
+}
+
+// marshalAudit returns the JSON text of the value
+func marshalAudit(v interface{}) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// unmarshalAudit reads the JSON text or bytes of the database into v
+func unmarshalAudit(value interface{}, v interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	}
+	return errors.New("audit values must be stored as JSON text")
+}
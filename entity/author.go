@@ -0,0 +1,10 @@
+package entity
+
+// Create Author struct representing the author table in the database
+type Author struct {
+	ID      uint64  `gorm:"primary_key;auto_increment" json:"id"`            // Primary key, auto-increment id with json tag id for json marshalling
+	Name    string  `gorm:"type:varchar(255);not null" json:"name"`          // Display name of the author
+	NameKey string  `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"` // Normalized name used to find duplicates ("J.K. Rowling" and "JK Rowling")
+	Bio     string  `gorm:"type:text" json:"bio"`                            // Short biography of the author
+	Books   *[]Book `gorm:"many2many:book_authors" json:"books,omitempty"`   // Books written by the author
+}
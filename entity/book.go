@@ -1,13 +1,77 @@
 package entity
 
+import (
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+)
+
+// Availability of a book, set by its owner
+const (
+	AvailabilityAvailable = "available" // the book can be handed to the next person of the waitlist
+	AvailabilityInUse     = "in_use"    // the book is being read
+)
+
+// Visibility of a book, set by its owner
+const (
+	VisibilityPrivate  = "private"  // only the owner sees the book
+	VisibilityUnlisted = "unlisted" // anyone with the id or the share link sees the book, it is not listed
+	VisibilityPublic   = "public"   // the book is listed
+)
+
 // Create Book struct representing the book table in the database
 type Book struct {
-	ID          uint64 `gorm:"primary_key;auto_increment" json:"id"` // Primary key, auto-increment id with json tag id for json marshalling
-	Title       string `gorm:"type:varchar(255)" json:"title"`       // Data type varchar with json tag name for json marshalling
-	Author      string `gorm:"type:varchar(255)" json:"author"`      // Data type varchar with json tag name for json marshalling
-	Price       int64  `gorm:"type:int(11)" json:"price"`            // Data type varchar with json tag name for json marshalling
-	Description string `gorm:"type:varchar(255)" json:"description"` // Data type varchar with json tag name for json marshalling
-	UserID      uint64 `gorm:"not_null" json:"-"`
+	ID          uint64  `gorm:"primary_key;auto_increment" json:"id"`                         // Primary key, auto-increment id with json tag id for json marshalling
+	Title       string  `gorm:"type:varchar(255)" json:"title"`                               // Data type varchar with json tag name for json marshalling
+	Author      string  `gorm:"type:varchar(255)" json:"author"`                              // Data type varchar with json tag name for json marshalling
+	Description string  `gorm:"type:varchar(255)" json:"description"`                         // Data type varchar with json tag name for json marshalling
+	ISBN        *string `gorm:"type:varchar(13);uniqueIndex:idx_books_user_isbn" json:"isbn"` // Normalized ISBN-13, unique per owner, null when unknown
+	UserID      uint64  `gorm:"not_null;uniqueIndex:idx_books_user_isbn" json:"-"`
+	// Price in the minor unit of its currency, zero for a free book
+	Price money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
 	// Create a foreign key to user table with json tag user for json marshalling
 	User *User `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user"`
+	// Authors of the book, Author above keeps their names for display
+	Authors []Author `gorm:"many2many:book_authors" json:"authors"`
+	// Categories of the book, managed by the administrators
+	Categories []Category `gorm:"many2many:book_categories" json:"categories"`
+	// Tags of the book, chosen by the owner
+	Tags []Tag `gorm:"many2many:book_tags" json:"tags"`
+
+	// Average stars and number of reviews, maintained by the review repository only
+	AverageRating float64 `gorm:"type:decimal(3,2);not null;default:0;<-:false" json:"average_rating"`
+	ReviewCount   int64   `gorm:"not null;default:0;<-:false" json:"review_count"`
+
+	// Cover image of the book, maintained by the cover upload only
+	Cover BookCover `gorm:"embedded;embeddedPrefix:cover_" json:"cover"`
+
+	// Loan the book is lent out with, null when it is with its owner, maintained by the loan repository only
+	ActiveLoanID *uint64 `gorm:"index;<-:false" json:"active_loan_id"`
+
+	// Available or in use, set by the owner through the waitlist repository only
+	Availability string `gorm:"type:varchar(16);not null;default:available;<-:false" json:"availability"`
+
+	// Order the book is sold with, null while it is for sale, maintained by the order repository only
+	OrderID *uint64 `gorm:"index;<-:false" json:"order_id"`
+
+	// Number of physical copies by status, maintained by the copy repository only
+	Copies BookCopyCounts `gorm:"embedded;embeddedPrefix:copies_" json:"copies"`
+
+	// Time the book was created, null for the books created before it was recorded
+	CreatedAt *time.Time `gorm:"<-:create;index" json:"created_at"`
+
+	// Private, unlisted or public, the books created before visibility existed are public
+	Visibility string `gorm:"type:varchar(16);not null;default:public;index" json:"visibility"`
+	// Token of the share link of an unlisted book, null for the other books
+	ShareToken *string `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	// Token of the share link in the responses, only filled for the owner of the book
+	OwnerShareToken *string `gorm:"-" json:"share_token,omitempty"`
+}
+
+// BookCover holds the stored cover image of a book and the URLs of its variants, all empty when there is no cover
+type BookCover struct {
+	Key          string `gorm:"type:varchar(255);<-:false" json:"-"`                       // Storage key of the original image
+	URL          string `gorm:"type:varchar(512);<-:false" json:"url,omitempty"`           // Original image
+	MediumURL    string `gorm:"type:varchar(512);<-:false" json:"medium_url,omitempty"`    // Image scaled to 600 pixels
+	ThumbnailURL string `gorm:"type:varchar(512);<-:false" json:"thumbnail_url,omitempty"` // Image scaled to 150 pixels
 }
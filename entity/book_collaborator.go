@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Roles of a collaborator on a book, the owner keeps every right
+const (
+	CollaboratorOwner  = "owner"  // role reported for the owner of the book, never stored
+	CollaboratorViewer = "viewer" // sees the book whatever its visibility
+	CollaboratorEditor = "editor" // also edits the details, the cover and the history of the book
+)
+
+// Statuses of a collaborator, only accepted collaborators have their role
+const (
+	CollaboratorInvited  = "invited"  // invited by the owner, waiting for an answer
+	CollaboratorAccepted = "accepted" // accepted the invitation
+	CollaboratorDeclined = "declined" // declined the invitation, the owner can invite them again
+)
+
+// Create BookCollaborator struct representing the book_collaborators table in the database, a user shares a book with the owner
+type BookCollaborator struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`                                       // Primary key, auto-increment id with json tag id for json marshalling
+	BookID    uint64    `gorm:"not null;uniqueIndex:idx_book_collaborators_book_user" json:"book_id"`       // Shared book
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_book_collaborators_book_user;index" json:"user_id"` // Invited user
+	Role      string    `gorm:"type:varchar(16);not null" json:"role"`                                      // viewer or editor
+	Status    string    `gorm:"type:varchar(16);not null" json:"status"`                                    // invited, accepted or declined
+	InviterID uint64    `gorm:"not null" json:"inviter_id"`                                                 // Owner who sent the invitation
+	CreatedAt time.Time `json:"created_at"`                                                                 // Time of the invitation
+	UpdatedAt time.Time `json:"updated_at"`                                                                 // Time of the last answer or role change
+	User      *User     `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
+	Book      *Book     `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"book,omitempty"`
+}
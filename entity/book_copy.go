@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Statuses of a physical copy
+const (
+	CopyAvailable = "available" // on its shelf, can be handed out
+	CopyInUse     = "in_use"    // being read
+	CopyLost      = "lost"      // cannot be found
+	CopyRepair    = "repair"    // away for repair
+)
+
+// Create BookCopy struct representing the book_copies table in the database, one row per physical copy of a book
+type BookCopy struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`                            // Primary key, auto-increment id with json tag id for json marshalling
+	BookID    uint64    `gorm:"not null;index" json:"book_id"`                                   // Book the copy is a copy of
+	Barcode   string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"barcode"`            // Label of the copy, unique across the library
+	Condition string    `gorm:"type:varchar(16);not null" json:"condition"`                      // new, good, fair or poor
+	Location  string    `gorm:"type:varchar(255)" json:"location"`                               // Where the copy is kept, free text
+	Status    string    `gorm:"type:varchar(16);not null;default:available;index" json:"status"` // available, in_use, lost or repair
+	CreatedAt time.Time `json:"created_at"`                                                      // Time the copy was added
+	UpdatedAt time.Time `json:"updated_at"`                                                      // Time the copy was last changed
+	Book      *Book     `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// BookCopyCounts holds the number of copies of a book by status, all zero when the book has no copies
+type BookCopyCounts struct {
+	Total     int64 `gorm:"not null;default:0;<-:false" json:"total"`     // All copies
+	Available int64 `gorm:"not null;default:0;<-:false" json:"available"` // Copies that can be handed out
+	InUse     int64 `gorm:"not null;default:0;<-:false" json:"in_use"`    // Copies being read
+	Lost      int64 `gorm:"not null;default:0;<-:false" json:"lost"`      // Copies that cannot be found
+	Repair    int64 `gorm:"not null;default:0;<-:false" json:"repair"`    // Copies away for repair
+}
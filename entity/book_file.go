@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Create BookFile struct representing the book_files table in the database, a book has at most one file per format
+type BookFile struct {
+	ID          uint64    `gorm:"primary_key;auto_increment" json:"id"`                                           // Primary key, auto-increment id with json tag id for json marshalling
+	BookID      uint64    `gorm:"not null;uniqueIndex:idx_book_files_book_format" json:"book_id"`                 // Book of the file
+	Format      string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_book_files_book_format" json:"format"` // epub or pdf
+	FileName    string    `gorm:"type:varchar(255);not null" json:"file_name"`                                    // Name the file is downloaded as
+	ContentType string    `gorm:"type:varchar(100);not null" json:"content_type"`                                 // Sniffed content type
+	Size        int64     `gorm:"not null" json:"size"`                                                           // Size in bytes
+	Key         string    `gorm:"type:varchar(255);not null" json:"-"`                                            // Private key of the file in the blob store
+	CreatedAt   time.Time `json:"created_at"`                                                                     // Time the file was uploaded
+	Book        *Book     `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Create CartItem struct representing the cart_items table in the database, a book the user wants to buy
+type CartItem struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`                               // Primary key, auto-increment id with json tag id for json marshalling
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_cart_items_user_book" json:"-"`             // Owner of the cart
+	BookID    uint64    `gorm:"not null;uniqueIndex:idx_cart_items_user_book;index" json:"book_id"` // Book in the cart
+	CreatedAt time.Time `json:"created_at"`                                                         // Time the book was added
+	Book      *Book     `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"book,omitempty"`
+	User      *User     `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
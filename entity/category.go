@@ -0,0 +1,11 @@
+package entity
+
+// Create Category struct representing the category table in the database
+type Category struct {
+	ID        uint64  `gorm:"primary_key;auto_increment" json:"id"`               // Primary key, auto-increment id with json tag id for json marshalling
+	Name      string  `gorm:"type:varchar(100);not null" json:"name"`             // Display name of the category
+	Slug      string  `gorm:"type:varchar(100);not null;uniqueIndex" json:"slug"` // URL friendly name used by the book filter
+	ParentID  *uint64 `gorm:"index" json:"parent_id"`                             // Parent category, null for root categories
+	Path      string  `gorm:"type:varchar(255);not null;index" json:"path"`       // Materialized path of ids from the root, for example /1/4/
+	BookCount int64   `gorm:"-:migration;->" json:"book_count,omitempty"`         // Books in the category and its subcategories (read only)
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Create IdempotencyRecord struct representing the idempotency_records table in the database
+type IdempotencyRecord struct {
+	Key         string    `gorm:"type:varchar(191);primary_key"` // Scoped Idempotency-Key
+	Fingerprint string    `gorm:"type:char(64);not null"`        // SHA-256 of the request method, path and body
+	Completed   bool      `gorm:"not null"`                      // Whether the response is stored
+	StatusCode  int       `gorm:"not null"`                      // Stored response status code
+	ContentType string    `gorm:"type:varchar(255)"`             // Stored response content type
+	Body        []byte    `gorm:"type:mediumblob"`               // Stored response body
+	ExpiresAt   time.Time `gorm:"not null;index"`                // Time after which the key may be reused
+	CreatedAt   time.Time `gorm:"not null"`
+}
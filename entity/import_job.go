@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Statuses of an import job
+const (
+	ImportPending = "pending" // waiting for a worker
+	ImportRunning = "running" // rows are being imported
+	ImportDone    = "done"    // every row was handled, see the report
+	ImportFailed  = "failed"  // the job stopped, see the error
+)
+
+// Create ImportJob struct representing the import_jobs table in the database, a bulk import of books by a user
+type ImportJob struct {
+	ID            uint64            `gorm:"primary_key;auto_increment" json:"id"`          // Primary key, auto-increment id with json tag id for json marshalling
+	UserID        uint64            `gorm:"not null;index" json:"-"`                       // Owner of the imported books
+	Format        string            `gorm:"type:varchar(10);not null" json:"format"`       // csv or ndjson
+	Status        string            `gorm:"type:varchar(20);not null;index" json:"status"` // pending, running, done or failed
+	TotalRows     int               `gorm:"not null" json:"total_rows"`                    // Rows in the file
+	ProcessedRows int               `gorm:"not null" json:"processed_rows"`                // Rows handled so far
+	CreatedRows   int               `gorm:"not null" json:"created_rows"`                  // Rows that created a book
+	FailedRows    int               `gorm:"not null" json:"failed_rows"`                   // Rows that were rejected
+	Error         string            `gorm:"type:varchar(255)" json:"error,omitempty"`      // Why the job failed
+	Report        []ImportRowResult `gorm:"type:longtext;serializer:json" json:"report"`   // Result of every row, written when the job is done
+	CreatedAt     time.Time         `json:"created_at"`                                    // Time the file was uploaded
+	UpdatedAt     time.Time         `json:"updated_at"`                                    // Time of the last progress
+	FinishedAt    *time.Time        `json:"finished_at"`                                   // Time the job was done or failed
+	User          *User             `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// ImportRowResult is the result of a row of an import, either the created book or the error
+type ImportRowResult struct {
+	Line   int    `json:"line"`              // Line of the row in the file
+	BookID uint64 `json:"book_id,omitempty"` // Created book
+	Error  string `json:"error,omitempty"`   // Why the row was rejected
+}
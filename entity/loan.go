@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Statuses of a loan
+const (
+	LoanRequested = "requested" // the borrower asked, waiting for the lender
+	LoanRejected  = "rejected"  // the lender declined the request
+	LoanCancelled = "cancelled" // the borrower withdrew the request
+	LoanActive    = "active"    // the book is with the borrower until it is returned
+	LoanReturned  = "returned"  // the lender got the book back
+)
+
+// Create Loan struct representing the loans table in the database, a user borrows the book of another user
+type Loan struct {
+	ID         uint64     `gorm:"primary_key;auto_increment" json:"id"`          // Primary key, auto-increment id with json tag id for json marshalling
+	BookID     uint64     `gorm:"not null;index" json:"book_id"`                 // Lent book
+	BorrowerID uint64     `gorm:"not null;index" json:"borrower_id"`             // User borrowing the book
+	LenderID   uint64     `gorm:"not null;index" json:"lender_id"`               // Owner of the book when it was requested
+	Status     string     `gorm:"type:varchar(20);not null;index" json:"status"` // requested, rejected, cancelled, active or returned
+	Note       string     `gorm:"type:varchar(255)" json:"note"`                 // Message of the borrower
+	DueAt      *time.Time `json:"due_at"`                                        // Date the book must be returned, proposed by the borrower and set by the lender
+	ApprovedAt *time.Time `json:"approved_at"`                                   // Time the book was lent
+	ReturnedAt *time.Time `json:"returned_at"`                                   // Time the book came back
+	CreatedAt  time.Time  `json:"created_at"`                                    // Time the loan was requested
+	UpdatedAt  time.Time  `json:"updated_at"`                                    // Time the status last changed
+	Overdue    bool       `gorm:"-" json:"overdue"`                              // Active and past its due date, computed when read
+	Book       *Book      `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"book,omitempty"`
+	Borrower   *User      `gorm:"foreignkey:BorrowerID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"borrower,omitempty"`
+	Lender     *User      `gorm:"foreignkey:LenderID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"lender,omitempty"`
+}
+
+// IsOverdue reports whether the book is still lent after its due date
+func (l Loan) IsOverdue(now time.Time) bool {
+	return l.Status == LoanActive && l.DueAt != nil && now.After(*l.DueAt)
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+)
+
+// Statuses of an order
+const (
+	OrderPending   = "pending"   // checked out, waiting for the payment
+	OrderPaid      = "paid"      // paid, waiting to be shipped
+	OrderShipped   = "shipped"   // sent to the buyer
+	OrderCancelled = "cancelled" // cancelled, its books are for sale again
+	OrderRefunded  = "refunded"  // paid back through the payment gateway, its books are for sale again
+)
+
+// orderTransitions lists the statuses an order can move to from each status
+var orderTransitions = map[string][]string{
+	OrderPending: {OrderPaid, OrderCancelled},
+	OrderPaid:    {OrderShipped, OrderCancelled},
+}
+
+// Create Order struct representing the orders table in the database, the books a buyer bought from one seller
+type Order struct {
+	ID              uint64      `gorm:"primary_key;auto_increment" json:"id"`                  // Primary key, auto-increment id with json tag id for json marshalling
+	BuyerID         uint64      `gorm:"not null;index" json:"buyer_id"`                        // User buying the books
+	SellerID        uint64      `gorm:"not null;index" json:"seller_id"`                       // Owner of the books
+	Status          string      `gorm:"type:varchar(20);not null;index" json:"status"`         // pending, paid, shipped, cancelled or refunded
+	Total           money.Money `gorm:"embedded;embeddedPrefix:total_" json:"total"`           // Sum of the prices of the items
+	PaymentIntentID *string     `gorm:"type:varchar(64);uniqueIndex" json:"payment_intent_id"` // Payment of the payment gateway, null when paid outside of it
+	PaidAt          *time.Time  `json:"paid_at"`                                               // Time the order was paid
+	ShippedAt       *time.Time  `json:"shipped_at"`                                            // Time the order was shipped
+	CancelledAt     *time.Time  `json:"cancelled_at"`                                          // Time the order was cancelled
+	RefundedAt      *time.Time  `json:"refunded_at"`                                           // Time the payment was given back
+	CreatedAt       time.Time   `json:"created_at"`                                            // Time of the checkout
+	UpdatedAt       time.Time   `json:"updated_at"`                                            // Time the status last changed
+	Items           []OrderItem `gorm:"constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"items"`
+	Buyer           *User       `gorm:"foreignkey:BuyerID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"buyer,omitempty"`
+	Seller          *User       `gorm:"foreignkey:SellerID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"seller,omitempty"`
+}
+
+// CanChangeTo reports whether the order can move from its status to the status
+func (o Order) CanChangeTo(status string) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Create OrderItem struct representing the order_items table in the database, the book is copied as it was sold
+type OrderItem struct {
+	ID      uint64      `gorm:"primary_key;auto_increment" json:"id"`        // Primary key, auto-increment id with json tag id for json marshalling
+	OrderID uint64      `gorm:"not null;index" json:"order_id"`              // Order of the item
+	BookID  *uint64     `gorm:"index" json:"book_id"`                        // Sold book, null once the book is deleted
+	Title   string      `gorm:"type:varchar(255)" json:"title"`              // Title of the book at checkout
+	Author  string      `gorm:"type:varchar(255)" json:"author"`             // Author of the book at checkout
+	ISBN    *string     `gorm:"type:varchar(13)" json:"isbn"`                // ISBN of the book at checkout
+	Price   money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"` // Price of the book at checkout
+	Book    *Book       `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:SET NULL" json:"-"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Create PaymentEvent struct representing the payment_events table in the database, every webhook event is applied once
+type PaymentEvent struct {
+	ID        string    `gorm:"type:varchar(64);primary_key" json:"id"`           // Id of the event given by the gateway
+	Type      string    `gorm:"type:varchar(64);not null" json:"type"`            // payment.succeeded or payment.refunded
+	IntentID  string    `gorm:"type:varchar(64);not null;index" json:"intent_id"` // Payment intent of the event
+	CreatedAt time.Time `json:"created_at"`                                       // Time the event was received
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Create RateLimitBucket struct representing the rate_limit_buckets table in the database
+type RateLimitBucket struct {
+	Key       string    `gorm:"type:varchar(191);primary_key"` // Bucket key, for example books:user:1
+	Tokens    float64   `gorm:"not null"`                      // Tokens left in the bucket
+	UpdatedAt time.Time `gorm:"not null"`                      // Last time the bucket was refilled
+	ExpiresAt time.Time `gorm:"index"`                         // Time the bucket is full again, the row can be deleted after it
+}
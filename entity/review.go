@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Create Review struct representing the review table in the database, a user reviews a book at most once
+type Review struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`                      // Primary key, auto-increment id with json tag id for json marshalling
+	BookID    uint64    `gorm:"not null;uniqueIndex:idx_reviews_book_user" json:"book_id"` // Reviewed book
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_reviews_book_user;index" json:"-"` // Author of the review
+	Rating    uint8     `gorm:"type:tinyint unsigned;not null" json:"rating"`              // Stars from 1 to 5
+	Text      string    `gorm:"type:text" json:"text"`                                     // Text of the review, may be empty
+	CreatedAt time.Time `json:"created_at"`                                                // Time the review was written
+	UpdatedAt time.Time `json:"updated_at"`                                                // Time the review was last changed
+	User      *User     `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user"`
+	Book      *Book     `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
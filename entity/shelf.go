@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Privacy settings of a shelf
+const (
+	ShelfPrivate = "private" // Only the owner sees the shelf
+	ShelfPublic  = "public"  // Everybody sees the shelf and its books
+)
+
+// Default shelves every user starts with
+var DefaultShelves = []string{"Favorites", "Want to read", "Reading", "Read"}
+
+// Create Shelf struct representing the shelf table in the database, a named list of books of a user
+type Shelf struct {
+	ID        uint64       `gorm:"primary_key;auto_increment" json:"id"`                                     // Primary key, auto-increment id with json tag id for json marshalling
+	UserID    uint64       `gorm:"not null;uniqueIndex:idx_shelves_user_name" json:"user_id"`                // Owner of the shelf
+	Name      string       `gorm:"type:varchar(100);not null;uniqueIndex:idx_shelves_user_name" json:"name"` // Name of the shelf, unique per user
+	Privacy   string       `gorm:"type:varchar(10);not null;default:private" json:"privacy"`                 // private or public
+	CreatedAt time.Time    `json:"created_at"`                                                               // Time the shelf was created
+	BookCount int64        `gorm:"-:migration;->" json:"book_count"`                                         // Books on the shelf (read only)
+	User      *User        `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Entries   []ShelfEntry `gorm:"constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"entries,omitempty"` // Books on the shelf
+}
+
+// Create ShelfEntry struct representing a book on a shelf with the reading progress of the owner
+type ShelfEntry struct {
+	ID         uint64     `gorm:"primary_key;auto_increment" json:"id"`                              // Primary key, auto-increment id with json tag id for json marshalling
+	ShelfID    uint64     `gorm:"not null;uniqueIndex:idx_shelf_entries_shelf_book" json:"shelf_id"` // Shelf of the entry
+	BookID     uint64     `gorm:"not null;uniqueIndex:idx_shelf_entries_shelf_book;index" json:"book_id"`
+	Progress   uint8      `gorm:"not null;default:0" json:"progress"` // Reading progress in percent
+	StartedAt  *time.Time `json:"started_at"`                         // Day the user started reading
+	FinishedAt *time.Time `json:"finished_at"`                        // Day the user finished reading
+	CreatedAt  time.Time  `json:"created_at"`                         // Time the book was added to the shelf
+	UpdatedAt  time.Time  `json:"updated_at"`                         // Time the entry was last changed
+	Book       *Book      `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"book,omitempty"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+// Create Tag struct representing the tag table in the database
+type Tag struct {
+	ID        uint64 `gorm:"primary_key;auto_increment" json:"id"`              // Primary key, auto-increment id with json tag id for json marshalling
+	Name      string `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"` // Lower-case tag name
+	BookCount int64  `gorm:"-:migration;->" json:"book_count,omitempty"`        // Books with the tag (read only)
+}
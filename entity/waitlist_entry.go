@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Statuses of a waitlist entry, waiting and offered entries are in the queue
+const (
+	WaitlistWaiting  = "waiting"  // in line for the book
+	WaitlistOffered  = "offered"  // first in line, the book is offered until OfferExpiresAt
+	WaitlistAccepted = "accepted" // took the offered book
+	WaitlistExpired  = "expired"  // let the offer expire, the book went to the next person
+	WaitlistLeft     = "left"     // left the queue
+)
+
+// Create WaitlistEntry struct representing the waitlist_entries table in the database, users queue for a book first come first served
+type WaitlistEntry struct {
+	ID             uint64     `gorm:"primary_key;auto_increment" json:"id"`                                           // Primary key, auto-increment id, also the order of the queue
+	BookID         uint64     `gorm:"not null;index:idx_waitlist_entries_book_status" json:"book_id"`                 // Awaited book
+	UserID         uint64     `gorm:"not null;index" json:"user_id"`                                                  // User in line
+	Status         string     `gorm:"type:varchar(16);not null;index:idx_waitlist_entries_book_status" json:"status"` // waiting, offered, accepted, expired or left
+	OfferedAt      *time.Time `json:"offered_at"`                                                                     // Time the book was offered
+	OfferExpiresAt *time.Time `json:"offer_expires_at"`                                                               // Time the offer moves on to the next person
+	CreatedAt      time.Time  `json:"created_at"`                                                                     // Time the user joined the queue
+	UpdatedAt      time.Time  `json:"updated_at"`                                                                     // Time the status last changed
+	Position       int        `gorm:"-" json:"position,omitempty"`                                                    // Place in the queue, 1 is offered first, computed when read
+	User           *User      `gorm:"foreignkey:UserID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
+	Book           *Book      `gorm:"foreignkey:BookID;references:ID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+)
+
+// authorSeparators always split a free-text author field into several authors, a comma may also be part of a name
+var authorSeparators = strings.NewReplacer("&", ";", " and ", ";")
+
+// SplitAuthorNames splits a free-text author field ("A, B & C") into clean author names, a single word followed by
+// a comma is a last name so "Tolkien, J.R.R." is the single author "J.R.R. Tolkien"
+func SplitAuthorNames(s string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, group := range strings.Split(authorSeparators.Replace(s), ";") {
+		parts := strings.Split(group, ",")
+		for i := 0; i < len(parts); i++ {
+			name := strings.Join(strings.Fields(parts[i]), " ") // Collapse the spaces
+			if name != "" && !strings.Contains(name, " ") && i+1 < len(parts) {
+				if first := strings.Join(strings.Fields(parts[i+1]), " "); first != "" {
+					name = first + " " + name // Put the inverted name back in order
+					i++
+				}
+			}
+			key := AuthorNameKey(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AuthorNameKey returns the key used to find duplicate authors, made of the lower-cased letters and digits of the name
+func AuthorNameKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitAuthorNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		author string
+		want   []string
+	}{
+		{"empty", "  ", nil},
+		{"single", "Neil  Gaiman", []string{"Neil Gaiman"}},
+		{"comma", "Neil Gaiman, Terry Pratchett", []string{"Neil Gaiman", "Terry Pratchett"}},
+		{"separators", "A. Author; B. Author & C. Author and D. Author", []string{"A. Author", "B. Author", "C. Author", "D. Author"}},
+		{"inverted", "Tolkien, J.R.R.", []string{"J.R.R. Tolkien"}},
+		{"inverted with spaced initials", "Tolkien, J. R. R.", []string{"J. R. R. Tolkien"}},
+		{"inverted list", "Gaiman, Neil, Pratchett, Terry", []string{"Neil Gaiman", "Terry Pratchett"}},
+		{"inverted and separator", "Gaiman, Neil & Pratchett, Terry", []string{"Neil Gaiman", "Terry Pratchett"}},
+		{"single word", "Plato", []string{"Plato"}},
+		{"trailing comma", "Plato,", []string{"Plato"}},
+		{"duplicate key", "J.K. Rowling, JK Rowling", []string{"J.K. Rowling"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitAuthorNames(tt.author); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitAuthorNames(%q) = %q, want %q", tt.author, got, tt.want)
+			}
+		})
+	}
+}
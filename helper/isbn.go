@@ -0,0 +1,85 @@
+package helper
+
+import "strings"
+
+// CleanISBN removes the hyphens and spaces of an ISBN and upper-cases the X check digit
+func CleanISBN(isbn string) string {
+	isbn = strings.ToUpper(isbn)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, isbn)
+}
+
+// IsValidISBN checks the format and check digit of an ISBN-10 or ISBN-13
+func IsValidISBN(isbn string) bool {
+	isbn = CleanISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		return isValidISBN10(isbn)
+	case 13:
+		return isValidISBN13(isbn)
+	default:
+		return false
+	}
+}
+
+// NormalizeISBN converts a valid ISBN-10 or ISBN-13 to its ISBN-13 form without hyphens,
+// returns an empty string if the ISBN is not valid
+func NormalizeISBN(isbn string) string {
+	isbn = CleanISBN(isbn)
+	if !IsValidISBN(isbn) {
+		return ""
+	}
+	if len(isbn) == 13 {
+		return isbn
+	}
+
+	// ISBN-10 becomes 978 + the first 9 digits + a new ISBN-13 check digit
+	isbn13 := "978" + isbn[:9]
+	return isbn13 + string(rune('0'+isbn13CheckDigit(isbn13)))
+}
+
+// isValidISBN10 checks an ISBN-10, the weighted sum must be a multiple of 11
+func isValidISBN10(isbn string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := isbn[i]
+		var d int
+		switch {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10 // X is only allowed as check digit
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// isValidISBN13 checks an ISBN-13, the last digit must match the check digit
+func isValidISBN13(isbn string) bool {
+	for i := 0; i < 13; i++ {
+		if isbn[i] < '0' || isbn[i] > '9' {
+			return false
+		}
+	}
+	return int(isbn[12]-'0') == isbn13CheckDigit(isbn[:12])
+}
+
+// isbn13CheckDigit computes the check digit of the first 12 digits of an ISBN-13
+func isbn13CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,56 @@
+package helper
+
+import "testing"
+
+func TestIsValidISBN(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn string
+		want bool
+	}{
+		{"isbn-10", "0306406152", true},
+		{"isbn-10 with hyphens", "0-306-40615-2", true},
+		{"isbn-10 with X check digit", "0-8044-2957-X", true},
+		{"isbn-10 with lower-case x", "080442957x", true},
+		{"isbn-10 wrong check digit", "0306406153", false},
+		{"isbn-10 X before the check digit", "08044295X7", false},
+		{"isbn-10 letter", "03064A6152", false},
+		{"isbn-13", "9780306406157", true},
+		{"isbn-13 with spaces", "978 0 306 40615 7", true},
+		{"isbn-13 check digit 0", "9780131101630", true},
+		{"isbn-13 wrong check digit", "9780306406158", false},
+		{"isbn-13 with X", "978030640615X", false},
+		{"isbn-13 letter", "97803064A6157", false},
+		{"empty", "", false},
+		{"too short", "030640615", false},
+		{"too long", "97803064061570", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidISBN(tt.isbn); got != tt.want {
+				t.Errorf("IsValidISBN(%q) = %v, want %v", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn string
+		want string
+	}{
+		{"isbn-10", "0-306-40615-2", "9780306406157"},
+		{"isbn-10 with X check digit", "080442957X", "9780804429573"},
+		{"isbn-13", "978-0-306-40615-7", "9780306406157"},
+		{"invalid", "0306406153", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeISBN(tt.isbn); got != tt.want {
+				t.Errorf("NormalizeISBN(%q) = %q, want %q", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is a request seen with an Idempotency-Key and, once completed, its response
+type Record struct {
+	Key         string    // Scoped idempotency key
+	Fingerprint string    // Hash of the request method, path and body
+	Completed   bool      // Whether the response below is available
+	StatusCode  int       // Stored response status code
+	ContentType string    // Stored response content type
+	Body        []byte    // Stored response body
+	ExpiresAt   time.Time // Time after which the key may be reused
+}
+
+// Store is a contract of where idempotency records are kept
+type Store interface {
+	// Reserve creates an in-progress record for the key, or returns the existing unexpired record with reserved false
+	Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (record Record, reserved bool, err error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// Release removes a reserved key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,68 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps the idempotency records in memory of this process
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+// NewMemoryStore method is used to create a new in-memory Store
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[string]*Record), now: time.Now}
+}
+
+// Reserve creates an in-progress record for the key, or returns the existing unexpired record
+func (s *memoryStore) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok {
+		return *r, false, nil
+	}
+
+	r := &Record{Key: key, Fingerprint: fingerprint, ExpiresAt: now.Add(ttl)}
+	s.records[key] = r
+	return *r, true, nil
+}
+
+// Complete stores the response of a reserved key
+func (s *memoryStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok {
+		r.Completed = true
+		r.StatusCode = statusCode
+		r.ContentType = contentType
+		r.Body = body
+	}
+	return nil
+}
+
+// Release removes a reserved key so the request can be retried
+func (s *memoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweep removes the expired records
+func (s *memoryStore) sweep(now time.Time) {
+	for key, r := range s.records {
+		if now.After(r.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
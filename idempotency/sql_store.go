@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sweepInterval is the shortest time between two purges of the expired records
+const sweepInterval = time.Minute
+
+// sqlStore keeps the idempotency records in the database so they are shared between instances
+type sqlStore struct {
+	connection *gorm.DB
+	mu         sync.Mutex
+	sweptAt    time.Time // Last purge of the expired records by this instance
+}
+
+// NewSQLStore method is used to create a new Store backed by the idempotency_records table
+func NewSQLStore(connection *gorm.DB) Store {
+	return &sqlStore{connection: connection}
+}
+
+// Reserve creates an in-progress record for the key, or returns the existing unexpired record
+func (s *sqlStore) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	var record Record
+	reserved := false
+
+	if err := s.sweep(ctx, time.Now()); err != nil {
+		return record, reserved, err
+	}
+
+	err := s.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Lock the record so two retries cannot both reserve the key
+		var existing entity.IdempotencyRecord
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).Take(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		row := entity.IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: now.Add(ttl), CreatedAt: now}
+
+		if err == nil {
+			if existing.ExpiresAt.After(now) {
+				record = toRecord(existing)
+				return nil
+			}
+			// Replace the expired record, the row is locked by this transaction
+			if err := tx.Save(&row).Error; err != nil {
+				return err
+			}
+			record, reserved = toRecord(row), true
+			return nil
+		}
+
+		// Insert the reservation, a concurrent retry may have inserted it first
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).Take(&existing).Error; err != nil {
+				return err
+			}
+			record = toRecord(existing)
+			return nil
+		}
+		record, reserved = toRecord(row), true
+		return nil
+	})
+
+	return record, reserved, err
+}
+
+// Complete stores the response of a reserved key
+func (s *sqlStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	return s.connection.WithContext(ctx).Model(&entity.IdempotencyRecord{}).Where("`key` = ?", key).Updates(map[string]interface{}{
+		"completed":    true,
+		"status_code":  statusCode,
+		"content_type": contentType,
+		"body":         body,
+	}).Error
+}
+
+// Release removes a reserved key so the request can be retried
+func (s *sqlStore) Release(ctx context.Context, key string) error {
+	return s.connection.WithContext(ctx).Where("`key` = ?", key).Delete(&entity.IdempotencyRecord{}).Error
+}
+
+// sweep deletes the expired records, at most once per sweepInterval
+func (s *sqlStore) sweep(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.sweptAt) < sweepInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.sweptAt = now
+	s.mu.Unlock()
+
+	return s.connection.WithContext(ctx).Where("expires_at <= ?", now).Delete(&entity.IdempotencyRecord{}).Error
+}
+
+// toRecord converts the database row to a Record
+func toRecord(r entity.IdempotencyRecord) Record {
+	return Record{
+		Key:         r.Key,
+		Fingerprint: r.Fingerprint,
+		Completed:   r.Completed,
+		StatusCode:  r.StatusCode,
+		ContentType: r.ContentType,
+		Body:        r.Body,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
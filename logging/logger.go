@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New creates a leveled logger writing JSON (default) or console encoded entries to stdout
+func New(level string, format string) (*zap.Logger, error) {
+	cfg := zap.NewProductionConfig() // JSON encoder writing to stdout
+	if format == "console" {
+		cfg.Encoding = "console"
+		cfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	cfg.OutputPaths = []string{"stdout"}
+	cfg.EncoderConfig.TimeKey = "time"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	// Use info level when LOG_LEVEL is empty
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Level = zap.NewAtomicLevelAt(lvl)
+	}
+
+	// Wrap the core so passwords and tokens never reach the output
+	return cfg.Build(zap.WrapCore(newRedactCore))
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted is the value written instead of a sensitive value
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are field and header names whose values must never be logged
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"password":      true,
+	"token":         true,
+}
+
+// isSensitive checks if the given field or header name holds a secret
+func isSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// RedactHeaders returns a copy of the headers with the sensitive values replaced
+func RedactHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string, len(headers))
+	for key, values := range headers {
+		if isSensitive(key) {
+			result[key] = redacted
+			continue
+		}
+		result[key] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// redactCore is a zapcore.Core that replaces the value of sensitive fields
+type redactCore struct {
+	zapcore.Core
+}
+
+// newRedactCore wraps the given core with field redaction
+func newRedactCore(core zapcore.Core) zapcore.Core {
+	return &redactCore{Core: core}
+}
+
+// With redacts the fields added to the child core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+// Check adds this core (and not the wrapped one) to the checked entry
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write redacts the fields before writing the entry
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+// redactFields replaces the value of every sensitive field
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	result := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		if isSensitive(field.Key) {
+			field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: redacted}
+		}
+		result[i] = field
+	}
+	return result
+}
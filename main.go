@@ -1,57 +1,272 @@
 package main
 
 import (
+	"path/filepath"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/config"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/controllers"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/idempotency"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/middleware"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ratelimit"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/storage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 var (
-	db             *gorm.DB                   = config.SetupDatabase()
-	userRepository repository.UserRepository  = repository.NewUserRepository(db)
-	bookRepository repository.BookRepository  = repository.NewBookRepository(db)
-	jwtService     services.JWTService        = services.NewJWTService()
-	userService    services.UserService       = services.NewUserService(userRepository)
-	bookService    services.BookService       = services.NewBookService(bookRepository)
-	authService    services.AuthService       = services.NewAuthService(userRepository)
-	authController                            = controllers.NewAuthController(authService, jwtService)
-	userController controllers.UserController = controllers.NewUserController(userService, jwtService)
-	bookController controllers.BookController = controllers.NewBookController(bookService, jwtService)
+	logger                 *zap.Logger                        = config.SetupLogger()
+	tracerProvider         *sdktrace.TracerProvider           = config.SetupTracer()
+	db                     *gorm.DB                           = config.SetupDatabase()
+	userRepository         repository.UserRepository          = repository.NewUserRepository(db)
+	bookRepository         repository.BookRepository          = repository.NewBookRepository(db)
+	bookFileRepository     repository.BookFileRepository      = repository.NewBookFileRepository(db)
+	importJobRepository    repository.ImportJobRepository     = repository.NewImportJobRepository(db)
+	authorRepository       repository.AuthorRepository        = repository.NewAuthorRepository(db)
+	categoryRepository     repository.CategoryRepository      = repository.NewCategoryRepository(db)
+	tagRepository          repository.TagRepository           = repository.NewTagRepository(db)
+	reviewRepository       repository.ReviewRepository        = repository.NewReviewRepository(db)
+	shelfRepository        repository.ShelfRepository         = repository.NewShelfRepository(db)
+	loanRepository         repository.LoanRepository          = repository.NewLoanRepository(db)
+	waitlistRepository     repository.WaitlistRepository      = repository.NewWaitlistRepository(db)
+	bookCopyRepository     repository.BookCopyRepository      = repository.NewBookCopyRepository(db)
+	cartRepository         repository.CartRepository          = repository.NewCartRepository(db)
+	orderRepository        repository.OrderRepository         = repository.NewOrderRepository(db)
+	reportRepository       repository.ReportRepository        = config.SetupReportRepository(db)
+	auditRepository        repository.AuditRepository         = repository.NewAuditRepository(db)
+	collaboratorRepository repository.CollaboratorRepository  = repository.NewCollaboratorRepository(db)
+	rateLimitStore         ratelimit.Store                    = config.SetupRateLimitStore(db)
+	idempotencyStore       idempotency.Store                  = config.SetupIdempotencyStore(db)
+	signer                 *storage.Signer                    = config.SetupSigner()
+	blobStore              storage.BlobStore                  = config.SetupBlobStore(signer)
+	paymentGateway         payment.PaymentGateway             = config.SetupPaymentGateway(logger)
+	jwtService             services.JWTService                = services.NewJWTService()
+	userService            services.UserService               = services.NewUserService(userRepository, logger)
+	bookService            services.BookService               = services.NewBookService(bookRepository, authorRepository, categoryRepository, tagRepository, logger)
+	authService            services.AuthService               = services.NewAuthService(userRepository, logger)
+	authorService          services.AuthorService             = services.NewAuthorService(authorRepository, logger)
+	catalogService         services.CatalogService            = services.NewCatalogService(config.SetupCatalogProvider(), logger)
+	categoryService        services.CategoryService           = services.NewCategoryService(categoryRepository, logger)
+	tagService             services.TagService                = services.NewTagService(tagRepository, logger)
+	reviewService          services.ReviewService             = services.NewReviewService(reviewRepository, logger)
+	shelfService           services.ShelfService              = services.NewShelfService(shelfRepository, logger)
+	loanService            services.LoanService               = services.NewLoanService(loanRepository, logger)
+	waitlistService        services.WaitlistService           = services.NewWaitlistService(waitlistRepository, bookRepository, config.WaitlistOfferTTL(), logger)
+	bookCopyService        services.BookCopyService           = services.NewBookCopyService(bookCopyRepository, logger)
+	cartService            services.CartService               = services.NewCartService(cartRepository, logger)
+	orderService           services.OrderService              = services.NewOrderService(orderRepository, logger)
+	paymentService         services.PaymentService            = services.NewPaymentService(orderRepository, paymentGateway, logger)
+	reportService          services.ReportService             = services.NewReportService(reportRepository, config.ReportTimezone(), logger)
+	auditService           services.AuditService              = services.NewAuditService(auditRepository, bookRepository, logger)
+	collaboratorService    services.CollaboratorService       = services.NewCollaboratorService(collaboratorRepository, bookRepository, userRepository, logger)
+	coverService           services.CoverService              = services.NewCoverService(bookRepository, blobStore, config.CoverMaxSize(), logger)
+	bookFileService        services.BookFileService           = services.NewBookFileService(bookFileRepository, blobStore, config.EbookMaxSize(), config.SignedURLTTL(), logger)
+	bookImportService      services.BookImportService         = services.NewBookImportService(importJobRepository, bookService, config.ImportLimits(), logger)
+	authController                                            = controllers.NewAuthController(authService, jwtService, logger)
+	userController         controllers.UserController         = controllers.NewUserController(userService, logger)
+	bookController         controllers.BookController         = controllers.NewBookController(bookService, catalogService, coverService, bookFileService, logger)
+	bookFileController     controllers.BookFileController     = controllers.NewBookFileController(bookFileService, bookService, blobStore, signer, logger)
+	bookImportController   controllers.BookImportController   = controllers.NewBookImportController(bookImportService, logger)
+	authorController       controllers.AuthorController       = controllers.NewAuthorController(authorService, logger)
+	categoryController     controllers.CategoryController     = controllers.NewCategoryController(categoryService, logger)
+	tagController          controllers.TagController          = controllers.NewTagController(tagService, logger)
+	reviewController       controllers.ReviewController       = controllers.NewReviewController(reviewService, bookService, logger)
+	shelfController        controllers.ShelfController        = controllers.NewShelfController(shelfService, bookService, logger)
+	loanController         controllers.LoanController         = controllers.NewLoanController(loanService, bookService, logger)
+	waitlistController     controllers.WaitlistController     = controllers.NewWaitlistController(waitlistService, bookService, logger)
+	bookCopyController     controllers.BookCopyController     = controllers.NewBookCopyController(bookCopyService, bookService, logger)
+	cartController         controllers.CartController         = controllers.NewCartController(cartService, orderService, bookService, logger)
+	orderController        controllers.OrderController        = controllers.NewOrderController(orderService, logger)
+	paymentController      controllers.PaymentController      = controllers.NewPaymentController(paymentService, bookService, orderService, config.PaymentWebhookSecret(), logger)
+	reportController       controllers.ReportController       = controllers.NewReportController(reportService, logger)
+	auditController        controllers.AuditController        = controllers.NewAuditController(auditService, logger)
+	collaboratorController controllers.CollaboratorController = controllers.NewCollaboratorController(collaboratorService, logger)
 )
 
 func main() {
+	dto.RegisterValidators()
+	defer config.CloseLogger(logger)
 	defer config.CloseDatabaseConnection(db)
-	r := gin.Default()
+	defer config.CloseTracer(tracerProvider)
+
+	// The rows of unfinished imports were lost with the previous process
+	config.FailInterruptedImports(importJobRepository)
 
-	authRoutes := r.Group("/api/auth")
+	r := gin.New()
+	// The client IP of the rate limits is only read from X-Forwarded-For behind a trusted proxy
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Tracing())
+	r.Use(middleware.AccessLog(logger))
+
+	// Serve the public files when they are stored on the local disk, the e-books are only served from signed URLs
+	if dir := config.LocalStorageDir(); dir != "" {
+		r.Static("/files/covers", filepath.Join(dir, "covers"))
+		r.GET("/api/files/download", bookFileController.Download)
+	}
+
+	idempotent := middleware.Idempotency(idempotencyStore, config.IdempotencyTTL(), config.IdempotencyMaxBody(), logger)
+
+	authRoutes := r.Group("/api/auth", middleware.RateLimit("auth", rateLimitStore, config.RateLimit("RATE_LIMIT_AUTH", "10/1m"), logger))
 	{
 		authRoutes.POST("/login", authController.Login)
-		authRoutes.POST("/register", authController.Register)
+		authRoutes.POST("/register", idempotent, authController.Register)
 	}
 
-	userRoutes := r.Group("/api/user", middleware.AuthorizeJWT(jwtService))
+	userRoutes := r.Group("/api/user", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("user", rateLimitStore, config.RateLimit("RATE_LIMIT_USER", "60/1m"), logger))
 	{
 		userRoutes.GET("/profile", userController.GetUser)
 		userRoutes.PUT("/profile", userController.UpdateUser)
+		userRoutes.GET("/shelves", shelfController.GetAll)
+		userRoutes.POST("/shelves", shelfController.CreateShelf)
+		userRoutes.GET("/shelves/:id", shelfController.GetByID)
+		userRoutes.PUT("/shelves/:id", shelfController.UpdateShelf)
+		userRoutes.DELETE("/shelves/:id", shelfController.DeleteShelf)
+		userRoutes.POST("/shelves/:id/books", shelfController.AddBook)
+		userRoutes.PUT("/shelves/:id/books/:bookId", shelfController.UpdateBook)
+		userRoutes.DELETE("/shelves/:id/books/:bookId", shelfController.RemoveBook)
+		userRoutes.GET("/invitations", collaboratorController.GetInvitations)
+		userRoutes.POST("/invitations/:id/accept", collaboratorController.Accept)
+		userRoutes.POST("/invitations/:id/decline", collaboratorController.Decline)
 	}
 
-	bookRoutes := r.Group("api/books", middleware.AuthorizeJWT(jwtService))
+	bookRoutes := r.Group("api/books", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("books", rateLimitStore, config.RateLimit("RATE_LIMIT_BOOKS", "60/1m"), logger))
 	{
 		bookRoutes.GET("/", bookController.GetAllMyBook)
 		bookRoutes.GET("/:id", bookController.GetByID)
-		bookRoutes.POST("/", bookController.CreateMyBook)
-		bookRoutes.PUT("/:id", bookController.UpdateMyBook)
-		bookRoutes.DELETE("/:id", bookController.DeleteMyBook)
+		bookRoutes.POST("/", idempotent, bookController.CreateMyBook)
+		bookRoutes.POST("/from-isbn", bookController.FromISBN)
+		bookRoutes.POST("/import", idempotent, bookImportController.Import)
+		bookRoutes.GET("/import/:jobId", bookImportController.GetJob)
+		bookRoutes.GET("/export", bookController.Export)
+		bookRoutes.PUT("/:id", middleware.BookEditor(bookService), bookController.UpdateMyBook)
+		bookRoutes.DELETE("/:id", middleware.BookOwner(bookService), bookController.DeleteMyBook)
+		bookRoutes.GET("/:id/history", middleware.BookEditor(bookService), auditController.BookHistory)
+		bookRoutes.POST("/:id/history/:entryId/revert", middleware.BookEditor(bookService), auditController.RevertBook)
+		bookRoutes.POST("/:id/transfer", middleware.BookOwner(bookService), collaboratorController.TransferBook)
+		bookRoutes.GET("/:id/collaborators", middleware.BookOwner(bookService), collaboratorController.GetAll)
+		bookRoutes.POST("/:id/collaborators", middleware.BookOwner(bookService), collaboratorController.Invite)
+		bookRoutes.DELETE("/:id/collaborators/:collaboratorId", middleware.BookOwner(bookService), collaboratorController.Revoke)
+		bookRoutes.POST("/:id/cover", middleware.BookEditor(bookService), bookController.UploadCover)
+		bookRoutes.DELETE("/:id/cover", middleware.BookEditor(bookService), bookController.DeleteCover)
+		bookRoutes.GET("/:id/files", middleware.BookOwner(bookService), bookFileController.GetAll)
+		bookRoutes.POST("/:id/files", middleware.BookOwner(bookService), bookFileController.UploadFile)
+		bookRoutes.GET("/:id/files/:fileId/download", middleware.BookOwner(bookService), bookFileController.DownloadURL)
+		bookRoutes.DELETE("/:id/files/:fileId", middleware.BookOwner(bookService), bookFileController.DeleteFile)
+		bookRoutes.GET("/:id/reviews", reviewController.GetAll)
+		bookRoutes.POST("/:id/reviews", reviewController.CreateReview)
+		bookRoutes.PUT("/:id/reviews/:reviewId", reviewController.UpdateReview)
+		bookRoutes.DELETE("/:id/reviews/:reviewId", reviewController.DeleteReview)
+		bookRoutes.GET("/:id/loans", middleware.BookOwner(bookService), loanController.GetByBook)
+		bookRoutes.PUT("/:id/availability", middleware.BookOwner(bookService), waitlistController.SetAvailability)
+		bookRoutes.GET("/:id/waitlist", waitlistController.GetAll)
+		bookRoutes.POST("/:id/waitlist", waitlistController.Join)
+		bookRoutes.DELETE("/:id/waitlist", waitlistController.Leave)
+		bookRoutes.POST("/:id/waitlist/accept", waitlistController.Accept)
+		bookRoutes.GET("/:id/copies", bookCopyController.GetAll)
+		bookRoutes.GET("/:id/copies/:copyId", bookCopyController.GetByID)
+		bookRoutes.POST("/:id/copies", middleware.BookOwner(bookService), bookCopyController.CreateCopy)
+		bookRoutes.PUT("/:id/copies/:copyId", middleware.BookOwner(bookService), bookCopyController.UpdateCopy)
+		bookRoutes.DELETE("/:id/copies/:copyId", middleware.BookOwner(bookService), bookCopyController.DeleteCopy)
+		bookRoutes.POST("/:id/purchase", idempotent, paymentController.Purchase)
+	}
+
+	loanRoutes := r.Group("/api/loans", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("loans", rateLimitStore, config.RateLimit("RATE_LIMIT_LOANS", "60/1m"), logger))
+	{
+		loanRoutes.POST("/", loanController.RequestLoan)
+		loanRoutes.GET("/borrowed", loanController.GetBorrowed)
+		loanRoutes.GET("/lent", loanController.GetLent)
+		loanRoutes.GET("/:id", loanController.GetByID)
+		loanRoutes.PUT("/:id/approve", loanController.ApproveLoan)
+		loanRoutes.PUT("/:id/reject", loanController.RejectLoan)
+		loanRoutes.PUT("/:id/cancel", loanController.CancelLoan)
+		loanRoutes.PUT("/:id/return", loanController.ReturnLoan)
+	}
+
+	// The payment gateway authenticates with the signature of the body
+	r.POST("/api/payments/webhook", paymentController.Webhook)
+
+	cartRoutes := r.Group("/api/cart", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("cart", rateLimitStore, config.RateLimit("RATE_LIMIT_CART", "60/1m"), logger))
+	{
+		cartRoutes.GET("/", cartController.GetCart)
+		cartRoutes.POST("/", cartController.AddItem)
+		cartRoutes.DELETE("/", cartController.ClearCart)
+		cartRoutes.DELETE("/:bookId", cartController.RemoveItem)
+		cartRoutes.POST("/checkout", idempotent, cartController.Checkout)
 	}
 
-	publicBookRoute := r.Group("/api/public/books")
+	orderRoutes := r.Group("/api/orders", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("orders", rateLimitStore, config.RateLimit("RATE_LIMIT_ORDERS", "60/1m"), logger))
+	{
+		orderRoutes.GET("/purchases", orderController.GetPurchases)
+		orderRoutes.GET("/sales", orderController.GetSales)
+		orderRoutes.GET("/:id", orderController.GetByID)
+		orderRoutes.PUT("/:id/status", orderController.ChangeStatus)
+		orderRoutes.POST("/:id/refund", idempotent, paymentController.Refund)
+	}
+
+	publicBookRoute := r.Group("/api/public/books", middleware.RateLimit("public-books", rateLimitStore, config.RateLimit("RATE_LIMIT_PUBLIC", "120/1m"), logger))
 	{
 		publicBookRoute.GET("/", bookController.GetAll)
 		publicBookRoute.GET("/:id", bookController.GetByID)
+		publicBookRoute.GET("/isbn/:isbn", bookController.GetByISBN)
+		publicBookRoute.GET("/shared/:token", bookController.GetShared)
+		publicBookRoute.GET("/:id/reviews", reviewController.GetAll)
+	}
+
+	authorRoutes := r.Group("/api/authors", middleware.AuthorizeJWT(jwtService, logger), middleware.RateLimit("authors", rateLimitStore, config.RateLimit("RATE_LIMIT_AUTHORS", "60/1m"), logger))
+	{
+		authorRoutes.GET("/", authorController.GetAll)
+		authorRoutes.GET("/:id", authorController.GetByID)
+		authorRoutes.POST("/", middleware.RequireRole(entity.RoleAdmin), authorController.CreateAuthor)
+		authorRoutes.PUT("/:id", middleware.RequireRole(entity.RoleAdmin), authorController.UpdateAuthor)
+		authorRoutes.DELETE("/:id", middleware.RequireRole(entity.RoleAdmin), authorController.DeleteAuthor)
+	}
+
+	publicAuthorRoute := r.Group("/api/public/authors", middleware.RateLimit("public-authors", rateLimitStore, config.RateLimit("RATE_LIMIT_PUBLIC", "120/1m"), logger))
+	{
+		publicAuthorRoute.GET("/", authorController.GetAll)
+		publicAuthorRoute.GET("/:id", authorController.GetByID)
+	}
+
+	adminRoutes := r.Group("/api/admin", middleware.AuthorizeJWT(jwtService, logger), middleware.RequireRole(entity.RoleAdmin), middleware.RateLimit("admin", rateLimitStore, config.RateLimit("RATE_LIMIT_ADMIN", "60/1m"), logger))
+	{
+		adminRoutes.POST("/categories", categoryController.CreateCategory)
+		adminRoutes.PUT("/categories/:id", categoryController.UpdateCategory)
+		adminRoutes.PUT("/categories/:id/move", categoryController.MoveCategory)
+		adminRoutes.DELETE("/categories/:id", categoryController.DeleteCategory)
+		adminRoutes.GET("/books/export", bookController.ExportAll)
+		adminRoutes.GET("/reports/books-created", reportController.BooksCreated)
+		adminRoutes.GET("/reports/active-users", reportController.ActiveUsers)
+		adminRoutes.GET("/reports/top-authors", reportController.TopAuthors)
+		adminRoutes.GET("/reports/price-distribution", reportController.PriceDistribution)
+		adminRoutes.GET("/reports/books-per-owner", reportController.BooksPerOwner)
+		adminRoutes.GET("/audit", auditController.Search)
+	}
+
+	publicCategoryRoute := r.Group("/api/public/categories", middleware.RateLimit("public-categories", rateLimitStore, config.RateLimit("RATE_LIMIT_PUBLIC", "120/1m"), logger))
+	{
+		publicCategoryRoute.GET("/", categoryController.GetAll)
+		publicCategoryRoute.GET("/:id", categoryController.GetByID)
+	}
+
+	publicTagRoute := r.Group("/api/public/tags", middleware.RateLimit("public-tags", rateLimitStore, config.RateLimit("RATE_LIMIT_PUBLIC", "120/1m"), logger))
+	{
+		publicTagRoute.GET("/", tagController.Autocomplete)
+	}
+
+	publicShelfRoute := r.Group("/api/public", middleware.RateLimit("public-shelves", rateLimitStore, config.RateLimit("RATE_LIMIT_PUBLIC", "120/1m"), logger))
+	{
+		publicShelfRoute.GET("/users/:id/shelves", shelfController.GetPublicByUser)
+		publicShelfRoute.GET("/shelves/:id", shelfController.GetPublicByID)
 	}
 
 	r.Run(":8080")
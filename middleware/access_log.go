@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+//AccessLog writes one log entry per request with the user ID, status and latency
+func AccessLog(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+			zap.Any("headers", logging.RedactHeaders(c.Request.Header)),
+		}
+
+		// The principal is set by AuthorizeJWT on authenticated routes
+		if principal, ok := auth.FromContext(c); ok {
+			fields = append(fields, zap.Uint64("user_id", principal.UserID))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		// Choose the level from the response status
+		level := zapcore.InfoLevel
+		if status >= 500 {
+			level = zapcore.ErrorLevel
+		} else if status >= 400 {
+			level = zapcore.WarnLevel
+		}
+
+		if ce := logging.With(c.Request.Context(), logger).Check(level, "request"); ce != nil {
+			ce.Write(fields...)
+		}
+	}
+}
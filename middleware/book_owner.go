@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+)
+
+//BookOwner allows the request only when the authenticated user owns the book of the :id parameter, return 403 if not
+func BookOwner(bookService services.BookService) gin.HandlerFunc {
+	return bookAction(bookService, services.BookActionManage)
+}
+
+//BookEditor allows the request only when the authenticated user owns or is an editor of the book of the :id parameter, return 403 if not
+func BookEditor(bookService services.BookService) gin.HandlerFunc {
+	return bookAction(bookService, services.BookActionUpdate)
+}
+
+//bookAction allows the request only when the role of the authenticated user on the book of the :id parameter allows the action
+func bookAction(bookService services.BookService, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get id from url parameter with key id
+		bookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			response := helper.ErrorsResponse(http.StatusBadRequest, "Book Not Found", err.Error(), helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+
+		// AuthorizeJWT must run before this middleware
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			response := helper.ErrorsResponse(http.StatusUnauthorized, "Failed to process request", "No token found", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		// Check if user is allowed to change the book
+		if !bookService.IsAllowedActionBook(c.Request.Context(), principal.UserID, bookID, action) {
+			response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "You are not allowed to access this book", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		c.Next()
+	}
+}
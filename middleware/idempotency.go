@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/idempotency"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader is the header carrying the client generated idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength is the longest idempotency key accepted
+const maxIdempotencyKeyLength = 128
+
+//Idempotency replays the stored response when a request is retried with the same Idempotency-Key,
+//return 422 when the key is reused with a different request and 413 when the body is larger than maxBody
+func Idempotency(store idempotency.Store, ttl time.Duration, maxBody int64, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader) // Get the key from the header of the request (if any)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", "Idempotency-Key is too long", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+
+		// Read the body to fingerprint it, then put it back for the handler, the body is limited before it is buffered
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response := helper.ErrorsResponse(http.StatusRequestEntityTooLarge, "Failed to process request", "request body is too large", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response)
+			return
+		}
+		if err != nil {
+			response := helper.ErrorsResponse(http.StatusBadRequest, "Failed to process request", err.Error(), helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the user (when authenticated) and the route
+		key := fmt.Sprintf("%s %s:%s", c.Request.Method, c.FullPath(), idempotencyKey)
+		if principal, ok := auth.FromContext(c); ok {
+			key = fmt.Sprintf("user:%d:%s", principal.UserID, key)
+		}
+		fingerprint := fingerprintRequest(c.Request.Method, c.Request.URL.Path, body)
+
+		record, reserved, err := store.Reserve(c.Request.Context(), key, fingerprint, ttl)
+		if err != nil {
+			// Fail open, an unavailable store must not take the API down
+			logging.With(c.Request.Context(), logger).Error("idempotency store failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if !reserved {
+			switch {
+			case record.Fingerprint != fingerprint:
+				response := helper.ErrorsResponse(http.StatusUnprocessableEntity, "Failed to process request", "Idempotency-Key was already used with a different request", helper.EmptyObject{})
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+			case !record.Completed:
+				response := helper.ErrorsResponse(http.StatusConflict, "Failed to process request", "A request with this Idempotency-Key is still in progress", helper.EmptyObject{})
+				c.AbortWithStatusJSON(http.StatusConflict, response)
+			default:
+				// Replay the stored response
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.StatusCode, record.ContentType, record.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// Capture the response written by the handler
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// A panicking handler never completes the key, release it before gin.Recovery answers 500
+		defer func() {
+			if r := recover(); r != nil {
+				if err := store.Release(c.Request.Context(), key); err != nil {
+					logging.With(c.Request.Context(), logger).Error("idempotency store failed", zap.Error(err))
+				}
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= 500 {
+			// Server errors are not final, let the client retry with the same key
+			if err := store.Release(c.Request.Context(), key); err != nil {
+				logging.With(c.Request.Context(), logger).Error("idempotency store failed", zap.Error(err))
+			}
+			return
+		}
+		if err := store.Complete(c.Request.Context(), key, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			logging.With(c.Request.Context(), logger).Error("idempotency store failed", zap.Error(err))
+		}
+	}
+}
+
+// fingerprintRequest hashes the parts of the request that must match on a retry
+func fingerprintRequest(method string, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyCaptureWriter is a gin.ResponseWriter that keeps a copy of the response body
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the response and to the copy
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString writes the string to the response and to the copy
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
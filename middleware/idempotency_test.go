@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/idempotency"
+	"go.uber.org/zap"
+)
+
+// idempotencyRequest is a request sent to the idempotent route and the answer expected
+type idempotencyRequest struct {
+	key          string
+	body         string
+	wantStatus   int
+	wantReplayed bool
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name      string
+		reserved  string // body of a request with the key "k" still in progress
+		responses []int  // status answered by the handler on each call, 0 panics
+		requests  []idempotencyRequest
+		wantCalls int
+	}{
+		{
+			name:      "replay",
+			responses: []int{http.StatusCreated},
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusCreated, false}, {"k", `{"a":1}`, http.StatusCreated, true}},
+			wantCalls: 1,
+		},
+		{
+			name:      "client error is replayed",
+			responses: []int{http.StatusBadRequest},
+			requests:  []idempotencyRequest{{"k", `{}`, http.StatusBadRequest, false}, {"k", `{}`, http.StatusBadRequest, true}},
+			wantCalls: 1,
+		},
+		{
+			name:      "different body",
+			responses: []int{http.StatusCreated},
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusCreated, false}, {"k", `{"a":2}`, http.StatusUnprocessableEntity, false}},
+			wantCalls: 1,
+		},
+		{
+			name:      "other key",
+			responses: []int{http.StatusCreated, http.StatusCreated},
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusCreated, false}, {"l", `{"a":1}`, http.StatusCreated, false}},
+			wantCalls: 2,
+		},
+		{
+			name:      "without key",
+			responses: []int{http.StatusCreated, http.StatusCreated},
+			requests:  []idempotencyRequest{{"", `{"a":1}`, http.StatusCreated, false}, {"", `{"a":1}`, http.StatusCreated, false}},
+			wantCalls: 2,
+		},
+		{
+			name:      "in progress",
+			reserved:  `{"a":1}`,
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusConflict, false}},
+			wantCalls: 0,
+		},
+		{
+			name:      "released after a server error",
+			responses: []int{http.StatusInternalServerError, http.StatusCreated},
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusInternalServerError, false}, {"k", `{"a":1}`, http.StatusCreated, false}, {"k", `{"a":1}`, http.StatusCreated, true}},
+			wantCalls: 2,
+		},
+		{
+			name:      "released after a panic",
+			responses: []int{0, http.StatusCreated},
+			requests:  []idempotencyRequest{{"k", `{"a":1}`, http.StatusInternalServerError, false}, {"k", `{"a":1}`, http.StatusCreated, false}},
+			wantCalls: 2,
+		},
+		{
+			name:      "key too long",
+			requests:  []idempotencyRequest{{strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`, http.StatusBadRequest, false}},
+			wantCalls: 0,
+		},
+		{
+			name:      "body too large",
+			requests:  []idempotencyRequest{{"k", strings.Repeat("a", 65), http.StatusRequestEntityTooLarge, false}},
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := idempotency.NewMemoryStore()
+			if tt.reserved != "" {
+				if _, _, err := store.Reserve(context.Background(), "POST /books:k", fingerprintRequest(http.MethodPost, "/books", []byte(tt.reserved)), time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			}
+			calls := 0
+			r := gin.New()
+			r.Use(gin.CustomRecovery(func(c *gin.Context, err interface{}) { c.AbortWithStatus(http.StatusInternalServerError) }))
+			r.POST("/books", Idempotency(store, time.Hour, 64, zap.NewNop()), func(c *gin.Context) {
+				calls++
+				status := tt.responses[calls-1]
+				if status == 0 {
+					panic("handler failed")
+				}
+				c.JSON(status, gin.H{"call": calls})
+			})
+
+			var previous string
+			for i, req := range tt.requests {
+				w := httptest.NewRecorder()
+				request := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(req.body))
+				if req.key != "" {
+					request.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				r.ServeHTTP(w, request)
+				if w.Code != req.wantStatus {
+					t.Errorf("request %d status = %d, want %d", i+1, w.Code, req.wantStatus)
+				}
+				if replayed := w.Header().Get("Idempotent-Replayed") == "true"; replayed != req.wantReplayed {
+					t.Errorf("request %d replayed = %v, want %v", i+1, replayed, req.wantReplayed)
+				}
+				if req.wantReplayed && w.Body.String() != previous {
+					t.Errorf("request %d body = %s, want the stored %s", i+1, w.Body.String(), previous)
+				}
+				previous = w.Body.String()
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -1,33 +1,32 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/services"
+	"go.uber.org/zap"
 )
 
-//AuthorizeJWT validates the token user given, return 401 if not valid
-func AuthorizeJWT(jwtService services.JWTService) gin.HandlerFunc {
+//AuthorizeJWT validates the token user given and stores its principal in the context, return 401 if not valid
+func AuthorizeJWT(jwtService services.JWTService, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization") // Get the token from the header of the request (if any) // Get the token from the header of the request (if any)
+		authHeader := c.GetHeader("Authorization") // Get the token from the header of the request (if any)
 		if authHeader == "" {
 			response := helper.ErrorsResponse(401, "Failed to process request", "No token found", nil)
 			c.AbortWithStatusJSON(http.StatusBadRequest, response)
 			return
 		}
-		token, err := jwtService.ValidateToken(authHeader) // Validate the token
-		if token.Valid {
-			claims := token.Claims.(jwt.MapClaims)             // Get the claims of the token
-			log.Println("Claim[user_id]: ", claims["user_id"]) // output the user_id
-			log.Println("Claim[issuer] :", claims["issuer"])   // output the issuer
-		} else {
-			log.Println(err)
+		principal, err := jwtService.ParsePrincipal(authHeader) // Validate the token once
+		if err != nil {
+			logging.With(c.Request.Context(), logger).Debug("invalid token", zap.Error(err))
 			response := helper.ErrorsResponse(401, "Token is not valid", err.Error(), nil)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
 		}
+		auth.SetPrincipal(c, principal) // Handlers read the caller with auth.FromContext
 	}
 }
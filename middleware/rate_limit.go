@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ratelimit"
+	"go.uber.org/zap"
+)
+
+//RateLimit limits the requests of a route group per user (when authenticated) or per client IP, return 429 when exceeded
+func RateLimit(group string, store ratelimit.Store, limit ratelimit.Limit, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The principal is set by AuthorizeJWT, so it must run before this middleware
+		key := fmt.Sprintf("%s:ip:%s", group, c.ClientIP())
+		if principal, ok := auth.FromContext(c); ok {
+			key = fmt.Sprintf("%s:user:%d", group, principal.UserID)
+		}
+
+		result, err := store.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			// Fail open, an unavailable store must not take the API down
+			logging.With(c.Request.Context(), logger).Error("rate limit store failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		// Send the standard RateLimit headers on every response
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			response := helper.ErrorsResponse(http.StatusTooManyRequests, "Too many requests", "Rate limit exceeded, retry later", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds formats a duration as a whole number of seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+)
+
+// RequestIDHeader is the header used to receive and send the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits the request IDs accepted from clients to safe, short values
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+//RequestID honours the X-Request-ID header of the request or creates a new one
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader) // Get the request ID from the header (if any)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		// Store the request ID in the request context for services and loggers
+		c.Request = c.Request.WithContext(logging.ContextWithRequestID(c.Request.Context(), requestID))
+
+		c.Header(RequestIDHeader, requestID) // Send the request ID back to the client
+		c.Next()
+	}
+}
+
+// newRequestID creates a random 128 bit request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/rand never returns an error on supported platforms
+	return hex.EncodeToString(b)
+}
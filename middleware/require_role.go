@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+)
+
+//RequireRole allows the request only when the authenticated user has the role, return 403 if not
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// AuthorizeJWT must run before this middleware
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			response := helper.ErrorsResponse(http.StatusUnauthorized, "Failed to process request", "No token found", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		if !principal.HasRole(role) {
+			response := helper.ErrorsResponse(http.StatusForbidden, "Forbidden", "This action requires the "+role+" role", helper.EmptyObject{})
+			c.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Currency and locale of the amounts created without them, changed once at start by SetDefaults
+var (
+	defaultCurrency = "USD"
+	defaultLocale   = language.AmericanEnglish
+)
+
+// Money is an amount in the minor unit of its currency (cents for USD), so prices are never rounded
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0" json:"amount" binding:"min=0"`                   // Amount in minor units, 1250 is 12.50 USD
+	Currency string `gorm:"type:char(3);not null" json:"currency" binding:"omitempty,currency"` // ISO 4217 code, the default currency when empty
+}
+
+// SetDefaults sets the currency of the amounts without currency and the locale of the formatted amounts
+func SetDefaults(currencyCode string, locale language.Tag) {
+	defaultCurrency = currencyCode
+	defaultLocale = locale
+}
+
+// DefaultCurrency returns the currency of the amounts created without currency
+func DefaultCurrency() string {
+	return defaultCurrency
+}
+
+// New returns the amount in minor units of the currency
+func New(amount int64, currencyCode string) Money {
+	return Money{Amount: amount, Currency: currencyCode}
+}
+
+// IsValidCurrency reports whether the code is an upper case ISO 4217 currency code
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 || strings.ToUpper(code) != code {
+		return false
+	}
+	_, err := currency.ParseISO(code)
+	return err == nil
+}
+
+// Scale returns the number of digits of the minor unit of the currency, 2 for USD and 0 for JPY
+func Scale(currencyCode string) int {
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
+
+// WithDefaultCurrency returns the amount in the default currency when it has no currency
+func (m Money) WithDefaultCurrency() Money {
+	if m.Currency == "" {
+		m.Currency = defaultCurrency
+	}
+	return m
+}
+
+// Major returns the amount in major units, 12.5 for 1250 USD cents
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(Scale(m.Currency))
+}
+
+// Format returns the amount with its currency symbol and the separators of the locale, "$ 12.50" in en-US and "€ 12,50" in de-DE
+func (m Money) Format(locale language.Tag) string {
+	unit, err := currency.ParseISO(m.Currency)
+	if err != nil {
+		return message.NewPrinter(locale).Sprint(number.Decimal(m.Major(), number.Scale(Scale(m.Currency))))
+	}
+	return message.NewPrinter(locale).Sprint(currency.Symbol(unit.Amount(m.Major())))
+}
+
+// String returns the amount formatted in the default locale
+func (m Money) String() string {
+	return m.Format(defaultLocale)
+}
+
+/*
+UnmarshalJSON reads the amount and the currency, or a bare number sent by the clients written before prices had
+a currency. A bare number is in whole units of the default currency, like the prices were then
+*/
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] != '{' {
+		var units int64
+		if err := json.Unmarshal(data, &units); err != nil {
+			return err
+		}
+		*m = New(units*int64(math.Pow10(Scale(defaultCurrency))), defaultCurrency)
+		return nil
+	}
+	type plain Money // without the methods, so it is decoded field by field
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+// MarshalJSON adds the amount formatted in the default locale to the amount and the currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Formatted string `json:"formatted"`
+	}{m.Amount, m.Currency, m.String()})
+}
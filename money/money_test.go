@@ -0,0 +1,49 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{"object", `{"amount": 1250, "currency": "EUR"}`, Money{Amount: 1250, Currency: "EUR"}, false},
+		{"object without currency", `{"amount": 1250}`, Money{Amount: 1250}, false},
+		{"bare number", `12`, Money{Amount: 1200, Currency: "USD"}, false},
+		{"null", `null`, Money{}, false},
+		{"fraction", `12.5`, Money{}, true},
+		{"string", `"12"`, Money{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("UnmarshalJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSONDefaultCurrency(t *testing.T) {
+	defer SetDefaults(defaultCurrency, defaultLocale)
+	SetDefaults("IDR", defaultLocale)
+
+	var book struct {
+		Price Money `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": 100000}`), &book); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Money{Amount: 100000, Currency: "IDR"}); book.Price != want {
+		t.Errorf("UnmarshalJSON() = %+v, want %+v", book.Price, want)
+	}
+}
@@ -0,0 +1,119 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeGateway keeps the payment intents in memory and authorizes every payment, used for development and tests.
+// The events are sent to the webhook URL like a real gateway would, or only kept when the URL is empty
+type fakeGateway struct {
+	mu         sync.Mutex
+	intents    map[string]Intent
+	events     []Event
+	webhookURL string
+	secret     string
+	client     *http.Client
+	logger     *zap.Logger
+}
+
+// NewFakeGateway method is used to create a PaymentGateway sending its events signed with the secret to the webhook URL
+func NewFakeGateway(webhookURL string, secret string, logger *zap.Logger) PaymentGateway {
+	return &fakeGateway{intents: make(map[string]Intent), webhookURL: webhookURL, secret: secret, client: &http.Client{Timeout: 10 * time.Second}, logger: logger}
+}
+
+// CreateIntent starts an authorized payment of the amount for the reference
+func (g *fakeGateway) CreateIntent(ctx context.Context, amount int64, currency string, reference string) (Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent := Intent{ID: "pi_" + randomID(), Amount: amount, Currency: currency, Status: IntentRequiresCapture, Reference: reference}
+	intent.ClientSecret = intent.ID + "_secret_" + randomID()
+	g.intents[intent.ID] = intent
+	return intent, nil
+}
+
+// Capture takes the money of the authorized intent and sends payment.succeeded
+func (g *fakeGateway) Capture(ctx context.Context, intentID string) (Intent, error) {
+	return g.transition(intentID, IntentRequiresCapture, IntentSucceeded, EventPaymentSucceeded)
+}
+
+// Refund gives the money of the captured intent back and sends payment.refunded
+func (g *fakeGateway) Refund(ctx context.Context, intentID string) (Intent, error) {
+	return g.transition(intentID, IntentSucceeded, IntentRefunded, EventPaymentRefunded)
+}
+
+// Events returns the events sent so far, oldest first
+func (g *fakeGateway) Events() []Event {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Event(nil), g.events...)
+}
+
+// transition moves the intent from one status to another and sends the event of the change
+func (g *fakeGateway) transition(intentID string, from string, to string, eventType string) (Intent, error) {
+	g.mu.Lock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		g.mu.Unlock()
+		return Intent{}, ErrNotFound
+	}
+	if intent.Status != from {
+		g.mu.Unlock()
+		return intent, ErrInvalidState
+	}
+	intent.Status = to
+	g.intents[intentID] = intent
+	event := Event{ID: "evt_" + randomID(), Type: eventType, IntentID: intentID, Amount: intent.Amount, CreatedAt: time.Now().UTC()}
+	g.events = append(g.events, event)
+	g.mu.Unlock()
+
+	if g.webhookURL != "" {
+		go g.deliver(event) // A real gateway calls the webhook after answering
+	}
+	return intent, nil
+}
+
+// deliver posts the signed event to the webhook URL, retrying a few times while it fails
+func (g *fakeGateway) deliver(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		g.logger.Error("Failed to encode payment event", zap.String("event_id", event.ID), zap.Error(err))
+		return
+	}
+	for attempt := 0; attempt < 3; attempt++ {
+		time.Sleep(time.Duration(attempt) * time.Second)
+		req, err := http.NewRequest(http.MethodPost, g.webhookURL, bytes.NewReader(body))
+		if err != nil {
+			g.logger.Error("Failed to deliver payment event", zap.String("event_id", event.ID), zap.String("url", g.webhookURL), zap.Error(err))
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(SignatureHeader, SignWebhook(g.secret, body, time.Now()))
+		resp, err := g.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("webhook answered %d", resp.StatusCode)
+		}
+		g.logger.Warn("Payment event delivery failed", zap.String("event_id", event.ID), zap.Int("attempt", attempt+1), zap.Error(err))
+	}
+	g.logger.Error("Failed to deliver payment event", zap.String("event_id", event.ID), zap.String("url", g.webhookURL))
+}
+
+// randomID returns 12 random bytes in hex
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when the gateway has no payment intent with the id
+var ErrNotFound = errors.New("payment intent not found")
+
+// ErrInvalidState is returned when the payment intent cannot be captured or refunded in its status
+var ErrInvalidState = errors.New("payment intent cannot do this in its status")
+
+// Statuses of a payment intent
+const (
+	IntentRequiresCapture = "requires_capture" // authorized, the money is taken on capture
+	IntentSucceeded       = "succeeded"        // captured
+	IntentRefunded        = "refunded"         // given back to the payer
+)
+
+// Types of the events sent to the webhook
+const (
+	EventPaymentSucceeded = "payment.succeeded" // the payment intent was captured
+	EventPaymentRefunded  = "payment.refunded"  // the payment intent was refunded
+)
+
+// Intent is a payment of an amount tracked by the gateway
+type Intent struct {
+	ID           string `json:"id"`
+	Amount       int64  `json:"amount"`        // In the smallest unit of the currency
+	Currency     string `json:"currency"`      // ISO 4217 code
+	Status       string `json:"status"`        // requires_capture, succeeded or refunded
+	Reference    string `json:"reference"`     // Reference of the application, the order of the payment
+	ClientSecret string `json:"client_secret"` // Secret the client confirms the payment with
+}
+
+// Event is a change of a payment intent sent by the gateway to the webhook
+type Event struct {
+	ID        string    `json:"id"`   // Unique id of the event, a retried delivery keeps it
+	Type      string    `json:"type"` // payment.succeeded or payment.refunded
+	IntentID  string    `json:"intent_id"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentGateway is a contract of what a payment provider should be able to do
+type PaymentGateway interface {
+	// CreateIntent starts a payment of the amount for the reference
+	CreateIntent(ctx context.Context, amount int64, currency string, reference string) (Intent, error)
+	// Capture takes the money of the authorized intent, the gateway then sends payment.succeeded
+	Capture(ctx context.Context, intentID string) (Intent, error)
+	// Refund gives the money of the captured intent back, the gateway then sends payment.refunded
+	Refund(ctx context.Context, intentID string) (Intent, error)
+}
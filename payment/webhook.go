@@ -0,0 +1,63 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned when a webhook body was not signed with the secret or its signature is too old
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SignatureHeader is the header carrying the signature of a webhook body
+const SignatureHeader = "Payment-Signature"
+
+// signatureTolerance is how old a signature may be, so a captured delivery cannot be replayed later
+const signatureTolerance = 5 * time.Minute
+
+// SignWebhook returns the signature header of the body sent at now, t=<unix time>,v1=<hex HMAC-SHA256 of "t.body">
+func SignWebhook(secret string, body []byte, now time.Time) string {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + webhookSignature(secret, timestamp, body)
+}
+
+// VerifyWebhook checks the signature header of the body and returns the event it holds
+func VerifyWebhook(secret string, header string, body []byte, now time.Time) (Event, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || now.Sub(time.Unix(sentAt, 0)).Abs() > signatureTolerance {
+		return Event{}, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(webhookSignature(secret, timestamp, body))) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// webhookSignature returns the hex HMAC-SHA256 of the timestamp and body
+func webhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
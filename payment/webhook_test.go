@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded","intent_id":"pi_1"}`)
+	sentAt := time.Unix(1700000000, 0)
+	header := SignWebhook(secret, body, sentAt)
+	signature := header[strings.Index(header, "v1=")+len("v1="):]
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr error
+	}{
+		{"valid", secret, header, body, sentAt, nil},
+		{"valid with spaces", secret, strings.ReplaceAll(header, ",", ", "), body, sentAt, nil},
+		{"within tolerance", secret, header, body, sentAt.Add(signatureTolerance), nil},
+		{"clock behind within tolerance", secret, header, body, sentAt.Add(-signatureTolerance), nil},
+		{"too old", secret, header, body, sentAt.Add(signatureTolerance + time.Second), ErrInvalidSignature},
+		{"from the future", secret, header, body, sentAt.Add(-signatureTolerance - time.Second), ErrInvalidSignature},
+		{"wrong secret", "other", header, body, sentAt, ErrInvalidSignature},
+		{"tampered body", secret, header, []byte(`{"id":"evt_1","type":"payment.refunded","intent_id":"pi_1"}`), sentAt, ErrInvalidSignature},
+		{"tampered timestamp", secret, "t=1700000001,v1=" + signature, body, sentAt, ErrInvalidSignature},
+		{"missing signature", secret, "t=1700000000", body, sentAt, ErrInvalidSignature},
+		{"missing timestamp", secret, "v1=" + signature, body, sentAt, ErrInvalidSignature},
+		{"invalid timestamp", secret, "t=abc,v1=" + signature, body, sentAt, ErrInvalidSignature},
+		{"empty header", secret, "", body, sentAt, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := VerifyWebhook(tt.secret, tt.header, tt.body, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (event.ID != "evt_1" || event.Type != EventPaymentSucceeded || event.IntentID != "pi_1") {
+				t.Errorf("VerifyWebhook() = %+v, want the event of the body", event)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookInvalidJSON(t *testing.T) {
+	body := []byte(`not json`)
+	now := time.Unix(1700000000, 0)
+	if _, err := VerifyWebhook("secret", SignWebhook("secret", body, now), body, now); err == nil || errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook() error = %v, want the JSON error", err)
+	}
+}
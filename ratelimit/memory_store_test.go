@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore().(*memoryStore)
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Period: 2 * time.Second}
+
+	steps := []struct {
+		name    string
+		key     string
+		advance time.Duration
+		allowed bool
+	}{
+		{"first", "a", 0, true},
+		{"second", "a", 0, true},
+		{"bucket empty", "a", 0, false},
+		{"other key", "b", 0, true},
+		{"refilled one token", "a", time.Second, true},
+		{"empty again", "a", 0, false},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		result, err := store.Take(ctx, step.key, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != step.allowed {
+			t.Errorf("%s: Take(%q) allowed = %v, want %v", step.name, step.key, result.Allowed, step.allowed)
+		}
+	}
+
+	// The buckets refilled completely are swept, at most once per minute
+	now = now.Add(time.Minute)
+	if _, err := store.Take(ctx, "c", limit); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.buckets["a"]; ok {
+		t.Error("the full bucket a is not swept")
+	}
+	if _, ok := store.buckets["c"]; !ok {
+		t.Error("the bucket c is missing")
+	}
+}
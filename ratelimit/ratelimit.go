@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is the number of requests allowed per period, the bucket refills continuously
+type Limit struct {
+	Requests int           // Bucket capacity (burst)
+	Period   time.Duration // Time to refill an empty bucket
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Bucket capacity
+	Remaining  int           // Whole tokens left in the bucket
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token is available (only when not allowed)
+}
+
+// Store is a contract of where token buckets are kept
+type Store interface {
+	// Take removes one token from the bucket identified by key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// ParseLimit parses a limit written as "<requests>/<period>", for example "60/1m"
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", s)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit requests %q", parts[0])
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit period %q", parts[1])
+	}
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+// rate returns the number of tokens added to the bucket per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// take refills the bucket up to now and removes one token if possible.
+// It returns the new token count and the result of the operation.
+func take(tokens float64, updatedAt time.Time, limit Limit, now time.Time) (float64, Result) {
+	capacity := float64(limit.Requests)
+
+	// Refill the bucket with the tokens earned since the last update
+	if elapsed := now.Sub(updatedAt).Seconds(); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed*limit.rate())
+	}
+
+	result := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / limit.rate())
+
+	return tokens, result
+}
+
+// seconds converts a float number of seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{"60/1m", Limit{Requests: 60, Period: time.Minute}, false},
+		{" 10 / 30s ", Limit{Requests: 10, Period: 30 * time.Second}, false},
+		{"60", Limit{}, true},
+		{"0/1m", Limit{}, true},
+		{"-1/1m", Limit{}, true},
+		{"abc/1m", Limit{}, true},
+		{"60/0s", Limit{}, true},
+		{"60/minute", Limit{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseLimit(%q) = %+v, %v, want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Requests: 10, Period: 10 * time.Second} // one token per second
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		wantTokens float64
+		want       Result
+	}{
+		{"full", 10, 0, 9, Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Second}},
+		{"last token", 1, 0, 0, Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+		{"empty", 0, 0, 0, Result{Limit: 10, Remaining: 0, Reset: 10 * time.Second, RetryAfter: time.Second}},
+		{"half a token", 0.5, 0, 0.5, Result{Limit: 10, Remaining: 0, Reset: 9500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}},
+		{"refilled", 0, 3 * time.Second, 2, Result{Allowed: true, Limit: 10, Remaining: 2, Reset: 8 * time.Second}},
+		{"refilled up to the capacity", 5, time.Hour, 9, Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Second}},
+		{"clock going back", 0, -time.Minute, 0, Result{Limit: 10, Remaining: 0, Reset: 10 * time.Second, RetryAfter: time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, got := take(tt.tokens, now.Add(-tt.elapsed), limit, now)
+			if tokens != tt.wantTokens || got != tt.want {
+				t.Errorf("take() = %v, %+v, want %v, %+v", tokens, got, tt.wantTokens, tt.want)
+			}
+		})
+	}
+}
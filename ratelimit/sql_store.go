@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sqlStore keeps the token buckets in the database so they are shared between instances
+type sqlStore struct {
+	connection *gorm.DB
+	mu         sync.Mutex
+	lastSweep  time.Time
+}
+
+// NewSQLStore method is used to create a new Store backed by the rate_limit_buckets table
+func NewSQLStore(connection *gorm.DB) Store {
+	return &sqlStore{connection: connection}
+}
+
+// Take removes one token from the bucket identified by key
+func (s *sqlStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var result Result
+
+	if err := s.sweep(ctx, time.Now()); err != nil {
+		return result, err
+	}
+
+	err := s.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// A new bucket starts full, keep the row when another request created it first
+		bucket := entity.RateLimitBucket{Key: key, Tokens: float64(limit.Requests), UpdatedAt: now, ExpiresAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bucket).Error; err != nil {
+			return err
+		}
+
+		// Lock the bucket row so concurrent requests cannot spend the same token
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).Take(&bucket).Error; err != nil {
+			return err
+		}
+
+		bucket.Tokens, result = take(bucket.Tokens, bucket.UpdatedAt, limit, now)
+
+		return tx.Model(&entity.RateLimitBucket{}).Where("`key` = ?", key).Updates(map[string]interface{}{
+			"tokens":     bucket.Tokens,
+			"updated_at": now,
+			"expires_at": now.Add(result.Reset),
+		}).Error
+	})
+
+	return result, err
+}
+
+// sweep deletes the buckets that have been refilled completely, at most once per minute. A full bucket is
+// the same as no bucket, rows written before buckets expired have no expiry and are deleted too
+func (s *sqlStore) sweep(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < time.Minute {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	return s.connection.WithContext(ctx).Where("expires_at IS NULL OR expires_at <= ?", now).Delete(&entity.RateLimitBucket{}).Error
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSQLStoreTake(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would open its own in-memory database
+	if err := db.AutoMigrate(&entity.RateLimitBucket{}); err != nil {
+		t.Fatal(err)
+	}
+	// Left by a previous process: a full bucket and a row written before buckets expired
+	db.Create(&entity.RateLimitBucket{Key: "expired", Tokens: 1, UpdatedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Minute)})
+	db.Exec("INSERT INTO rate_limit_buckets (`key`, tokens, updated_at) VALUES ('legacy', 1, ?)", time.Now())
+
+	store := NewSQLStore(db)
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: time.Hour}
+	for i, want := range []bool{true, true, true, false} {
+		result, err := store.Take(ctx, "k", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != want {
+			t.Errorf("Take() %d allowed = %v, want %v", i+1, result.Allowed, want)
+		}
+	}
+
+	var keys []string
+	db.Model(&entity.RateLimitBucket{}).Order("`key`").Pluck("key", &keys)
+	if len(keys) != 1 || keys[0] != "k" {
+		t.Errorf("buckets = %v, want only k after the sweep", keys)
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"gorm.io/gorm"
+)
+
+// AuditRepository is contract what auditRepository can do to db, the entries are written by the repositories of the audited records
+type AuditRepository interface {
+	GetByID(ctx context.Context, entryID uint64) entity.AuditEntry                           // get audit entry by entryID
+	GetByEntity(ctx context.Context, entityType string, entityID uint64) []entity.AuditEntry // get the history of a record, newest first
+	Search(ctx context.Context, f AuditFilter) []entity.AuditEntry                           // get the entries matching the filter, newest first
+}
+
+// AuditFilter narrows the entries returned by Search, empty fields are ignored
+type AuditFilter struct {
+	EntityType string     // book or user
+	EntityID   uint64     // changed record
+	ActorID    uint64     // user who made the changes
+	Action     string     // create, update, delete or revert
+	RequestID  string     // request of the changes
+	From       *time.Time // changes at or after the time
+	To         *time.Time // changes before the time
+	BeforeID   uint64     // entries older than this entry, to read the next page
+	Limit      int        // most entries returned
+}
+
+// auditConnection is a struct that implements connection to db with gorm
+type auditConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewAuditRepository method is used to create a new instance of auditConnection
+func NewAuditRepository(connection *gorm.DB) AuditRepository {
+	return &auditConnection{connection: connection}
+}
+
+// GetByID method is used to get audit entry by entryID
+func (db *auditConnection) GetByID(ctx context.Context, entryID uint64) entity.AuditEntry {
+	var entry entity.AuditEntry
+	db.connection.WithContext(ctx).Find(&entry, entryID)
+	return entry
+}
+
+// GetByEntity method is used to get every entry of the record, newest first
+func (db *auditConnection) GetByEntity(ctx context.Context, entityType string, entityID uint64) []entity.AuditEntry {
+	var entries []entity.AuditEntry
+	db.connection.WithContext(ctx).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("id DESC").Find(&entries)
+	return entries
+}
+
+// Search method is used to get the entries matching the filter, newest first
+func (db *auditConnection) Search(ctx context.Context, f AuditFilter) []entity.AuditEntry {
+	var entries []entity.AuditEntry
+	query := db.connection.WithContext(ctx)
+	if f.EntityType != "" {
+		query = query.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != 0 {
+		query = query.Where("entity_id = ?", f.EntityID)
+	}
+	if f.ActorID != 0 {
+		query = query.Where("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.RequestID != "" {
+		query = query.Where("request_id = ?", f.RequestID)
+	}
+	if f.From != nil {
+		query = query.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("created_at < ?", *f.To)
+	}
+	if f.BeforeID != 0 {
+		query = query.Where("id < ?", f.BeforeID)
+	}
+	query.Order("id DESC").Limit(f.Limit).Find(&entries)
+	return entries
+}
+
+// auditEntry returns the entry of the change of the record from before to after (nil when the record did not exist or no longer exists),
+// the actor and the request are read from the context of the transaction
+func auditEntry(tx *gorm.DB, entityType string, entityID uint64, action string, before entity.AuditSnapshot, after entity.AuditSnapshot) entity.AuditEntry {
+	ctx := tx.Statement.Context
+	entry := entity.AuditEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		RequestID:  logging.RequestIDFromContext(ctx),
+		Changes:    diffAudit(before, after),
+		Snapshot:   after,
+	}
+	if action == entity.AuditDelete {
+		entry.Snapshot = before
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		entry.ActorID = &principal.UserID
+	}
+	return entry
+}
+
+// insertAudit appends the entry in the transaction of its change, an update that changed none of the audited fields is not recorded
+func insertAudit(tx *gorm.DB, entry entity.AuditEntry) error {
+	if entry.Action == entity.AuditUpdate && len(entry.Changes) == 0 {
+		return nil
+	}
+	return tx.Create(&entry).Error
+}
+
+// diffAudit returns the fields whose value differs between the snapshots
+func diffAudit(before entity.AuditSnapshot, after entity.AuditSnapshot) entity.AuditChanges {
+	changes := entity.AuditChanges{}
+	for name, value := range after {
+		if old, ok := before[name]; !ok || !reflect.DeepEqual(old, value) {
+			changes[name] = entity.AuditChange{Old: before[name], New: value}
+		}
+	}
+	for name, old := range before {
+		if _, ok := after[name]; !ok {
+			changes[name] = entity.AuditChange{Old: old}
+		}
+	}
+	return changes
+}
+
+// auditSnapshot returns the fields with the values they get back from JSON, so a stored snapshot compares equal to a new one
+func auditSnapshot(fields map[string]interface{}) (entity.AuditSnapshot, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot entity.AuditSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// bookAudit returns the audited fields of the book, its authors, categories and tags are recorded by id so a revert can link them again
+func bookAudit(b entity.Book) (entity.AuditSnapshot, error) {
+	authorIDs := make([]uint64, len(b.Authors))
+	for i, a := range b.Authors {
+		authorIDs[i] = a.ID
+	}
+	categoryIDs := make([]uint64, len(b.Categories))
+	for i, c := range b.Categories {
+		categoryIDs[i] = c.ID
+	}
+	tagIDs := make([]uint64, len(b.Tags))
+	for i, t := range b.Tags {
+		tagIDs[i] = t.ID
+	}
+	return auditSnapshot(map[string]interface{}{
+		"title":          b.Title,
+		"author":         b.Author,
+		"author_ids":     authorIDs,
+		"category_ids":   categoryIDs,
+		"tag_ids":        tagIDs,
+		"description":    b.Description,
+		"isbn":           b.ISBN,
+		"price_amount":   b.Price.Amount,
+		"price_currency": b.Price.Currency,
+	})
+}
+
+// auditedBook preloads the associations of the book recorded by bookAudit
+func auditedBook(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Authors").Preload("Categories").Preload("Tags")
+}
+
+// snapshotIDs returns the ids recorded under the name, false when the snapshot was recorded before the name was audited
+func snapshotIDs(snapshot entity.AuditSnapshot, name string) ([]uint64, bool) {
+	values, ok := snapshot[name].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	ids := make([]uint64, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(float64); ok {
+			ids = append(ids, uint64(id))
+		}
+	}
+	return ids, true
+}
+
+// userAudit returns the audited fields of the user, the password is never written to the audit log
+func userAudit(u entity.User) (entity.AuditSnapshot, error) {
+	return auditSnapshot(map[string]interface{}{
+		"name":  u.Name,
+		"email": u.Email,
+		"role":  u.Role,
+	})
+}
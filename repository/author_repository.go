@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorRepository is contract what authorRepository can do to db
+type AuthorRepository interface {
+	GetAll(ctx context.Context) []entity.Author                                       // get all author from database
+	GetByID(ctx context.Context, authorID uint64) entity.Author                       // get author by authorID with their books
+	GetByIDs(ctx context.Context, authorIDs []uint64) []entity.Author                 // get authors by authorIDs
+	FindOrCreateByNames(ctx context.Context, names []string) ([]entity.Author, error) // get authors by name, create the missing ones
+	InsertAuthor(ctx context.Context, a entity.Author) entity.Author                  // insert author
+	UpdateAuthor(ctx context.Context, a entity.Author) (entity.Author, error)         // update author and the author names of their books
+	DeleteAuthor(ctx context.Context, a entity.Author)                                // delete author
+	CountBooks(ctx context.Context, authorID uint64) int64                            // count the books of the author
+	IsDuplicateName(ctx context.Context, name string, authorID uint64) (tx *gorm.DB)  // find another author with the same name key
+}
+
+// authorConnection is a struct that implements connection to db with gorm
+type authorConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewAuthorRepository method is used to create a new instance of authorConnection
+func NewAuthorRepository(connection *gorm.DB) AuthorRepository {
+	return &authorConnection{connection: connection}
+}
+
+// GetAll method is used to get all author from database
+func (db *authorConnection) GetAll(ctx context.Context) []entity.Author {
+	var authors []entity.Author                                 // create variable authors to store all author
+	db.connection.WithContext(ctx).Order("name").Find(&authors) // get all author sorted by name
+	return authors                                              // return all author
+}
+
+// GetByID method is used to get author by authorID with their public books
+func (db *authorConnection) GetByID(ctx context.Context, authorID uint64) entity.Author {
+	var author entity.Author // create variable author
+	db.connection.WithContext(ctx).Preload("Books", "books.visibility = ?", entity.VisibilityPublic).Preload("Books.User").Preload("Books.Authors").Preload("Books.Categories").Preload("Books.Tags").Find(&author, authorID)
+	return author // return author
+}
+
+// GetByIDs method is used to get authors by authorIDs
+func (db *authorConnection) GetByIDs(ctx context.Context, authorIDs []uint64) []entity.Author {
+	var authors []entity.Author // create variable authors
+	if len(authorIDs) == 0 {
+		return authors
+	}
+	db.connection.WithContext(ctx).Where("id IN ?", authorIDs).Find(&authors) // get authors by id
+	return authors                                                            // return authors
+}
+
+// FindOrCreateByNames method is used to get authors by name (in the given order), the missing ones are created
+func (db *authorConnection) FindOrCreateByNames(ctx context.Context, names []string) ([]entity.Author, error) {
+	authors := make([]entity.Author, len(names))
+	for i, name := range names {
+		authors[i] = entity.Author{Name: name}
+	}
+	if err := findOrCreateAuthors(db.connection.WithContext(ctx), authors); err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// InsertAuthor method is used to insert author
+func (db *authorConnection) InsertAuthor(ctx context.Context, a entity.Author) entity.Author {
+	a.NameKey = helper.AuthorNameKey(a.Name)  // set name key
+	db.connection.WithContext(ctx).Create(&a) // insert author
+	return a                                  // return author
+}
+
+// UpdateAuthor method is used to update author and the author names of their books
+func (db *authorConnection) UpdateAuthor(ctx context.Context, a entity.Author) (entity.Author, error) {
+	a.NameKey = helper.AuthorNameKey(a.Name) // set name key
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&a).Select("name", "name_key", "bio").Updates(&a).Error; err != nil {
+			return err
+		}
+
+		// Refresh the Author column of every book of the author
+		var books []entity.Book
+		err := tx.Preload("Authors").
+			Joins("JOIN book_authors ON book_authors.book_id = books.id").
+			Where("book_authors.author_id = ?", a.ID).
+			Find(&books).Error
+		if err != nil {
+			return err
+		}
+		for _, b := range books {
+			if err := tx.Model(&b).UpdateColumn("author", authorNames(b.Authors)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return a, err // return author
+}
+
+// DeleteAuthor method is used to delete author
+func (db *authorConnection) DeleteAuthor(ctx context.Context, a entity.Author) {
+	db.connection.WithContext(ctx).Select("Books").Delete(&a) // delete author and their book links
+}
+
+// CountBooks method is used to count the books of the author
+func (db *authorConnection) CountBooks(ctx context.Context, authorID uint64) int64 {
+	return db.connection.WithContext(ctx).Model(&entity.Author{ID: authorID}).Association("Books").Count()
+}
+
+// IsDuplicateName method is used to find another author with the same name key and return transaction to caller function
+func (db *authorConnection) IsDuplicateName(ctx context.Context, name string, authorID uint64) (tx *gorm.DB) {
+	var author entity.Author // get author from db
+	return db.connection.WithContext(ctx).Where("name_key = ? AND id <> ?", helper.AuthorNameKey(name), authorID).Take(&author)
+}
+
+/*
+findOrCreateAuthors sets the authors without ID to the author with the same name key, the missing ones are created.
+The books run it in their transaction, so a failed book write does not leave its new authors behind.
+*/
+func findOrCreateAuthors(tx *gorm.DB, authors []entity.Author) error {
+	for i := range authors {
+		if authors[i].ID != 0 {
+			continue
+		}
+		author := entity.Author{Name: authors[i].Name, NameKey: helper.AuthorNameKey(authors[i].Name)}
+
+		// Insert the author, keep the existing one when the name key is already known
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&author).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("name_key = ?", author.NameKey).Take(&authors[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorNames joins the names of the authors for the Author column of a book
+func authorNames(authors []entity.Author) string {
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// BookCopyRepository is contract what bookCopyRepository can do to db
+type BookCopyRepository interface {
+	GetByBook(ctx context.Context, bookID uint64, status string) []entity.BookCopy       // get all copy of the book, in the status when it is not empty
+	GetByID(ctx context.Context, copyID uint64) entity.BookCopy                          // get copy by copyID
+	InsertCopy(ctx context.Context, c entity.BookCopy) (entity.BookCopy, error)          // insert copy and refresh the counts of the book
+	UpdateCopy(ctx context.Context, c entity.BookCopy) (entity.BookCopy, error)          // update copy and refresh the counts of the book
+	DeleteCopy(ctx context.Context, c entity.BookCopy) error                             // delete copy and refresh the counts of the book
+	IsDuplicateBarcode(ctx context.Context, barcode string, copyID uint64) (tx *gorm.DB) // find another copy with the barcode
+}
+
+// bookCopyConnection is a struct that implements connection to db with gorm
+type bookCopyConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewBookCopyRepository method is used to create a new instance of bookCopyConnection
+func NewBookCopyRepository(connection *gorm.DB) BookCopyRepository {
+	return &bookCopyConnection{connection: connection}
+}
+
+// GetByBook method is used to get all copy of the book ordered by barcode, in the status when it is not empty
+func (db *bookCopyConnection) GetByBook(ctx context.Context, bookID uint64, status string) []entity.BookCopy {
+	var copies []entity.BookCopy // create variable copies
+	query := db.connection.WithContext(ctx).Where("book_id = ?", bookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Order("barcode").Find(&copies)
+	return copies // return copies
+}
+
+// GetByID method is used to get copy by copyID
+func (db *bookCopyConnection) GetByID(ctx context.Context, copyID uint64) entity.BookCopy {
+	var bookCopy entity.BookCopy                           // create variable bookCopy
+	db.connection.WithContext(ctx).Find(&bookCopy, copyID) // get copy by id
+	return bookCopy                                        // return bookCopy
+}
+
+// InsertCopy method is used to insert copy and refresh the counts of the book
+func (db *bookCopyConnection) InsertCopy(ctx context.Context, c entity.BookCopy) (entity.BookCopy, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, c.BookID); err != nil {
+			return err
+		}
+		if err := tx.Create(&c).Error; err != nil {
+			return err
+		}
+		return refreshCopies(tx, c.BookID)
+	})
+	if err != nil {
+		return c, err
+	}
+	return db.GetByID(ctx, c.ID), nil
+}
+
+// UpdateCopy method is used to update barcode, condition, location and status of the copy and refresh the counts of the book
+func (db *bookCopyConnection) UpdateCopy(ctx context.Context, c entity.BookCopy) (entity.BookCopy, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, c.BookID); err != nil {
+			return err
+		}
+		if err := tx.Model(&c).Select("barcode", "condition", "location", "status").Updates(&c).Error; err != nil {
+			return err
+		}
+		return refreshCopies(tx, c.BookID)
+	})
+	if err != nil {
+		return c, err
+	}
+	return db.GetByID(ctx, c.ID), nil
+}
+
+// DeleteCopy method is used to delete copy and refresh the counts of the book
+func (db *bookCopyConnection) DeleteCopy(ctx context.Context, c entity.BookCopy) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, c.BookID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&c).Error; err != nil {
+			return err
+		}
+		return refreshCopies(tx, c.BookID)
+	})
+}
+
+// IsDuplicateBarcode method is used to find another copy with the barcode and return transaction to caller function
+func (db *bookCopyConnection) IsDuplicateBarcode(ctx context.Context, barcode string, copyID uint64) (tx *gorm.DB) {
+	var bookCopy entity.BookCopy // get copy from db
+	return db.connection.WithContext(ctx).Where("barcode = ? AND id <> ?", barcode, copyID).Take(&bookCopy)
+}
+
+// refreshCopies recomputes the number of copies of the book by status from its copies
+func refreshCopies(tx *gorm.DB, bookID uint64) error {
+	return tx.Exec(`UPDATE books SET
+		copies_total = (SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id),
+		copies_available = (SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id AND book_copies.status = ?),
+		copies_in_use = (SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id AND book_copies.status = ?),
+		copies_lost = (SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id AND book_copies.status = ?),
+		copies_repair = (SELECT COUNT(*) FROM book_copies WHERE book_copies.book_id = books.id AND book_copies.status = ?)
+		WHERE id = ?`, entity.CopyAvailable, entity.CopyInUse, entity.CopyLost, entity.CopyRepair, bookID).Error
+}
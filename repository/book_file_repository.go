@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// BookFileRepository is contract what bookFileRepository can do to db
+type BookFileRepository interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.BookFile                // get all file of the book
+	GetByID(ctx context.Context, fileID uint64) entity.BookFile                    // get file by fileID
+	GetByFormat(ctx context.Context, bookID uint64, format string) entity.BookFile // get the file of the book in the format
+	SaveFile(ctx context.Context, f entity.BookFile) (entity.BookFile, error)      // insert the file, or replace the file of the book in the same format
+	DeleteFile(ctx context.Context, f entity.BookFile) error                       // delete file
+}
+
+// bookFileConnection is a struct that implements connection to db with gorm
+type bookFileConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewBookFileRepository method is used to create a new instance of bookFileConnection
+func NewBookFileRepository(connection *gorm.DB) BookFileRepository {
+	return &bookFileConnection{connection: connection}
+}
+
+// GetByBook method is used to get all file of the book
+func (db *bookFileConnection) GetByBook(ctx context.Context, bookID uint64) []entity.BookFile {
+	var files []entity.BookFile // create variable files
+	db.connection.WithContext(ctx).Where("book_id = ?", bookID).Order("format").Find(&files)
+	return files // return files
+}
+
+// GetByID method is used to get file by fileID
+func (db *bookFileConnection) GetByID(ctx context.Context, fileID uint64) entity.BookFile {
+	var file entity.BookFile                           // create variable file
+	db.connection.WithContext(ctx).Find(&file, fileID) // get file by id
+	return file                                        // return file
+}
+
+// GetByFormat method is used to get the file of the book in the format
+func (db *bookFileConnection) GetByFormat(ctx context.Context, bookID uint64, format string) entity.BookFile {
+	var file entity.BookFile // create variable file
+	db.connection.WithContext(ctx).Where("book_id = ? AND format = ?", bookID, format).Find(&file)
+	return file // return file
+}
+
+// SaveFile method is used to insert the file, or to replace the file of the book in the same format keeping its id
+func (db *bookFileConnection) SaveFile(ctx context.Context, f entity.BookFile) (entity.BookFile, error) {
+	if existing := db.GetByFormat(ctx, f.BookID, f.Format); existing.ID != 0 {
+		f.ID = existing.ID
+		f.CreatedAt = existing.CreatedAt
+	}
+	err := db.connection.WithContext(ctx).Save(&f).Error
+	return f, err
+}
+
+// DeleteFile method is used to delete file
+func (db *bookFileConnection) DeleteFile(ctx context.Context, f entity.BookFile) error {
+	return db.connection.WithContext(ctx).Delete(&f).Error
+}
@@ -1,17 +1,59 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
 	"gorm.io/gorm"
 )
 
 type BookRepository interface {
-	GetAll() []entity.Book                  // get all book from database
-	GetByID(bookID uint64) entity.Book      // get book by bookID
-	GetAllMyBook() []entity.Book            // get all book by userID
-	CreateMyBook(b entity.Book) entity.Book // create book by userID
-	UpdateMyBook(b entity.Book) entity.Book // update book by userID
-	DeleteMyBook(b entity.Book)             // delete book by userID
+	GetAll(ctx context.Context, f BookFilter) []entity.Book // get all book matching the filter from database
+	GetByID(ctx context.Context, bookID uint64) entity.Book // get book by bookID
+	// get all book of the user, their own books and the books shared with them
+	GetAllMyBook(ctx context.Context, userID uint64) []entity.Book
+	CreateMyBook(ctx context.Context, b entity.Book) (entity.Book, error) // create book by userID
+	// create the books in one transaction, a failing book does not roll back the others
+	CreateMyBooks(ctx context.Context, books []entity.Book) ([]entity.Book, []error)
+	UpdateMyBook(ctx context.Context, b entity.Book) (entity.Book, error) // update book by userID
+	DeleteMyBook(ctx context.Context, b entity.Book) error                // delete book by userID
+	// get all book with the isbn visible to the viewer
+	GetByISBN(ctx context.Context, isbn string, viewerID uint64) []entity.Book
+	GetByShareToken(ctx context.Context, token string) entity.Book // get the unlisted book of the share link
+	// get the role of the user on the book: owner, editor or viewer, empty when they have none
+	GetRole(ctx context.Context, bookID uint64, userID uint64) string
+	// check if the user has another book (than bookID) with the isbn
+	IsDuplicateISBN(ctx context.Context, userID uint64, isbn string, bookID uint64) (tx *gorm.DB)
+	UpdateCover(ctx context.Context, bookID uint64, cover entity.BookCover) error // replace the cover of the book
+	// read the books matching the filter batch by batch, in id order
+	Export(ctx context.Context, f ExportFilter, batchSize int, fn func(books []entity.Book) error) error
+	// put the book back to the version recorded by the entry of its history
+	RevertMyBook(ctx context.Context, bookID uint64, entry entity.AuditEntry) (entity.Book, error)
+}
+
+// ErrDuplicateISBN is returned when the (user_id, isbn) unique index rejects a book, the owner already has another book with the isbn
+var ErrDuplicateISBN = errors.New("the owner already has another book with this isbn")
+
+// BookFilter narrows the books returned by GetAll, empty fields are ignored
+type BookFilter struct {
+	CategoryPath string // path of a category, books of its subcategories are included
+	Tag          string // name of a tag
+	Sort         string // rating or reviews, by id when empty
+	ViewerID     uint64 // user reading the list, their own books are listed whatever their visibility, 0 when anonymous
+}
+
+// ExportFilter narrows the books read by Export, empty fields are ignored
+type ExportFilter struct {
+	UserID   uint64 // owner of the books, all owners when 0
+	Author   string // part of the author names
+	Title    string // part of the title
+	MinPrice *int64 // lowest price, inclusive
+	MaxPrice *int64 // highest price, inclusive
+	Currency string // currency of the prices, all currencies when empty
 }
 
 // Create bookConnection struct to implement connection to database
@@ -24,42 +66,388 @@ func NewBookRepository(connection *gorm.DB) BookRepository {
 	return &bookConnection{connection: connection}
 }
 
-// GetAll method is used to get all book from database
-func (db *bookConnection) GetAll() []entity.Book {
-	var books []entity.Book                    // create variable books to store all book
-	db.connection.Preload("User").Find(&books) // get all book and preload user from book
-	return books                               // return all book
+// GetAll method is used to get all book matching the filter from database
+func (db *bookConnection) GetAll(ctx context.Context, f BookFilter) []entity.Book {
+	var books []entity.Book        // create variable books to store all book
+	query := db.withRelations(ctx) // get all book and preload user from book
+	if f.CategoryPath != "" {
+		query = query.Where("books.id IN (?)", db.connection.
+			Table("book_categories").
+			Select("book_categories.book_id").
+			Joins("JOIN categories ON categories.id = book_categories.category_id").
+			Where("categories.path LIKE ?", f.CategoryPath+"%"))
+	}
+	if f.Tag != "" {
+		query = query.Where("books.id IN (?)", db.connection.
+			Table("book_tags").
+			Select("book_tags.book_id").
+			Joins("JOIN tags ON tags.id = book_tags.tag_id").
+			Where("tags.name = ?", f.Tag))
+	}
+	query = listedTo(query, f.ViewerID)
+	switch f.Sort {
+	case "rating":
+		query = query.Order("books.average_rating DESC, books.review_count DESC")
+	case "reviews":
+		query = query.Order("books.review_count DESC")
+	}
+	query.Order("books.id").Find(&books)
+	return books // return all book
 }
 
-// GetAllMyBook method is used to get all book by userID
-func (db *bookConnection) GetAllMyBook() []entity.Book {
-	var books []entity.Book                    // create variable books to store all book
-	db.connection.Preload("User").Find(&books) // get all book and preload user from book
-	return books                               // return all book
+// GetAllMyBook method is used to get all book owned by the user or shared with them, the public books of the others are left to GetAll
+func (db *bookConnection) GetAllMyBook(ctx context.Context, userID uint64) []entity.Book {
+	var books []entity.Book        // create variable books to store all book
+	query := db.withRelations(ctx) // preload user from book
+	query.Where("(books.user_id = ? OR books.id IN (?))", userID, sharedWith(query, userID)).Find(&books)
+	return books // return all book
 }
 
 // GetByID method is used to get book by bookID
-func (db *bookConnection) GetByID(bookID uint64) entity.Book {
-	var book entity.Book                              // create variable book
-	db.connection.Preload("User").Find(&book, bookID) // get data book from bookID and preload user from book
-	return book                                       // return book
+func (db *bookConnection) GetByID(ctx context.Context, bookID uint64) entity.Book {
+	var book entity.Book                      // create variable book
+	db.withRelations(ctx).Find(&book, bookID) // get data book from bookID and preload user from book
+	return book                               // return book
 }
 
 // CreateMyBook method is used to create book by userID
-func (db *bookConnection) CreateMyBook(b entity.Book) entity.Book {
-	db.connection.Save(&b)                 // save insert book
-	db.connection.Preload("User").Find(&b) // get data user from book
-	return b                               // return book
+func (db *bookConnection) CreateMyBook(ctx context.Context, b entity.Book) (entity.Book, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := linkAuthors(tx, &b); err != nil {
+			return err
+		}
+		if err := tx.Save(&b).Error; err != nil { // save insert book
+			return err
+		}
+		after, err := bookAudit(b)
+		if err != nil {
+			return err
+		}
+		return insertAudit(tx, auditEntry(tx, entity.AuditBook, b.ID, entity.AuditCreate, nil, after))
+	})
+	if err != nil {
+		return entity.Book{}, duplicateISBN(err)
+	}
+	db.withRelations(ctx).Find(&b) // get data user from book
+	return b, nil                  // return book
+}
+
+/*
+CreateMyBooks method is used to create the books in one transaction, every book is inserted after a savepoint
+so a failing book is rolled back alone, the errors are returned at the index of their book
+*/
+func (db *bookConnection) CreateMyBooks(ctx context.Context, books []entity.Book) ([]entity.Book, []error) {
+	errs := make([]error, len(books))
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range books {
+			if err := tx.SavePoint("book").Error; err != nil {
+				return err
+			}
+			err := linkAuthors(tx, &books[i])
+			if err == nil {
+				err = tx.Create(&books[i]).Error
+			}
+			var after entity.AuditSnapshot
+			if err == nil {
+				after, err = bookAudit(books[i])
+			}
+			if err == nil {
+				err = insertAudit(tx, auditEntry(tx, entity.AuditBook, books[i].ID, entity.AuditCreate, nil, after))
+			}
+			if err != nil {
+				errs[i] = duplicateISBN(err)
+				books[i].ID = 0
+				if err := tx.RollbackTo("book").Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Nothing was committed
+		for i := range books {
+			books[i].ID = 0
+			errs[i] = err
+		}
+	}
+	return books, errs
 }
 
 // UpdateMyBook method is used to update book by userID
-func (db *bookConnection) UpdateMyBook(b entity.Book) entity.Book {
-	db.connection.Save(&b)                 // save update book
-	db.connection.Preload("User").Find(&b) // get data user from book
-	return b                               // return book
+func (db *bookConnection) UpdateMyBook(ctx context.Context, b entity.Book) (entity.Book, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old entity.Book // the book before the update, for the audit log
+		if err := auditedBook(tx).Take(&old, b.ID).Error; err != nil {
+			return err
+		}
+		if err := linkAuthors(tx, &b); err != nil {
+			return err
+		}
+		if err := tx.Save(&b).Error; err != nil { // save update book
+			return err
+		}
+		if err := tx.Model(&b).Association("Authors").Replace(b.Authors); err != nil { // remove the previous authors
+			return err
+		}
+		if err := tx.Model(&b).Association("Categories").Replace(b.Categories); err != nil {
+			return err
+		}
+		if err := tx.Model(&b).Association("Tags").Replace(b.Tags); err != nil {
+			return err
+		}
+		before, err := bookAudit(old)
+		if err != nil {
+			return err
+		}
+		after, err := bookAudit(b)
+		if err != nil {
+			return err
+		}
+		return insertAudit(tx, auditEntry(tx, entity.AuditBook, b.ID, entity.AuditUpdate, before, after))
+	})
+	if err != nil {
+		return entity.Book{}, duplicateISBN(err)
+	}
+	db.withRelations(ctx).Find(&b) // get data user from book
+	return b, nil                  // return book
 }
 
 // DeleteMyBook method is used to delete book by userID
-func (db *bookConnection) DeleteMyBook(b entity.Book) {
-	db.connection.Delete(&b) // delete book
+func (db *bookConnection) DeleteMyBook(ctx context.Context, b entity.Book) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old entity.Book // the book as it was deleted, for the audit log
+		if err := auditedBook(tx).Take(&old, b.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Select("Authors", "Categories", "Tags").Delete(&b).Error; err != nil { // delete book and its author, category and tag links
+			return err
+		}
+		before, err := bookAudit(old)
+		if err != nil {
+			return err
+		}
+		return insertAudit(tx, auditEntry(tx, entity.AuditBook, b.ID, entity.AuditDelete, before, nil))
+	})
+}
+
+/*
+RevertMyBook method is used to put the audited fields of the book back to the version recorded by the entry of its history,
+the revert is itself recorded. The entry must be of the book and carry a snapshot
+*/
+func (db *bookConnection) RevertMyBook(ctx context.Context, bookID uint64, entry entity.AuditEntry) (entity.Book, error) {
+	var book entity.Book
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := auditedBook(tx).Take(&book, bookID).Error; err != nil {
+			return err
+		}
+		before, err := bookAudit(book)
+		if err != nil {
+			return err
+		}
+
+		version := entry.Snapshot
+		book.Title, _ = version["title"].(string)
+		book.Author, _ = version["author"].(string)
+		book.Description, _ = version["description"].(string)
+		book.ISBN = nil
+		if isbn, ok := version["isbn"].(string); ok {
+			book.ISBN = &isbn
+		}
+		amount, _ := version["price_amount"].(float64)
+		book.Price.Amount = int64(amount)
+		book.Price.Currency, _ = version["price_currency"].(string)
+		if err := revertBookLinks(tx, &book, version); err != nil {
+			return err
+		}
+
+		changes := map[string]interface{}{"title": book.Title, "author": book.Author, "description": book.Description, "isbn": book.ISBN, "price_amount": book.Price.Amount, "price_currency": book.Price.Currency}
+		if err := tx.Model(&entity.Book{}).Where("id = ?", bookID).Updates(changes).Error; err != nil {
+			return err
+		}
+		after, err := bookAudit(book)
+		if err != nil {
+			return err
+		}
+		revert := auditEntry(tx, entity.AuditBook, bookID, entity.AuditRevert, before, after)
+		revert.RevertedID = &entry.ID
+		return insertAudit(tx, revert)
+	})
+	if err != nil {
+		return book, duplicateISBN(err)
+	}
+	return db.GetByID(ctx, bookID), nil
+}
+
+/*
+revertBookLinks links the book again to the authors, categories and tags recorded by the version, those deleted since are
+skipped and the author names follow the authors. A version recorded before the links were audited leaves them unchanged
+*/
+func revertBookLinks(tx *gorm.DB, book *entity.Book, version entity.AuditSnapshot) error {
+	if ids, ok := snapshotIDs(version, "author_ids"); ok {
+		var found []entity.Author
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Find(&found).Error; err != nil {
+				return err
+			}
+		}
+		authors := make([]entity.Author, 0, len(found))
+		for _, id := range ids { // keep the order of the authors
+			for _, a := range found {
+				if a.ID == id {
+					authors = append(authors, a)
+				}
+			}
+		}
+		if err := tx.Model(book).Association("Authors").Replace(authors); err != nil {
+			return err
+		}
+		book.Authors = authors
+		if len(authors) > 0 {
+			book.Author = authorNames(authors)
+		}
+	}
+	if ids, ok := snapshotIDs(version, "category_ids"); ok {
+		categories := []entity.Category{}
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(book).Association("Categories").Replace(categories); err != nil {
+			return err
+		}
+		book.Categories = categories
+	}
+	if ids, ok := snapshotIDs(version, "tag_ids"); ok {
+		tags := []entity.Tag{}
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(book).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		book.Tags = tags
+	}
+	return nil
+}
+
+// GetByISBN method is used to get all book with the isbn visible to the viewer
+func (db *bookConnection) GetByISBN(ctx context.Context, isbn string, viewerID uint64) []entity.Book {
+	var books []entity.Book                                                        // create variable books to store all book
+	listedTo(db.withRelations(ctx), viewerID).Where("isbn = ?", isbn).Find(&books) // get book by isbn and preload user from book
+	return books                                                                   // return all book
+}
+
+// GetByShareToken method is used to get the unlisted book of the share link
+func (db *bookConnection) GetByShareToken(ctx context.Context, token string) entity.Book {
+	var book entity.Book
+	db.withRelations(ctx).Where("books.share_token = ? AND books.visibility = ?", token, entity.VisibilityUnlisted).Find(&book)
+	return book
+}
+
+// GetRole method is used to get the role of the user on the book, only accepted collaborators have a role
+func (db *bookConnection) GetRole(ctx context.Context, bookID uint64, userID uint64) string {
+	var book entity.Book
+	if db.connection.WithContext(ctx).Select("id", "user_id").Find(&book, bookID); book.ID == 0 {
+		return ""
+	}
+	if book.UserID == userID {
+		return entity.CollaboratorOwner
+	}
+	var collaborator entity.BookCollaborator
+	db.connection.WithContext(ctx).Where("book_id = ? AND user_id = ? AND status = ?", bookID, userID, entity.CollaboratorAccepted).Find(&collaborator)
+	return collaborator.Role
+}
+
+// listedTo keeps the books listed to the viewer: the public books, their own books and the books shared with them, only the public books when viewerID is 0
+func listedTo(query *gorm.DB, viewerID uint64) *gorm.DB {
+	return query.Where("(books.visibility = ? OR books.user_id = ? OR books.id IN (?))", entity.VisibilityPublic, viewerID, sharedWith(query, viewerID))
+}
+
+// sharedWith returns the subquery of the ids of the books shared with the user, they accepted the invitation
+func sharedWith(query *gorm.DB, userID uint64) *gorm.DB {
+	return query.
+		Session(&gorm.Session{NewDB: true}).
+		Table("book_collaborators").
+		Select("book_collaborators.book_id").
+		Where("book_collaborators.user_id = ? AND book_collaborators.status = ?", userID, entity.CollaboratorAccepted)
+}
+
+// linkAuthors finds or creates the authors of the book in the transaction and keeps their names for display
+func linkAuthors(tx *gorm.DB, b *entity.Book) error {
+	if len(b.Authors) == 0 {
+		return nil
+	}
+	if err := findOrCreateAuthors(tx, b.Authors); err != nil {
+		return err
+	}
+	b.Author = authorNames(b.Authors)
+	return nil
+}
+
+// duplicateISBN returns ErrDuplicateISBN for the duplicate key error of the (user_id, isbn) unique index, the other errors unchanged
+func duplicateISBN(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, "idx_books_user_isbn") {
+		return ErrDuplicateISBN
+	}
+	return err
+}
+
+// IsDuplicateISBN method is used to find another book of the user with the isbn and return transaction to caller function
+func (db *bookConnection) IsDuplicateISBN(ctx context.Context, userID uint64, isbn string, bookID uint64) (tx *gorm.DB) {
+	var book entity.Book // get book from db
+	return db.connection.WithContext(ctx).Where("user_id = ? AND isbn = ? AND id <> ?", userID, isbn, bookID).Take(&book)
+}
+
+// UpdateCover method is used to replace the cover of the book, the cover columns are not written by the other methods
+func (db *bookConnection) UpdateCover(ctx context.Context, bookID uint64, cover entity.BookCover) error {
+	return db.connection.WithContext(ctx).Table("books").Where("id = ?", bookID).Updates(map[string]interface{}{
+		"cover_key":           cover.Key,
+		"cover_url":           cover.URL,
+		"cover_medium_url":    cover.MediumURL,
+		"cover_thumbnail_url": cover.ThumbnailURL,
+	}).Error
+}
+
+/*
+Export method is used to read the books matching the filter in batches of batchSize, fn gets every batch
+in id order and its error stops the export, so the books are never all held in memory
+*/
+func (db *bookConnection) Export(ctx context.Context, f ExportFilter, batchSize int, fn func(books []entity.Book) error) error {
+	query := db.withRelations(ctx)
+	if f.UserID != 0 {
+		query = query.Where("books.user_id = ?", f.UserID)
+	}
+	if f.Author != "" {
+		query = query.Where("books.author LIKE ?", "%"+helper.EscapeLike(f.Author)+"%")
+	}
+	if f.Title != "" {
+		query = query.Where("books.title LIKE ?", "%"+helper.EscapeLike(f.Title)+"%")
+	}
+	if f.MinPrice != nil {
+		query = query.Where("books.price_amount >= ?", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		query = query.Where("books.price_amount <= ?", *f.MaxPrice)
+	}
+	if f.Currency != "" {
+		query = query.Where("books.price_currency = ?", f.Currency)
+	}
+
+	var books []entity.Book
+	return query.FindInBatches(&books, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(books)
+	}).Error
+}
+
+// withRelations returns a query preloading the user, authors, categories and tags of the books
+func (db *bookConnection) withRelations(ctx context.Context) *gorm.DB {
+	return db.connection.WithContext(ctx).Preload("User").Preload("Authors").Preload("Categories").Preload("Tags")
 }
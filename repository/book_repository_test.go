@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty in-memory database with the tables of the books
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // every connection would open its own in-memory database
+	if err := db.AutoMigrate(&entity.User{}, &entity.Book{}, &entity.Author{}, &entity.Category{}, &entity.Tag{}, &entity.Shelf{}, &entity.ShelfEntry{}, &entity.AuditEntry{}, &entity.BookCollaborator{}, &entity.Order{}, &entity.OrderItem{}, &entity.PaymentEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// visibilityFixture holds the users and the books of the visibility tests
+type visibilityFixture struct {
+	owner, collaborator, invitee, other uint64
+	books                               map[string]uint64 // id of the book of every visibility
+}
+
+// newVisibilityFixture creates a private, an unlisted and a public book of the owner, the private and unlisted
+// books are shared with the collaborator while the invitee has not accepted their invitation to the private book
+func newVisibilityFixture(t *testing.T, db *gorm.DB) visibilityFixture {
+	t.Helper()
+	var f visibilityFixture
+	for _, u := range []struct {
+		id    *uint64
+		email string
+	}{{&f.owner, "owner@test"}, {&f.collaborator, "collaborator@test"}, {&f.invitee, "invitee@test"}, {&f.other, "other@test"}} {
+		user := entity.User{Name: u.email, Email: u.email, Password: "secret"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		*u.id = user.ID
+	}
+	f.books = map[string]uint64{}
+	for _, visibility := range []string{entity.VisibilityPrivate, entity.VisibilityUnlisted, entity.VisibilityPublic} {
+		book := entity.Book{Title: visibility, Author: "author", Description: "description", UserID: f.owner, Visibility: visibility}
+		if err := db.Create(&book).Error; err != nil {
+			t.Fatal(err)
+		}
+		f.books[visibility] = book.ID
+	}
+	for _, c := range []entity.BookCollaborator{
+		{BookID: f.books[entity.VisibilityPrivate], UserID: f.collaborator, Role: entity.CollaboratorViewer, Status: entity.CollaboratorAccepted, InviterID: f.owner},
+		{BookID: f.books[entity.VisibilityUnlisted], UserID: f.collaborator, Role: entity.CollaboratorEditor, Status: entity.CollaboratorAccepted, InviterID: f.owner},
+		{BookID: f.books[entity.VisibilityPrivate], UserID: f.invitee, Role: entity.CollaboratorViewer, Status: entity.CollaboratorInvited, InviterID: f.owner},
+	} {
+		if err := db.Create(&c).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+func bookTitles(books []entity.Book) []string {
+	titles := []string{}
+	for _, b := range books {
+		titles = append(titles, b.Title)
+	}
+	sort.Strings(titles)
+	return titles
+}
+
+func TestBookRepositoryGetAllVisibility(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		viewerID uint64
+		want     []string
+		wantMine []string // owned and shared books only, the public books of the others are not theirs
+	}{
+		{"anonymous", 0, []string{entity.VisibilityPublic}, []string{}},
+		{"owner", f.owner, []string{entity.VisibilityPrivate, entity.VisibilityPublic, entity.VisibilityUnlisted}, []string{entity.VisibilityPrivate, entity.VisibilityPublic, entity.VisibilityUnlisted}},
+		{"collaborator", f.collaborator, []string{entity.VisibilityPrivate, entity.VisibilityPublic, entity.VisibilityUnlisted}, []string{entity.VisibilityPrivate, entity.VisibilityUnlisted}},
+		{"invitee", f.invitee, []string{entity.VisibilityPublic}, []string{}},
+		{"other user", f.other, []string{entity.VisibilityPublic}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bookTitles(repo.GetAll(ctx, BookFilter{ViewerID: tt.viewerID})); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll() = %v, want %v", got, tt.want)
+			}
+			if got := bookTitles(repo.GetAllMyBook(ctx, tt.viewerID)); !reflect.DeepEqual(got, tt.wantMine) {
+				t.Errorf("GetAllMyBook() = %v, want %v", got, tt.wantMine)
+			}
+		})
+	}
+}
+
+func TestBookRepositoryGetRole(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		visibility string
+		userID     uint64
+		want       string
+	}{
+		{"anonymous", entity.VisibilityPrivate, 0, ""},
+		{"owner", entity.VisibilityPrivate, f.owner, entity.CollaboratorOwner},
+		{"viewer", entity.VisibilityPrivate, f.collaborator, entity.CollaboratorViewer},
+		{"editor", entity.VisibilityUnlisted, f.collaborator, entity.CollaboratorEditor},
+		{"not shared", entity.VisibilityPublic, f.collaborator, ""},
+		{"invitee", entity.VisibilityPrivate, f.invitee, ""},
+		{"other user", entity.VisibilityPrivate, f.other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repo.GetRole(ctx, f.books[tt.visibility], tt.userID); got != tt.want {
+				t.Errorf("GetRole() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := repo.GetRole(ctx, 1000, f.owner); got != "" {
+		t.Errorf("GetRole() of a missing book = %q, want empty", got)
+	}
+}
+
+func TestDuplicateISBN(t *testing.T) {
+	isbnIndex := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-9780306406157' for key 'books.idx_books_user_isbn'"}
+	otherIndex := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'abc' for key 'books.idx_books_share_token'"}
+	other := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"isbn index", isbnIndex, ErrDuplicateISBN},
+		{"wrapped", fmt.Errorf("save: %w", isbnIndex), ErrDuplicateISBN},
+		{"other index", otherIndex, otherIndex},
+		{"other error", other, other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateISBN(tt.err); got != tt.want {
+				t.Errorf("duplicateISBN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepositoryDeleteMyBookRollback(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	// Without the audit table the delete cannot be recorded and is rolled back
+	if err := db.Migrator().DropTable(&entity.AuditEntry{}); err != nil {
+		t.Fatal(err)
+	}
+	bookID := f.books[entity.VisibilityPublic]
+	if err := repo.DeleteMyBook(ctx, entity.Book{ID: bookID}); err == nil {
+		t.Fatal("DeleteMyBook() error = nil, want the error of the audit")
+	}
+	if repo.GetByID(ctx, bookID).ID != bookID {
+		t.Error("the book is deleted after the rollback")
+	}
+}
+
+func TestBookRepositoryCreateMyBookAuthorsRollback(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	isbn := "9780306406157"
+	if _, err := repo.CreateMyBook(ctx, entity.Book{Title: "first", UserID: f.owner, ISBN: &isbn, Authors: []entity.Author{{Name: "First Author"}}}); err != nil {
+		t.Fatal(err)
+	}
+	// The second book has the same ISBN, its new author is rolled back with it
+	if _, err := repo.CreateMyBook(ctx, entity.Book{Title: "second", UserID: f.owner, ISBN: &isbn, Authors: []entity.Author{{Name: "Second Author"}}}); err == nil {
+		t.Fatal("CreateMyBook() error = nil, want the duplicate ISBN")
+	}
+	var names []string
+	db.Model(&entity.Author{}).Order("name").Pluck("name", &names)
+	if want := []string{"First Author"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("authors = %v, want %v", names, want)
+	}
+	var linked int64
+	db.Table("book_authors").Count(&linked)
+	if linked != 1 {
+		t.Errorf("book author links = %d, want 1", linked)
+	}
+}
+
+func TestBookRepositoryRevertMyBookLinks(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewBookRepository(db)
+	ctx := context.Background()
+
+	categories := []entity.Category{{Name: "Fiction", Slug: "fiction", Path: "/1/"}, {Name: "Poetry", Slug: "poetry", Path: "/2/"}}
+	tags := []entity.Tag{{Name: "classic"}, {Name: "modern"}}
+	db.Create(&categories)
+	db.Create(&tags)
+
+	book, err := repo.CreateMyBook(ctx, entity.Book{Title: "title", UserID: f.owner, Visibility: entity.VisibilityPublic,
+		Authors: []entity.Author{{Name: "Second Author"}, {Name: "First Author"}}, Categories: categories[:1], Tags: tags[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	book.Authors = []entity.Author{{Name: "Other Author"}}
+	book.Categories = categories[1:]
+	book.Tags = tags[1:]
+	if _, err := repo.UpdateMyBook(ctx, book); err != nil {
+		t.Fatal(err)
+	}
+
+	var created entity.AuditEntry
+	if err := db.Where("entity_id = ? AND action = ?", book.ID, entity.AuditCreate).Take(&created).Error; err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := repo.RevertMyBook(ctx, book.ID, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var authors []string
+	for _, a := range reverted.Authors {
+		authors = append(authors, a.Name)
+	}
+	if want := []string{"Second Author", "First Author"}; !reflect.DeepEqual(authors, want) {
+		t.Errorf("authors = %v, want %v", authors, want)
+	}
+	if reverted.Author != "Second Author, First Author" {
+		t.Errorf("author = %q, want the names of the authors", reverted.Author)
+	}
+	if len(reverted.Categories) != 1 || reverted.Categories[0].ID != categories[0].ID {
+		t.Errorf("categories = %+v, want %s", reverted.Categories, categories[0].Name)
+	}
+	if len(reverted.Tags) != 1 || reverted.Tags[0].ID != tags[0].ID {
+		t.Errorf("tags = %+v, want %s", reverted.Tags, tags[0].Name)
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// CartRepository is contract what cartRepository can do to db
+type CartRepository interface {
+	GetCart(ctx context.Context, userID uint64) []entity.CartItem                  // get all item of the cart of the user, oldest first
+	InsertItem(ctx context.Context, item entity.CartItem) (entity.CartItem, error) // insert item
+	DeleteItem(ctx context.Context, userID uint64, bookID uint64) error            // delete the book from the cart of the user
+	ClearCart(ctx context.Context, userID uint64) error                            // delete all item of the cart of the user
+	IsInCart(ctx context.Context, userID uint64, bookID uint64) (tx *gorm.DB)      // find the book in the cart of the user
+}
+
+// cartConnection is a struct that implements connection to db with gorm
+type cartConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewCartRepository method is used to create a new instance of cartConnection
+func NewCartRepository(connection *gorm.DB) CartRepository {
+	return &cartConnection{connection: connection}
+}
+
+// GetCart method is used to get all item of the cart of the user with their book, oldest first
+func (db *cartConnection) GetCart(ctx context.Context, userID uint64) []entity.CartItem {
+	var items []entity.CartItem // create variable items
+	db.connection.WithContext(ctx).Preload("Book").Where("user_id = ?", userID).Order("id").Find(&items)
+	return items // return items
+}
+
+// InsertItem method is used to insert item
+func (db *cartConnection) InsertItem(ctx context.Context, item entity.CartItem) (entity.CartItem, error) {
+	if err := db.connection.WithContext(ctx).Create(&item).Error; err != nil {
+		return item, err
+	}
+	db.connection.WithContext(ctx).Preload("Book").Find(&item, item.ID)
+	return item, nil
+}
+
+// DeleteItem method is used to delete the book from the cart of the user, gorm.ErrRecordNotFound when it is not in the cart
+func (db *cartConnection) DeleteItem(ctx context.Context, userID uint64, bookID uint64) error {
+	result := db.connection.WithContext(ctx).Where("user_id = ? AND book_id = ?", userID, bookID).Delete(&entity.CartItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClearCart method is used to delete all item of the cart of the user
+func (db *cartConnection) ClearCart(ctx context.Context, userID uint64) error {
+	return db.connection.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.CartItem{}).Error
+}
+
+// IsInCart method is used to find the book in the cart of the user and return transaction to caller function
+func (db *cartConnection) IsInCart(ctx context.Context, userID uint64, bookID uint64) (tx *gorm.DB) {
+	var item entity.CartItem // get item from db
+	return db.connection.WithContext(ctx).Where("user_id = ? AND book_id = ?", userID, bookID).Take(&item)
+}
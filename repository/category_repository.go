@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// ErrCategoryCycle is returned when a category is moved below itself or one of its subcategories
+var ErrCategoryCycle = errors.New("category cannot be moved below itself")
+
+// CategoryRepository is contract what categoryRepository can do to db
+type CategoryRepository interface {
+	GetAll(ctx context.Context) []entity.Category                                      // get all category with their book count
+	GetByID(ctx context.Context, categoryID uint64) entity.Category                    // get category by categoryID
+	GetBySlug(ctx context.Context, slug string) entity.Category                        // get category by slug
+	GetByIDs(ctx context.Context, categoryIDs []uint64) []entity.Category              // get categories by categoryIDs
+	InsertCategory(ctx context.Context, c entity.Category) (entity.Category, error)    // insert category below its parent
+	UpdateCategory(ctx context.Context, c entity.Category) entity.Category             // update name and slug of the category
+	MoveCategory(ctx context.Context, c entity.Category, parentID *uint64) error       // move the category and its subtree below parentID
+	DeleteCategory(ctx context.Context, c entity.Category) error                       // delete category and its book links
+	CountChildren(ctx context.Context, categoryID uint64) int64                        // count the direct subcategories
+	IsDuplicateSlug(ctx context.Context, slug string, categoryID uint64) (tx *gorm.DB) // find another category with the slug
+}
+
+// categoryConnection is a struct that implements connection to db with gorm
+type categoryConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewCategoryRepository method is used to create a new instance of categoryConnection
+func NewCategoryRepository(connection *gorm.DB) CategoryRepository {
+	return &categoryConnection{connection: connection}
+}
+
+// GetAll method is used to get all category sorted by path, the book count includes the books of the subcategories
+func (db *categoryConnection) GetAll(ctx context.Context) []entity.Category {
+	var categories []entity.Category // create variable categories to store all category
+	db.connection.WithContext(ctx).
+		Select("categories.*, (?) AS book_count", db.connection.
+			Table("book_categories").
+			Select("COUNT(DISTINCT book_categories.book_id)").
+			Joins("JOIN categories AS sub ON sub.id = book_categories.category_id").
+			Where("sub.path LIKE CONCAT(categories.path, '%')")).
+		Order("categories.path").
+		Find(&categories)
+	return categories // return all category
+}
+
+// GetByID method is used to get category by categoryID
+func (db *categoryConnection) GetByID(ctx context.Context, categoryID uint64) entity.Category {
+	var category entity.Category                               // create variable category
+	db.connection.WithContext(ctx).Find(&category, categoryID) // get category by id
+	return category                                            // return category
+}
+
+// GetBySlug method is used to get category by slug
+func (db *categoryConnection) GetBySlug(ctx context.Context, slug string) entity.Category {
+	var category entity.Category                                           // create variable category
+	db.connection.WithContext(ctx).Where("slug = ?", slug).Find(&category) // get category by slug
+	return category                                                        // return category
+}
+
+// GetByIDs method is used to get categories by categoryIDs
+func (db *categoryConnection) GetByIDs(ctx context.Context, categoryIDs []uint64) []entity.Category {
+	var categories []entity.Category // create variable categories
+	if len(categoryIDs) == 0 {
+		return categories
+	}
+	db.connection.WithContext(ctx).Where("id IN ?", categoryIDs).Find(&categories) // get categories by id
+	return categories                                                              // return categories
+}
+
+// InsertCategory method is used to insert category below its parent
+func (db *categoryConnection) InsertCategory(ctx context.Context, c entity.Category) (entity.Category, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parentPath := "/"
+		if c.ParentID != nil {
+			var parent entity.Category
+			if err := tx.Take(&parent, *c.ParentID).Error; err != nil {
+				return err
+			}
+			parentPath = parent.Path
+		}
+		c.Path = parentPath // the own id is only known after the insert
+		if err := tx.Create(&c).Error; err != nil {
+			return err
+		}
+		c.Path = fmt.Sprintf("%s%d/", parentPath, c.ID)
+		return tx.Model(&c).UpdateColumn("path", c.Path).Error
+	})
+	if err != nil {
+		return entity.Category{}, err
+	}
+	return c, nil // return category
+}
+
+// UpdateCategory method is used to update name and slug of the category
+func (db *categoryConnection) UpdateCategory(ctx context.Context, c entity.Category) entity.Category {
+	db.connection.WithContext(ctx).Model(&c).Select("name", "slug").Updates(&c) // update category
+	db.connection.WithContext(ctx).Find(&c)                                     // get the stored category
+	return c                                                                    // return category
+}
+
+// MoveCategory method is used to move the category below parentID (nil for the root), the paths of the whole subtree are rewritten
+func (db *categoryConnection) MoveCategory(ctx context.Context, c entity.Category, parentID *uint64) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Take(&c, c.ID).Error; err != nil {
+			return err
+		}
+		newPath := fmt.Sprintf("/%d/", c.ID)
+		if parentID != nil {
+			var parent entity.Category
+			if err := tx.Take(&parent, *parentID).Error; err != nil {
+				return err
+			}
+			if strings.HasPrefix(parent.Path, c.Path) {
+				return ErrCategoryCycle
+			}
+			newPath = fmt.Sprintf("%s%d/", parent.Path, c.ID)
+		}
+		if err := tx.Model(&c).UpdateColumn("parent_id", parentID).Error; err != nil {
+			return err
+		}
+
+		// Replace the path prefix of the category and every subcategory
+		var subtree []entity.Category
+		if err := tx.Where("path LIKE ?", c.Path+"%").Find(&subtree).Error; err != nil {
+			return err
+		}
+		for _, sub := range subtree {
+			path := newPath + strings.TrimPrefix(sub.Path, c.Path)
+			if err := tx.Model(&sub).UpdateColumn("path", path).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteCategory method is used to delete category and its book links
+func (db *categoryConnection) DeleteCategory(ctx context.Context, c entity.Category) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM book_categories WHERE category_id = ?", c.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&c).Error
+	})
+}
+
+// CountChildren method is used to count the direct subcategories
+func (db *categoryConnection) CountChildren(ctx context.Context, categoryID uint64) int64 {
+	var count int64
+	db.connection.WithContext(ctx).Model(&entity.Category{}).Where("parent_id = ?", categoryID).Count(&count)
+	return count
+}
+
+// IsDuplicateSlug method is used to find another category with the slug and return transaction to caller function
+func (db *categoryConnection) IsDuplicateSlug(ctx context.Context, slug string, categoryID uint64) (tx *gorm.DB) {
+	var category entity.Category // get category from db
+	return db.connection.WithContext(ctx).Where("slug = ? AND id <> ?", slug, categoryID).Take(&category)
+}
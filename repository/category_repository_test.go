@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+)
+
+func TestCategoryRepositoryInsertCategory(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	parent, err := repo.InsertCategory(ctx, entity.Category{Name: "Fiction", Slug: "fiction"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := repo.InsertCategory(ctx, entity.Category{Name: "Fantasy", Slug: "fantasy", ParentID: &parent.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("/%d/%d/", parent.ID, child.ID); child.Path != want {
+		t.Errorf("InsertCategory() path = %q, want %q", child.Path, want)
+	}
+
+	// An unknown parent rolls the category back
+	missing := uint64(1000)
+	category, err := repo.InsertCategory(ctx, entity.Category{Name: "Orphan", Slug: "orphan", ParentID: &missing})
+	if err == nil {
+		t.Fatal("InsertCategory() error = nil, want the error of the parent")
+	}
+	if category.ID != 0 {
+		t.Errorf("InsertCategory() id = %d, want 0", category.ID)
+	}
+	if repo.GetBySlug(ctx, "orphan").ID != 0 {
+		t.Error("the category is stored after the rollback")
+	}
+}
+
+func TestCategoryRepositoryDeleteCategoryRollback(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	category, err := repo.InsertCategory(ctx, entity.Category{Name: "Fiction", Slug: "fiction"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without the book links table the links cannot be removed and the delete is rolled back
+	if err := db.Migrator().DropTable("book_categories"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteCategory(ctx, category); err == nil {
+		t.Fatal("DeleteCategory() error = nil, want the error of the book links")
+	}
+	if repo.GetByID(ctx, category.ID).ID != category.ID {
+		t.Error("the category is deleted after the rollback")
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyCollaborator is returned when the owner invites a user who is already invited to or collaborating on the book
+var ErrAlreadyCollaborator = errors.New("the user is already a collaborator of the book")
+
+// ErrNoInvitation is returned when a user answers an invitation that is not waiting for their answer
+var ErrNoInvitation = errors.New("no invitation waiting for your answer")
+
+// ErrNotCollaborator is returned when a book is transferred to a user who has not accepted to collaborate on it
+var ErrNotCollaborator = errors.New("the book can only be transferred to a collaborator who accepted the invitation")
+
+// CollaboratorRepository is contract what collaboratorRepository can do to db, changes to the collaborators of a book lock the book row
+type CollaboratorRepository interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.BookCollaborator                                           // get the collaborators of the book with their user
+	GetByID(ctx context.Context, collaboratorID uint64) entity.BookCollaborator                                       // get collaborator by collaboratorID
+	GetInvitations(ctx context.Context, userID uint64) []entity.BookCollaborator                                      // get the invitations waiting for the answer of the user with their book
+	Invite(ctx context.Context, c entity.BookCollaborator) (entity.BookCollaborator, error)                           // invite the user, again if they declined
+	Answer(ctx context.Context, collaboratorID uint64, userID uint64, status string) (entity.BookCollaborator, error) // accept or decline the invitation of the user
+	Revoke(ctx context.Context, c entity.BookCollaborator) error                                                      // remove the collaborator or cancel the invitation
+	// give the book to an accepted collaborator, the previous owner becomes an editor
+	TransferBook(ctx context.Context, bookID uint64, newOwnerID uint64) error
+}
+
+// collaboratorConnection is a struct that implements connection to db with gorm
+type collaboratorConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewCollaboratorRepository method is used to create a new instance of collaboratorConnection
+func NewCollaboratorRepository(connection *gorm.DB) CollaboratorRepository {
+	return &collaboratorConnection{connection: connection}
+}
+
+// GetByBook method is used to get the collaborators of the book with their user, in invitation order
+func (db *collaboratorConnection) GetByBook(ctx context.Context, bookID uint64) []entity.BookCollaborator {
+	collaborators := []entity.BookCollaborator{}
+	db.connection.WithContext(ctx).Preload("User").Where("book_id = ?", bookID).Order("id").Find(&collaborators)
+	return collaborators
+}
+
+// GetByID method is used to get collaborator by collaboratorID
+func (db *collaboratorConnection) GetByID(ctx context.Context, collaboratorID uint64) entity.BookCollaborator {
+	var collaborator entity.BookCollaborator
+	db.connection.WithContext(ctx).Preload("User").Find(&collaborator, collaboratorID)
+	return collaborator
+}
+
+// GetInvitations method is used to get the invitations waiting for the answer of the user with their book, newest first
+func (db *collaboratorConnection) GetInvitations(ctx context.Context, userID uint64) []entity.BookCollaborator {
+	invitations := []entity.BookCollaborator{}
+	db.connection.WithContext(ctx).Preload("Book").Preload("Book.User").
+		Where("user_id = ? AND status = ?", userID, entity.CollaboratorInvited).Order("id DESC").Find(&invitations)
+	return invitations
+}
+
+// Invite method is used to invite the user to the book, a declined invitation is sent again with the new role
+func (db *collaboratorConnection) Invite(ctx context.Context, c entity.BookCollaborator) (entity.BookCollaborator, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, c.BookID); err != nil {
+			return err
+		}
+		var existing entity.BookCollaborator
+		if err := tx.Where("book_id = ? AND user_id = ?", c.BookID, c.UserID).Find(&existing).Error; err != nil {
+			return err
+		}
+		c.Status = entity.CollaboratorInvited
+		if existing.ID == 0 {
+			return tx.Create(&c).Error
+		}
+		if existing.Status != entity.CollaboratorDeclined {
+			return ErrAlreadyCollaborator
+		}
+		c.ID = existing.ID
+		c.CreatedAt = existing.CreatedAt
+		return tx.Save(&c).Error
+	})
+	if err != nil {
+		return c, err
+	}
+	return db.GetByID(ctx, c.ID), nil
+}
+
+// Answer method is used to accept or decline the invitation, only the invited user answers and only once
+func (db *collaboratorConnection) Answer(ctx context.Context, collaboratorID uint64, userID uint64, status string) (entity.BookCollaborator, error) {
+	result := db.connection.WithContext(ctx).Model(&entity.BookCollaborator{}).
+		Where("id = ? AND user_id = ? AND status = ?", collaboratorID, userID, entity.CollaboratorInvited).
+		Update("status", status)
+	if result.Error != nil {
+		return entity.BookCollaborator{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.BookCollaborator{}, ErrNoInvitation
+	}
+	return db.GetByID(ctx, collaboratorID), nil
+}
+
+// Revoke method is used to remove the collaborator from the book, whatever the status of their invitation
+func (db *collaboratorConnection) Revoke(ctx context.Context, c entity.BookCollaborator) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, c.BookID); err != nil {
+			return err
+		}
+		return tx.Delete(&c).Error
+	})
+}
+
+/*
+TransferBook method is used to give the book to a collaborator who accepted the invitation. The new owner stops being
+a collaborator and the previous owner becomes an editor, the change of owner is recorded in the history of the book
+*/
+func (db *collaboratorConnection) TransferBook(ctx context.Context, bookID uint64, newOwnerID uint64) error {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var book entity.Book
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := auditedBook(tx).Take(&book, bookID).Error; err != nil {
+			return err
+		}
+		var collaborator entity.BookCollaborator
+		if err := tx.Where("book_id = ? AND user_id = ? AND status = ?", bookID, newOwnerID, entity.CollaboratorAccepted).Find(&collaborator).Error; err != nil {
+			return err
+		}
+		if collaborator.ID == 0 {
+			return ErrNotCollaborator
+		}
+		if err := tx.Delete(&collaborator).Error; err != nil {
+			return err
+		}
+		previous := entity.BookCollaborator{BookID: bookID, UserID: book.UserID, Role: entity.CollaboratorEditor, Status: entity.CollaboratorAccepted, InviterID: newOwnerID}
+		if err := tx.Create(&previous).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Book{}).Where("id = ?", bookID).Update("user_id", newOwnerID).Error; err != nil {
+			return err
+		}
+		snapshot, err := bookAudit(book)
+		if err != nil {
+			return err
+		}
+		entry := auditEntry(tx, entity.AuditBook, bookID, entity.AuditUpdate, snapshot, snapshot)
+		entry.Changes["user_id"] = entity.AuditChange{Old: book.UserID, New: newOwnerID}
+		return insertAudit(tx, entry)
+	})
+	return duplicateISBN(err) // the new owner may have another book with the isbn
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// ImportJobRepository is contract what importJobConnection can do to db
+type ImportJobRepository interface {
+	GetByID(ctx context.Context, jobID uint64) entity.ImportJob                  // get job by jobID
+	InsertJob(ctx context.Context, j entity.ImportJob) (entity.ImportJob, error) // insert job
+	SaveJob(ctx context.Context, j entity.ImportJob) error                       // save the status, progress and report of the job
+	FailUnfinished(ctx context.Context, reason string) (int64, error)            // mark the pending and running jobs failed
+}
+
+// importJobConnection is a struct that implements connection to db with gorm
+type importJobConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewImportJobRepository method is used to create a new instance of importJobConnection
+func NewImportJobRepository(connection *gorm.DB) ImportJobRepository {
+	return &importJobConnection{connection: connection}
+}
+
+// GetByID method is used to get job by jobID
+func (db *importJobConnection) GetByID(ctx context.Context, jobID uint64) entity.ImportJob {
+	var job entity.ImportJob                         // create variable job
+	db.connection.WithContext(ctx).Find(&job, jobID) // get job by id
+	return job                                       // return job
+}
+
+// InsertJob method is used to insert job
+func (db *importJobConnection) InsertJob(ctx context.Context, j entity.ImportJob) (entity.ImportJob, error) {
+	err := db.connection.WithContext(ctx).Create(&j).Error
+	return j, err
+}
+
+// SaveJob method is used to save the status, progress and report of the job
+func (db *importJobConnection) SaveJob(ctx context.Context, j entity.ImportJob) error {
+	return db.connection.WithContext(ctx).Save(&j).Error
+}
+
+// FailUnfinished method is used to mark the pending and running jobs failed, their worker is gone after a restart
+func (db *importJobConnection) FailUnfinished(ctx context.Context, reason string) (int64, error) {
+	result := db.connection.WithContext(ctx).Model(&entity.ImportJob{}).
+		Where("status IN ?", []string{entity.ImportPending, entity.ImportRunning}).
+		Updates(map[string]interface{}{"status": entity.ImportFailed, "error": reason, "finished_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// ErrBookOnLoan is returned when a loan is approved for a book that is already lent
+var ErrBookOnLoan = errors.New("book is already on loan")
+
+// ErrLoanStatusChanged is returned when the loan is no longer in the status the change expects
+var ErrLoanStatusChanged = errors.New("loan status has changed")
+
+// LoanRepository is contract what loanRepository can do to db
+type LoanRepository interface {
+	GetByID(ctx context.Context, loanID uint64) entity.Loan                               // get loan by loanID
+	GetLoans(ctx context.Context, f LoanFilter) []entity.Loan                             // get all loan matching the filter, newest first
+	InsertLoan(ctx context.Context, l entity.Loan) (entity.Loan, error)                   // insert loan
+	ApproveLoan(ctx context.Context, loanID uint64, bookID uint64, dueAt time.Time) error // lend the book of the requested loan until dueAt
+	ChangeStatus(ctx context.Context, loanID uint64, from string, to string) error        // change the status of the loan if it still is from
+	ReturnLoan(ctx context.Context, loanID uint64, bookID uint64) error                   // record the return of the book of the active loan
+	HasOpenLoan(ctx context.Context, bookID uint64, borrowerID uint64) (tx *gorm.DB)      // find a requested or active loan of the book by the borrower
+}
+
+// LoanFilter narrows the loans returned by GetLoans, empty fields are ignored
+type LoanFilter struct {
+	BorrowerID uint64    // loans of the borrower
+	LenderID   uint64    // loans of the lender
+	BookID     uint64    // loans of the book
+	Status     string    // loans in the status
+	OverdueAt  time.Time // active loans due before this time
+}
+
+// loanConnection is a struct that implements connection to db with gorm
+type loanConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewLoanRepository method is used to create a new instance of loanConnection
+func NewLoanRepository(connection *gorm.DB) LoanRepository {
+	return &loanConnection{connection: connection}
+}
+
+// GetByID method is used to get loan by loanID with its book, borrower and lender
+func (db *loanConnection) GetByID(ctx context.Context, loanID uint64) entity.Loan {
+	var loan entity.Loan                      // create variable loan
+	db.withRelations(ctx).Find(&loan, loanID) // get loan by id
+	loan.Overdue = loan.IsOverdue(time.Now())
+	return loan // return loan
+}
+
+// GetLoans method is used to get all loan matching the filter, newest first
+func (db *loanConnection) GetLoans(ctx context.Context, f LoanFilter) []entity.Loan {
+	var loans []entity.Loan // create variable loans
+	query := db.withRelations(ctx)
+	if f.BorrowerID != 0 {
+		query = query.Where("borrower_id = ?", f.BorrowerID)
+	}
+	if f.LenderID != 0 {
+		query = query.Where("lender_id = ?", f.LenderID)
+	}
+	if f.BookID != 0 {
+		query = query.Where("book_id = ?", f.BookID)
+	}
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if !f.OverdueAt.IsZero() {
+		query = query.Where("status = ? AND due_at < ?", entity.LoanActive, f.OverdueAt)
+	}
+	query.Order("created_at DESC, id DESC").Find(&loans)
+
+	now := time.Now()
+	for i := range loans {
+		loans[i].Overdue = loans[i].IsOverdue(now)
+	}
+	return loans // return loans
+}
+
+// InsertLoan method is used to insert loan
+func (db *loanConnection) InsertLoan(ctx context.Context, l entity.Loan) (entity.Loan, error) {
+	if err := db.connection.WithContext(ctx).Create(&l).Error; err != nil {
+		return l, err
+	}
+	return db.GetByID(ctx, l.ID), nil
+}
+
+// ApproveLoan method is used to lend the book of the requested loan until dueAt, the book row is locked so it is lent once at a time
+func (db *loanConnection) ApproveLoan(ctx context.Context, loanID uint64, bookID uint64, dueAt time.Time) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		var lent int64
+		if err := tx.Model(&entity.Book{}).Where("id = ? AND active_loan_id IS NOT NULL", bookID).Count(&lent).Error; err != nil {
+			return err
+		}
+		if lent > 0 {
+			return ErrBookOnLoan
+		}
+		result := tx.Model(&entity.Loan{}).Where("id = ? AND status = ?", loanID, entity.LoanRequested).
+			Updates(map[string]interface{}{"status": entity.LoanActive, "due_at": dueAt, "approved_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLoanStatusChanged
+		}
+		return tx.Table("books").Where("id = ?", bookID).Update("active_loan_id", loanID).Error
+	})
+}
+
+// ChangeStatus method is used to change the status of the loan, only when it still is from so concurrent changes cannot both win
+func (db *loanConnection) ChangeStatus(ctx context.Context, loanID uint64, from string, to string) error {
+	result := db.connection.WithContext(ctx).Model(&entity.Loan{}).Where("id = ? AND status = ?", loanID, from).Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLoanStatusChanged
+	}
+	return nil
+}
+
+// ReturnLoan method is used to record the return of the book of the active loan, the book is available again
+func (db *loanConnection) ReturnLoan(ctx context.Context, loanID uint64, bookID uint64) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Loan{}).Where("id = ? AND status = ?", loanID, entity.LoanActive).
+			Updates(map[string]interface{}{"status": entity.LoanReturned, "returned_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLoanStatusChanged
+		}
+		return tx.Table("books").Where("id = ? AND active_loan_id = ?", bookID, loanID).Update("active_loan_id", nil).Error
+	})
+}
+
+// HasOpenLoan method is used to find a requested or active loan of the book by the borrower and return transaction to caller function
+func (db *loanConnection) HasOpenLoan(ctx context.Context, bookID uint64, borrowerID uint64) (tx *gorm.DB) {
+	var loan entity.Loan // get loan from db
+	return db.connection.WithContext(ctx).Where("book_id = ? AND borrower_id = ? AND status IN ?", bookID, borrowerID, []string{entity.LoanRequested, entity.LoanActive}).Take(&loan)
+}
+
+// withRelations returns a query preloading the book, borrower and lender of the loans
+func (db *loanConnection) withRelations(ctx context.Context) *gorm.DB {
+	return db.connection.WithContext(ctx).Preload("Book").Preload("Borrower").Preload("Lender")
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCartEmpty is returned when a user checks out an empty cart
+var ErrCartEmpty = errors.New("cart is empty")
+
+// ErrBookSold is returned when a book of the cart was sold to someone else
+var ErrBookSold = errors.New("book is already sold")
+
+// ErrOrderStatusChanged is returned when the order is no longer in the status the change expects
+var ErrOrderStatusChanged = errors.New("order status has changed")
+
+// OrderRepository is contract what orderRepository can do to db
+type OrderRepository interface {
+	GetByID(ctx context.Context, orderID uint64) entity.Order                                       // get order by orderID with its items
+	GetOrders(ctx context.Context, f OrderFilter) []entity.Order                                    // get all order matching the filter, newest first
+	Checkout(ctx context.Context, buyerID uint64) ([]entity.Order, error)                           // turn the cart of the buyer into one order per seller and currency
+	ChangeStatus(ctx context.Context, orderID uint64, from string, to string) (entity.Order, error) // change the status of the order if it still is from
+	Purchase(ctx context.Context, buyerID uint64, bookID uint64) (entity.Order, error)              // order the book alone
+	SetPaymentIntent(ctx context.Context, orderID uint64, intentID string) error                    // link the order to its payment intent
+	// apply the event of the payment gateway to the order of its intent, false when the event was already applied
+	ApplyPaymentEvent(ctx context.Context, e entity.PaymentEvent) (entity.Order, bool, error)
+}
+
+// OrderFilter narrows the orders returned by GetOrders, empty fields are ignored
+type OrderFilter struct {
+	BuyerID  uint64 // orders of the buyer
+	SellerID uint64 // orders of the seller
+	Status   string // orders in the status
+}
+
+// orderConnection is a struct that implements connection to db with gorm
+type orderConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewOrderRepository method is used to create a new instance of orderConnection
+func NewOrderRepository(connection *gorm.DB) OrderRepository {
+	return &orderConnection{connection: connection}
+}
+
+// GetByID method is used to get order by orderID with its items, buyer and seller
+func (db *orderConnection) GetByID(ctx context.Context, orderID uint64) entity.Order {
+	var order entity.Order                      // create variable order
+	db.withRelations(ctx).Find(&order, orderID) // get order by id
+	return order                                // return order
+}
+
+// GetOrders method is used to get all order matching the filter with their items, newest first
+func (db *orderConnection) GetOrders(ctx context.Context, f OrderFilter) []entity.Order {
+	var orders []entity.Order // create variable orders
+	query := db.withRelations(ctx)
+	if f.BuyerID != 0 {
+		query = query.Where("buyer_id = ?", f.BuyerID)
+	}
+	if f.SellerID != 0 {
+		query = query.Where("seller_id = ?", f.SellerID)
+	}
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	query.Order("created_at DESC, id DESC").Find(&orders)
+	return orders // return orders
+}
+
+// Checkout method is used to turn the cart of the buyer into one pending order per seller and currency in one transaction, the prices are copied into the items.
+// The books are locked in id order and reserved for the order, so a book cannot be sold twice
+func (db *orderConnection) Checkout(ctx context.Context, buyerID uint64) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var items []entity.CartItem
+		if err := tx.Where("user_id = ?", buyerID).Order("book_id").Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrCartEmpty
+		}
+
+		type orderKey struct {
+			sellerID uint64
+			currency string
+		}
+		byKey := make(map[orderKey]*entity.Order)
+		var keys []orderKey // order of the first book of each seller and currency
+		for _, item := range items {
+			book, err := lockBookForSale(tx, item.BookID)
+			if err != nil {
+				return err
+			}
+			key := orderKey{sellerID: book.UserID, currency: book.Price.Currency}
+			order, ok := byKey[key]
+			if !ok {
+				order = &entity.Order{BuyerID: buyerID, SellerID: book.UserID, Status: entity.OrderPending, Total: money.New(0, key.currency)}
+				byKey[key] = order
+				keys = append(keys, key)
+			}
+			order.Items = append(order.Items, orderItem(book))
+			order.Total.Amount += book.Price.Amount
+		}
+
+		for _, key := range keys {
+			order := byKey[key]
+			if err := insertOrder(tx, order); err != nil {
+				return err
+			}
+			orders = append(orders, *order)
+		}
+		return tx.Where("user_id = ?", buyerID).Delete(&entity.CartItem{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i] = db.GetByID(ctx, orders[i].ID)
+	}
+	return orders, nil
+}
+
+// ChangeStatus method is used to change the status of the order, only when it still is from so concurrent changes cannot both win.
+// Only an order without payment intent can be marked paid, the gateway pays the others. A cancelled order puts its books up for sale again
+func (db *orderConnection) ChangeStatus(ctx context.Context, orderID uint64, from string, to string) (entity.Order, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		changes := map[string]interface{}{"status": to}
+		switch to {
+		case entity.OrderPaid:
+			changes["paid_at"] = time.Now()
+		case entity.OrderShipped:
+			changes["shipped_at"] = time.Now()
+		case entity.OrderCancelled:
+			changes["cancelled_at"] = time.Now()
+		}
+		query := tx.Model(&entity.Order{}).Where("id = ? AND status = ?", orderID, from)
+		if to == entity.OrderPaid {
+			query = query.Where("payment_intent_id IS NULL")
+		}
+		result := query.Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderStatusChanged
+		}
+		if to == entity.OrderCancelled {
+			return tx.Table("books").Where("order_id = ?", orderID).Update("order_id", nil).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return db.GetByID(ctx, orderID), nil
+}
+
+// Purchase method is used to order the book alone, the book is locked and reserved for the order like at checkout
+func (db *orderConnection) Purchase(ctx context.Context, buyerID uint64, bookID uint64) (entity.Order, error) {
+	var order entity.Order
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		book, err := lockBookForSale(tx, bookID)
+		if err != nil {
+			return err
+		}
+		order = entity.Order{BuyerID: buyerID, SellerID: book.UserID, Status: entity.OrderPending, Total: book.Price, Items: []entity.OrderItem{orderItem(book)}}
+		return insertOrder(tx, &order)
+	})
+	if err != nil {
+		return order, err
+	}
+	return db.GetByID(ctx, order.ID), nil
+}
+
+// SetPaymentIntent method is used to link the order to its payment intent, so the events of the intent find the order
+func (db *orderConnection) SetPaymentIntent(ctx context.Context, orderID uint64, intentID string) error {
+	return db.connection.WithContext(ctx).Model(&entity.Order{}).Where("id = ?", orderID).Update("payment_intent_id", intentID).Error
+}
+
+// ApplyPaymentEvent method is used to apply the event of the payment gateway to the order of its intent in one transaction with the record of the event,
+// so a retried event is applied once. A succeeded payment marks the pending order paid, a refund marks the paid or shipped order refunded and puts its
+// books up for sale again, the refund of a payment captured after its order was cancelled leaves the order cancelled
+func (db *orderConnection) ApplyPaymentEvent(ctx context.Context, e entity.PaymentEvent) (entity.Order, bool, error) {
+	var order entity.Order
+	applied := false
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&e)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // already applied
+		}
+		applied = true
+
+		if err := tx.Where("payment_intent_id = ?", e.IntentID).Take(&order).Error; err != nil {
+			return err
+		}
+		switch e.Type {
+		case payment.EventPaymentSucceeded:
+			return tx.Model(&entity.Order{}).Where("id = ? AND status = ?", order.ID, entity.OrderPending).
+				Updates(map[string]interface{}{"status": entity.OrderPaid, "paid_at": time.Now()}).Error
+		case payment.EventPaymentRefunded:
+			result := tx.Model(&entity.Order{}).Where("id = ? AND status IN ?", order.ID, []string{entity.OrderPaid, entity.OrderShipped}).
+				Updates(map[string]interface{}{"status": entity.OrderRefunded, "refunded_at": time.Now()})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return tx.Table("books").Where("order_id = ?", order.ID).Update("order_id", nil).Error
+		}
+		return nil
+	})
+	if err != nil || !applied {
+		return order, applied, err
+	}
+	return db.GetByID(ctx, order.ID), true, nil
+}
+
+// lockBookForSale locks the row of the book and returns it, ErrBookSold when it is reserved for an order
+func lockBookForSale(tx *gorm.DB, bookID uint64) (entity.Book, error) {
+	var book entity.Book
+	if err := lockBook(tx, bookID); err != nil {
+		return book, err
+	}
+	if err := tx.Take(&book, bookID).Error; err != nil {
+		return book, err
+	}
+	if book.OrderID != nil {
+		return book, fmt.Errorf("%w: %s", ErrBookSold, book.Title)
+	}
+	return book, nil
+}
+
+// orderItem copies the book into an order item as it is sold
+func orderItem(book entity.Book) entity.OrderItem {
+	bookID := book.ID
+	return entity.OrderItem{BookID: &bookID, Title: book.Title, Author: book.Author, ISBN: book.ISBN, Price: book.Price}
+}
+
+// insertOrder inserts the order with its items and reserves their books for it
+func insertOrder(tx *gorm.DB, order *entity.Order) error {
+	if err := tx.Create(order).Error; err != nil {
+		return err
+	}
+	for _, item := range order.Items {
+		if err := tx.Table("books").Where("id = ?", *item.BookID).Update("order_id", order.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withRelations returns a query preloading the items, buyer and seller of the orders
+func (db *orderConnection) withRelations(ctx context.Context) *gorm.DB {
+	return db.connection.WithContext(ctx).Preload("Items").Preload("Buyer").Preload("Seller")
+}
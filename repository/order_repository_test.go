@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+)
+
+func TestOrderRepositoryApplyRefund(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{entity.OrderPending, entity.OrderPending},
+		{entity.OrderPaid, entity.OrderRefunded},
+		{entity.OrderShipped, entity.OrderRefunded},
+		{entity.OrderCancelled, entity.OrderCancelled},
+		{entity.OrderRefunded, entity.OrderRefunded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			db := newTestDB(t)
+			f := newVisibilityFixture(t, db)
+			repo := NewOrderRepository(db)
+			ctx := context.Background()
+
+			intentID := "pi_" + tt.status
+			order := entity.Order{BuyerID: f.other, SellerID: f.owner, Status: tt.status, Total: money.Money{Amount: 1000, Currency: "USD"}, PaymentIntentID: &intentID}
+			if err := db.Create(&order).Error; err != nil {
+				t.Fatal(err)
+			}
+			got, applied, err := repo.ApplyPaymentEvent(ctx, entity.PaymentEvent{ID: "evt_" + tt.status, Type: payment.EventPaymentRefunded, IntentID: intentID})
+			if err != nil || !applied {
+				t.Fatalf("ApplyPaymentEvent() applied = %v, error = %v", applied, err)
+			}
+			if got.Status != tt.want {
+				t.Errorf("status = %q, want %q", got.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderRepositoryChangeStatusToPaid(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewOrderRepository(db)
+	ctx := context.Background()
+
+	intentID := "pi_pending"
+	tests := []struct {
+		name     string
+		intentID *string
+		wantErr  error
+	}{
+		{"without intent", nil, nil},
+		{"with intent", &intentID, ErrOrderStatusChanged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := entity.Order{BuyerID: f.other, SellerID: f.owner, Status: entity.OrderPending, Total: money.Money{Amount: 1000, Currency: "USD"}, PaymentIntentID: tt.intentID}
+			if err := db.Create(&order).Error; err != nil {
+				t.Fatal(err)
+			}
+			_, err := repo.ChangeStatus(ctx, order.ID, entity.OrderPending, entity.OrderPaid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangeStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			var stored entity.Order
+			db.Take(&stored, order.ID)
+			if paid := stored.Status == entity.OrderPaid; paid != (tt.wantErr == nil) {
+				t.Errorf("status = %q, want paid %v", stored.Status, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// reportCacheEntry is a cached report
+type reportCacheEntry struct {
+	report    interface{}
+	expiresAt time.Time
+}
+
+// cachedReportRepository caches the reports of another ReportRepository in memory, reports are expensive and a few minutes old is recent enough
+type cachedReportRepository struct {
+	repository ReportRepository
+	ttl        time.Duration
+	mu         sync.Mutex
+	entries    map[string]reportCacheEntry
+}
+
+// NewCachedReportRepository method is used to wrap a ReportRepository with an in-memory cache
+func NewCachedReportRepository(repository ReportRepository, ttl time.Duration) ReportRepository {
+	return &cachedReportRepository{repository: repository, ttl: ttl, entries: make(map[string]reportCacheEntry)}
+}
+
+// BooksCreated returns the cached counts, or asks the wrapped repository
+func (r *cachedReportRepository) BooksCreated(ctx context.Context, bounds []time.Time) []PeriodCount {
+	return r.cached(fmt.Sprint("books-created", bounds), func() interface{} {
+		return r.repository.BooksCreated(ctx, bounds)
+	}).([]PeriodCount)
+}
+
+// ActiveUsers returns the cached counts, or asks the wrapped repository
+func (r *cachedReportRepository) ActiveUsers(ctx context.Context, bounds []time.Time) []PeriodCount {
+	return r.cached(fmt.Sprint("active-users", bounds), func() interface{} {
+		return r.repository.ActiveUsers(ctx, bounds)
+	}).([]PeriodCount)
+}
+
+// TopAuthors returns the cached authors, or asks the wrapped repository
+func (r *cachedReportRepository) TopAuthors(ctx context.Context, limit int) []AuthorReport {
+	return r.cached(fmt.Sprint("top-authors", limit), func() interface{} {
+		return r.repository.TopAuthors(ctx, limit)
+	}).([]AuthorReport)
+}
+
+// PriceDistribution returns the cached buckets, or asks the wrapped repository
+func (r *cachedReportRepository) PriceDistribution(ctx context.Context, currency string, width int64) []PriceBucket {
+	return r.cached(fmt.Sprint("price-distribution", currency, width), func() interface{} {
+		return r.repository.PriceDistribution(ctx, currency, width)
+	}).([]PriceBucket)
+}
+
+// BooksPerOwner returns the cached owners, or asks the wrapped repository
+func (r *cachedReportRepository) BooksPerOwner(ctx context.Context, limit int) []OwnerReport {
+	return r.cached(fmt.Sprint("books-per-owner", limit), func() interface{} {
+		return r.repository.BooksPerOwner(ctx, limit)
+	}).([]OwnerReport)
+}
+
+// cached returns the report of the key if it has not expired, otherwise builds it with fn and keeps it for the ttl
+func (r *cachedReportRepository) cached(key string, fn func() interface{}) interface{} {
+	now := time.Now()
+
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.report
+	}
+
+	report := fn()
+
+	r.mu.Lock()
+	r.sweep(now)
+	r.entries[key] = reportCacheEntry{report: report, expiresAt: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return report
+}
+
+// sweep removes the expired entries
+func (r *cachedReportRepository) sweep(now time.Time) {
+	for key, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, key)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"gorm.io/gorm"
+)
+
+// ReportRepository is contract what reportRepository can do to db
+type ReportRepository interface {
+	BooksCreated(ctx context.Context, bounds []time.Time) []PeriodCount                // count the books created in each period between the bounds
+	ActiveUsers(ctx context.Context, bounds []time.Time) []PeriodCount                 // count the users active in each period between the bounds
+	TopAuthors(ctx context.Context, limit int) []AuthorReport                          // get the authors with the most books
+	PriceDistribution(ctx context.Context, currency string, width int64) []PriceBucket // count the books in each price range of the width
+	BooksPerOwner(ctx context.Context, limit int) []OwnerReport                        // get the users owning the most books
+}
+
+// PeriodCount is the number of rows of a period, from Start included to End excluded
+type PeriodCount struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int64     `json:"count"`
+}
+
+// AuthorReport is an author with the number of its books and of their reviews
+type AuthorReport struct {
+	AuthorID uint64 `json:"author_id"`
+	Name     string `json:"name"`
+	Books    int64  `json:"books"`
+	Reviews  int64  `json:"reviews"`
+}
+
+// PriceBucket is the number of books priced from From included to To excluded
+type PriceBucket struct {
+	From  money.Money `json:"from"`
+	To    money.Money `json:"to"`
+	Books int64       `json:"books"`
+}
+
+// OwnerReport is a user with the number of books they own
+type OwnerReport struct {
+	UserID uint64 `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Books  int64  `json:"books"`
+}
+
+// activitySQL lists the user and the time of every action that makes a user active
+const activitySQL = `SELECT user_id, created_at FROM books
+	UNION ALL SELECT user_id, created_at FROM reviews
+	UNION ALL SELECT borrower_id, created_at FROM loans
+	UNION ALL SELECT buyer_id, created_at FROM orders`
+
+// reportConnection is a struct that implements connection to db with gorm
+type reportConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewReportRepository method is used to create a new instance of reportConnection
+func NewReportRepository(connection *gorm.DB) ReportRepository {
+	return &reportConnection{connection: connection}
+}
+
+// BooksCreated method is used to count the books created in each period, the books created before their time was recorded are left out
+func (db *reportConnection) BooksCreated(ctx context.Context, bounds []time.Time) []PeriodCount {
+	return db.countPerPeriod(ctx, bounds, "books", "COUNT(books.id)")
+}
+
+// ActiveUsers method is used to count the users who added a book, reviewed, borrowed or bought in each period
+func (db *reportConnection) ActiveUsers(ctx context.Context, bounds []time.Time) []PeriodCount {
+	return db.countPerPeriod(ctx, bounds, "("+activitySQL+") AS activity", "COUNT(DISTINCT activity.user_id)")
+}
+
+// TopAuthors method is used to get the authors with the most books, most reviewed first on a tie
+func (db *reportConnection) TopAuthors(ctx context.Context, limit int) []AuthorReport {
+	authors := []AuthorReport{}
+	db.connection.WithContext(ctx).Table("authors").
+		Select("authors.id AS author_id, authors.name, COUNT(books.id) AS books, COALESCE(SUM(books.review_count), 0) AS reviews").
+		Joins("JOIN book_authors ON book_authors.author_id = authors.id").
+		Joins("JOIN books ON books.id = book_authors.book_id").
+		Group("authors.id, authors.name").
+		Order("books DESC, reviews DESC, authors.id").
+		Limit(limit).
+		Scan(&authors)
+	return authors
+}
+
+// PriceDistribution method is used to count the books of every price range of the width, by currency then price
+func (db *reportConnection) PriceDistribution(ctx context.Context, currency string, width int64) []PriceBucket {
+	var rows []struct {
+		Currency string
+		Bucket   int64
+		Books    int64
+	}
+	query := db.connection.WithContext(ctx).Table("books").
+		Select("price_currency AS currency, FLOOR(price_amount / ?) AS bucket, COUNT(*) AS books", width)
+	if currency != "" {
+		query = query.Where("price_currency = ?", currency)
+	}
+	query.Group("currency, bucket").Order("currency, bucket").Scan(&rows)
+
+	buckets := make([]PriceBucket, len(rows))
+	for i, r := range rows {
+		buckets[i] = PriceBucket{From: money.New(r.Bucket*width, r.Currency), To: money.New((r.Bucket+1)*width, r.Currency), Books: r.Books}
+	}
+	return buckets
+}
+
+// BooksPerOwner method is used to get the users owning the most books, users without books are left out
+func (db *reportConnection) BooksPerOwner(ctx context.Context, limit int) []OwnerReport {
+	owners := []OwnerReport{}
+	db.connection.WithContext(ctx).Table("users").
+		Select("users.id AS user_id, users.name, users.email, COUNT(books.id) AS books").
+		Joins("JOIN books ON books.user_id = users.id").
+		Group("users.id, users.name, users.email").
+		Order("books DESC, users.id").
+		Limit(limit).
+		Scan(&owners)
+	return owners
+}
+
+/*
+countPerPeriod counts the rows of the source with a created_at in each period between the bounds. The periods are
+sent as a derived table, so they are cut in the time zone of the caller and the database groups the rows into them
+*/
+func (db *reportConnection) countPerPeriod(ctx context.Context, bounds []time.Time, source string, count string) []PeriodCount {
+	if len(bounds) < 2 {
+		return []PeriodCount{}
+	}
+	periods := make([]string, len(bounds)-1)
+	args := make([]interface{}, 0, 3*len(periods))
+	for i := range periods {
+		periods[i] = "SELECT ? AS period_index, ? AS period_start, ? AS period_end"
+		args = append(args, i, bounds[i], bounds[i+1])
+	}
+
+	var rows []struct {
+		PeriodIndex int
+		Count       int64
+	}
+	db.connection.WithContext(ctx).
+		Raw("SELECT periods.period_index, "+count+" AS count FROM ("+strings.Join(periods, " UNION ALL ")+") AS periods"+
+			" LEFT JOIN "+source+" ON created_at >= periods.period_start AND created_at < periods.period_end"+
+			" GROUP BY periods.period_index", args...).
+		Scan(&rows)
+
+	counts := make([]PeriodCount, len(bounds)-1)
+	for i := range counts {
+		counts[i] = PeriodCount{Start: bounds[i], End: bounds[i+1]}
+	}
+	for _, r := range rows {
+		if r.PeriodIndex >= 0 && r.PeriodIndex < len(counts) {
+			counts[r.PeriodIndex].Count = r.Count
+		}
+	}
+	return counts
+}
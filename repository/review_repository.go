@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewRepository is contract what reviewRepository can do to db
+type ReviewRepository interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.Review                      // get all review of the book, newest first
+	GetByID(ctx context.Context, reviewID uint64) entity.Review                        // get review by reviewID
+	InsertReview(ctx context.Context, r entity.Review) (entity.Review, error)          // insert review and refresh the rating of the book
+	UpdateReview(ctx context.Context, r entity.Review) (entity.Review, error)          // update review and refresh the rating of the book
+	DeleteReview(ctx context.Context, r entity.Review) error                           // delete review and refresh the rating of the book
+	IsDuplicateReview(ctx context.Context, bookID uint64, userID uint64) (tx *gorm.DB) // find the review of the user for the book
+}
+
+// reviewConnection is a struct that implements connection to db with gorm
+type reviewConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewReviewRepository method is used to create a new instance of reviewConnection
+func NewReviewRepository(connection *gorm.DB) ReviewRepository {
+	return &reviewConnection{connection: connection}
+}
+
+// GetByBook method is used to get all review of the book, newest first
+func (db *reviewConnection) GetByBook(ctx context.Context, bookID uint64) []entity.Review {
+	var reviews []entity.Review // create variable reviews
+	db.connection.WithContext(ctx).Preload("User").Where("book_id = ?", bookID).Order("created_at DESC, id DESC").Find(&reviews)
+	return reviews // return reviews
+}
+
+// GetByID method is used to get review by reviewID
+func (db *reviewConnection) GetByID(ctx context.Context, reviewID uint64) entity.Review {
+	var review entity.Review                                               // create variable review
+	db.connection.WithContext(ctx).Preload("User").Find(&review, reviewID) // get review by id
+	return review                                                          // return review
+}
+
+// InsertReview method is used to insert review and refresh the rating of the book
+func (db *reviewConnection) InsertReview(ctx context.Context, r entity.Review) (entity.Review, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, r.BookID); err != nil {
+			return err
+		}
+		if err := tx.Create(&r).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, r.BookID)
+	})
+	if err != nil {
+		return r, err
+	}
+	return db.GetByID(ctx, r.ID), nil
+}
+
+// UpdateReview method is used to update rating and text of the review and refresh the rating of the book
+func (db *reviewConnection) UpdateReview(ctx context.Context, r entity.Review) (entity.Review, error) {
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, r.BookID); err != nil {
+			return err
+		}
+		if err := tx.Model(&r).Select("rating", "text").Updates(&r).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, r.BookID)
+	})
+	if err != nil {
+		return r, err
+	}
+	return db.GetByID(ctx, r.ID), nil
+}
+
+// DeleteReview method is used to delete review and refresh the rating of the book
+func (db *reviewConnection) DeleteReview(ctx context.Context, r entity.Review) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, r.BookID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&r).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, r.BookID)
+	})
+}
+
+// IsDuplicateReview method is used to find the review of the user for the book and return transaction to caller function
+func (db *reviewConnection) IsDuplicateReview(ctx context.Context, bookID uint64, userID uint64) (tx *gorm.DB) {
+	var review entity.Review // get review from db
+	return db.connection.WithContext(ctx).Where("book_id = ? AND user_id = ?", bookID, userID).Take(&review)
+}
+
+// lockBook locks the row of the book until the end of the transaction, so the review writes of a book run one at a time
+func lockBook(tx *gorm.DB, bookID uint64) error {
+	var book entity.Book
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Take(&book, bookID).Error
+}
+
+// refreshRating recomputes the average rating and review count of the book from its reviews
+func refreshRating(tx *gorm.DB, bookID uint64) error {
+	return tx.Exec(`UPDATE books SET
+		review_count = (SELECT COUNT(*) FROM reviews WHERE reviews.book_id = books.id),
+		average_rating = (SELECT COALESCE(AVG(reviews.rating), 0) FROM reviews WHERE reviews.book_id = books.id)
+		WHERE id = ?`, bookID).Error
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// shelfBookCount selects the shelf columns with the number of books on the shelf
+const shelfBookCount = "shelves.*, (SELECT COUNT(*) FROM shelf_entries WHERE shelf_entries.shelf_id = shelves.id) AS book_count"
+
+// ShelfRepository is contract what shelfRepository can do to db
+type ShelfRepository interface {
+	GetByUser(ctx context.Context, userID uint64, publicOnly bool) []entity.Shelf                  // get the shelves of the user with their book count
+	GetByID(ctx context.Context, shelfID uint64, viewerID uint64) entity.Shelf                     // get shelf by shelfID with the books listed to the viewer
+	InsertShelf(ctx context.Context, s entity.Shelf) entity.Shelf                                  // insert shelf
+	UpdateShelf(ctx context.Context, s entity.Shelf) entity.Shelf                                  // update name and privacy of the shelf
+	DeleteShelf(ctx context.Context, s entity.Shelf)                                               // delete shelf and its entries
+	IsDuplicateName(ctx context.Context, userID uint64, name string, shelfID uint64) (tx *gorm.DB) // find another shelf of the user with the name
+	GetEntry(ctx context.Context, shelfID uint64, bookID uint64) entity.ShelfEntry                 // get the entry of the book on the shelf
+	InsertEntry(ctx context.Context, e entity.ShelfEntry) (entity.ShelfEntry, error)               // put a book on the shelf
+	UpdateEntry(ctx context.Context, e entity.ShelfEntry) entity.ShelfEntry                        // update progress and dates of the entry
+	DeleteEntry(ctx context.Context, e entity.ShelfEntry)                                          // remove a book from the shelf
+}
+
+// shelfConnection is a struct that implements connection to db with gorm
+type shelfConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewShelfRepository method is used to create a new instance of shelfConnection
+func NewShelfRepository(connection *gorm.DB) ShelfRepository {
+	return &shelfConnection{connection: connection}
+}
+
+// GetByUser method is used to get the shelves of the user with their book count, only the public ones when publicOnly is set
+func (db *shelfConnection) GetByUser(ctx context.Context, userID uint64, publicOnly bool) []entity.Shelf {
+	var shelves []entity.Shelf // create variable shelves
+	query := db.connection.WithContext(ctx).Select(shelfBookCount).Where("user_id = ?", userID)
+	if publicOnly {
+		query = query.Where("privacy = ?", entity.ShelfPublic)
+	}
+	query.Order("id").Find(&shelves)
+	return shelves // return shelves
+}
+
+// GetByID method is used to get shelf by shelfID with the books listed to the viewer, most recently added first
+func (db *shelfConnection) GetByID(ctx context.Context, shelfID uint64, viewerID uint64) entity.Shelf {
+	var shelf entity.Shelf // create variable shelf
+	db.connection.WithContext(ctx).
+		Select(shelfBookCount).
+		Preload("Entries", func(tx *gorm.DB) *gorm.DB {
+			// A book made private, or no longer shared with the viewer, stays on the shelf but is not shown
+			return tx.Where("shelf_entries.book_id IN (?)", listedTo(db.connection.Table("books").Select("books.id"), viewerID)).
+				Order("created_at DESC, id DESC")
+		}).
+		Preload("Entries.Book").
+		Preload("Entries.Book.User").
+		Preload("Entries.Book.Authors").
+		Find(&shelf, shelfID)
+	if shelf.ID != 0 {
+		shelf.BookCount = int64(len(shelf.Entries)) // count the books shown only
+	}
+	return shelf // return shelf
+}
+
+// InsertShelf method is used to insert shelf
+func (db *shelfConnection) InsertShelf(ctx context.Context, s entity.Shelf) entity.Shelf {
+	db.connection.WithContext(ctx).Create(&s) // insert shelf
+	return s                                  // return shelf
+}
+
+// UpdateShelf method is used to update name and privacy of the shelf
+func (db *shelfConnection) UpdateShelf(ctx context.Context, s entity.Shelf) entity.Shelf {
+	db.connection.WithContext(ctx).Model(&s).Select("name", "privacy").Updates(&s) // update shelf
+	return db.GetByID(ctx, s.ID, s.UserID)                                         // return the stored shelf
+}
+
+// DeleteShelf method is used to delete shelf and its entries
+func (db *shelfConnection) DeleteShelf(ctx context.Context, s entity.Shelf) {
+	db.connection.WithContext(ctx).Select("Entries").Delete(&s) // delete shelf and its entries
+}
+
+// IsDuplicateName method is used to find another shelf of the user with the name and return transaction to caller function
+func (db *shelfConnection) IsDuplicateName(ctx context.Context, userID uint64, name string, shelfID uint64) (tx *gorm.DB) {
+	var shelf entity.Shelf // get shelf from db
+	return db.connection.WithContext(ctx).Where("user_id = ? AND name = ? AND id <> ?", userID, name, shelfID).Take(&shelf)
+}
+
+// GetEntry method is used to get the entry of the book on the shelf
+func (db *shelfConnection) GetEntry(ctx context.Context, shelfID uint64, bookID uint64) entity.ShelfEntry {
+	var entry entity.ShelfEntry // create variable entry
+	db.connection.WithContext(ctx).Preload("Book").Where("shelf_id = ? AND book_id = ?", shelfID, bookID).Find(&entry)
+	return entry // return entry
+}
+
+// InsertEntry method is used to put a book on the shelf
+func (db *shelfConnection) InsertEntry(ctx context.Context, e entity.ShelfEntry) (entity.ShelfEntry, error) {
+	if err := db.connection.WithContext(ctx).Create(&e).Error; err != nil {
+		return e, err
+	}
+	return db.GetEntry(ctx, e.ShelfID, e.BookID), nil
+}
+
+// UpdateEntry method is used to update progress and dates of the entry
+func (db *shelfConnection) UpdateEntry(ctx context.Context, e entity.ShelfEntry) entity.ShelfEntry {
+	db.connection.WithContext(ctx).Model(&e).Select("progress", "started_at", "finished_at").Updates(&e) // update entry
+	return db.GetEntry(ctx, e.ShelfID, e.BookID)                                                         // return the stored entry
+}
+
+// DeleteEntry method is used to remove a book from the shelf
+func (db *shelfConnection) DeleteEntry(ctx context.Context, e entity.ShelfEntry) {
+	db.connection.WithContext(ctx).Delete(&e) // delete entry
+}
+
+// InsertDefaultShelves method is used to create the shelves every user starts with
+func InsertDefaultShelves(db *gorm.DB, userID uint64) error {
+	shelves := make([]entity.Shelf, len(entity.DefaultShelves))
+	for i, name := range entity.DefaultShelves {
+		shelves[i] = entity.Shelf{UserID: userID, Name: name, Privacy: entity.ShelfPrivate}
+	}
+	return db.Create(&shelves).Error
+}
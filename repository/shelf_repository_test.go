@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+)
+
+func TestShelfRepositoryGetByIDVisibility(t *testing.T) {
+	db := newTestDB(t)
+	f := newVisibilityFixture(t, db)
+	repo := NewShelfRepository(db)
+	ctx := context.Background()
+
+	// The collaborator shelved every book of the owner
+	shelf := repo.InsertShelf(ctx, entity.Shelf{UserID: f.collaborator, Name: "Favorites", Privacy: entity.ShelfPublic})
+	for _, bookID := range f.books {
+		if _, err := repo.InsertEntry(ctx, entity.ShelfEntry{ShelfID: shelf.ID, BookID: bookID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		viewerID uint64
+		want     []string
+	}{
+		{"anonymous", 0, []string{entity.VisibilityPublic}},
+		{"collaborator", f.collaborator, []string{entity.VisibilityPrivate, entity.VisibilityPublic, entity.VisibilityUnlisted}},
+		{"other user", f.other, []string{entity.VisibilityPublic}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repo.GetByID(ctx, shelf.ID, tt.viewerID)
+			var books []entity.Book
+			for _, e := range got.Entries {
+				books = append(books, *e.Book)
+			}
+			if titles := bookTitles(books); !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("GetByID() books = %v, want %v", titles, tt.want)
+			}
+			if got.BookCount != int64(len(tt.want)) {
+				t.Errorf("GetByID() book count = %d, want %d", got.BookCount, len(tt.want))
+			}
+		})
+	}
+
+	// Once the private book is no longer shared, its entry is hidden from the collaborator too
+	db.Where("book_id = ? AND user_id = ?", f.books[entity.VisibilityPrivate], f.collaborator).Delete(&entity.BookCollaborator{})
+	got := repo.GetByID(ctx, shelf.ID, f.collaborator)
+	if len(got.Entries) != 2 {
+		t.Errorf("GetByID() after revoke has %d entries, want 2", len(got.Entries))
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository is contract what tagRepository can do to db
+type TagRepository interface {
+	Search(ctx context.Context, prefix string, limit int) []entity.Tag    // get the most used tags starting with prefix
+	FindOrCreateByNames(ctx context.Context, names []string) []entity.Tag // get tags by name, create the missing ones
+}
+
+// tagConnection is a struct that implements connection to db with gorm
+type tagConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewTagRepository method is used to create a new instance of tagConnection
+func NewTagRepository(connection *gorm.DB) TagRepository {
+	return &tagConnection{connection: connection}
+}
+
+// Search method is used to get the most used tags starting with prefix
+func (db *tagConnection) Search(ctx context.Context, prefix string, limit int) []entity.Tag {
+	var tags []entity.Tag // create variable tags
+	db.connection.WithContext(ctx).
+		Select("tags.*, COUNT(book_tags.book_id) AS book_count").
+		Joins("LEFT JOIN book_tags ON book_tags.tag_id = tags.id").
+		Where("tags.name LIKE ?", helper.EscapeLike(prefix)+"%").
+		Group("tags.id").
+		Order("book_count DESC, tags.name").
+		Limit(limit).
+		Find(&tags)
+	return tags // return tags
+}
+
+// FindOrCreateByNames method is used to get tags by name (in the given order), the missing ones are created
+func (db *tagConnection) FindOrCreateByNames(ctx context.Context, names []string) []entity.Tag {
+	tags := make([]entity.Tag, 0, len(names))
+	for _, name := range names {
+		tag := entity.Tag{Name: name}
+
+		// Insert the tag, keep the existing one when the name is already known
+		db.connection.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tag)
+		db.connection.WithContext(ctx).Where("name = ?", tag.Name).Take(&tag)
+
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"log"
 
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -11,22 +13,22 @@ import (
 //UserRepository is contract what userRepository can do to db
 type UserRepository interface {
 	//InsertUser is insert user to db
-	InsertUser(user entity.User) entity.User
+	InsertUser(ctx context.Context, user entity.User) (entity.User, error)
 
 	//UpdateUser is update user to db
-	UpdateUser(user entity.User) entity.User
+	UpdateUser(ctx context.Context, user entity.User) (entity.User, error)
 
 	//VerifyCredential is verify user credential
-	VerifyCredential(email string, password string) interface{}
+	VerifyCredential(ctx context.Context, email string, password string) interface{}
 
 	//IsDuplicateEmail is check duplicate email
-	IsDuplicateEmail(email string) (tx *gorm.DB)
+	IsDuplicateEmail(ctx context.Context, email string) (tx *gorm.DB)
 
 	//FindByEmail is find user by email
-	FindByEmail(email string) entity.User
+	FindByEmail(ctx context.Context, email string) entity.User
 
 	//ProfileUser is find user by id
-	ProfileUser(userID int64) entity.User
+	ProfileUser(ctx context.Context, userID int64) entity.User
 }
 
 //userConnection is a struct that implements connection to db with gorm
@@ -44,29 +46,68 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 // CreateUser is insert user to db and return user entity to caller function
-func (db *userConnection) InsertUser(user entity.User) entity.User {
-	user.Password = hashAndSalt([]byte(user.Password)) //hash password
-	db.connection.Save(&user)                          //save user to db
-	return user
+func (db *userConnection) InsertUser(ctx context.Context, user entity.User) (entity.User, error) {
+	user.Password = hashAndSalt(ctx, []byte(user.Password)) //hash password
+	if user.Role == "" {
+		user.Role = entity.RoleUser //new users get the default role
+	}
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil { //save user to db
+			return err
+		}
+		if err := InsertDefaultShelves(tx, user.ID); err != nil { //every user starts with the default shelves
+			return err
+		}
+		after, err := userAudit(user)
+		if err != nil {
+			return err
+		}
+		return insertAudit(tx, auditEntry(tx, entity.AuditUser, user.ID, entity.AuditCreate, nil, after))
+	})
+	if err != nil {
+		return entity.User{}, err //the user was rolled back
+	}
+	return user, nil
 }
 
 // UpdateUser is update user to db and return user entity to caller function
-func (db *userConnection) UpdateUser(user entity.User) entity.User {
+func (db *userConnection) UpdateUser(ctx context.Context, user entity.User) (entity.User, error) {
+	var tempUser entity.User                                //get user from db
+	db.connection.WithContext(ctx).Find(&tempUser, user.ID) //find user by id
 	if user.Password != "" {
-		user.Password = hashAndSalt([]byte(user.Password)) //hash password
+		user.Password = hashAndSalt(ctx, []byte(user.Password)) //hash password
 	} else {
-		var tempUser entity.User               //get user from db
-		db.connection.Find(&tempUser, user.ID) //find user by id
-		user.Password = tempUser.Password      //set password to user
+		user.Password = tempUser.Password //set password to user
 	}
-	db.connection.Save(&user) //save user to db
-	return user
+	user.Role = tempUser.Role //the role cannot be changed from the profile
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil { //save user to db
+			return err
+		}
+		before, err := userAudit(tempUser)
+		if err != nil {
+			return err
+		}
+		after, err := userAudit(user)
+		if err != nil {
+			return err
+		}
+		entry := auditEntry(tx, entity.AuditUser, user.ID, entity.AuditUpdate, before, after)
+		if user.Password != tempUser.Password {
+			entry.Changes["password"] = entity.AuditChange{Old: entity.AuditRedacted, New: entity.AuditRedacted} //record that it changed, never the hash
+		}
+		return insertAudit(tx, entry)
+	})
+	if err != nil {
+		return tempUser, err //the user is unchanged
+	}
+	return user, nil
 }
 
 // VerifyCredential is verify user credential and return user entity to caller function if credential is correct or return nil if credential is incorrect
-func (db *userConnection) VerifyCredential(email string, password string) interface{} {
+func (db *userConnection) VerifyCredential(ctx context.Context, email string, password string) interface{} {
 	var user entity.User
-	res := db.connection.Where("email = ?", email).Take(&user)
+	res := db.connection.WithContext(ctx).Where("email = ?", email).Take(&user)
 	if res.Error == nil {
 		return user
 	}
@@ -87,27 +128,33 @@ func (db *userConnection) VerifyCredential(email string, password string) interf
 // }
 
 //IsDuplicateEmail is check duplicate email and return transaction to caller function
-func (db *userConnection) IsDuplicateEmail(email string) (tx *gorm.DB) {
-	var user entity.User                                       //get user from db
-	return db.connection.Where("email = ?", email).Take(&user) //find user by email
+func (db *userConnection) IsDuplicateEmail(ctx context.Context, email string) (tx *gorm.DB) {
+	var user entity.User                                                        //get user from db
+	return db.connection.WithContext(ctx).Where("email = ?", email).Take(&user) //find user by email
 }
 
 // FindByEmail is find user by email and return user entity to caller function
-func (db *userConnection) FindByEmail(email string) entity.User {
-	var user entity.User                                //get user from db
-	db.connection.Where("email = ?", email).Take(&user) //find user by email
-	return user                                         //return user
+func (db *userConnection) FindByEmail(ctx context.Context, email string) entity.User {
+	var user entity.User                                                 //get user from db
+	db.connection.WithContext(ctx).Where("email = ?", email).Take(&user) //find user by email
+	return user                                                          //return user
 }
 
 // ProfileUser is find user by id and return user entity to caller function
-func (db *userConnection) ProfileUser(userID int64) entity.User {
-	var user entity.User                                                     // get user from db
-	db.connection.Preload("Books").Preload("Books.User").Find(&user, userID) //find user by id and preload books and user
-	return user                                                              //return user
+func (db *userConnection) ProfileUser(ctx context.Context, userID int64) entity.User {
+	var user entity.User // get user from db
+	db.connection.WithContext(ctx).
+		Preload("Books").Preload("Books.User").Preload("Books.Authors").Preload("Books.Categories").Preload("Books.Tags").
+		Preload("Shelves", func(tx *gorm.DB) *gorm.DB { return tx.Select(shelfBookCount).Order("id") }).
+		Find(&user, userID) //find user by id and preload books, user and shelves with their book count
+	return user //return user
 }
 
 // hashAndSalt is hash password and return hashed password
-func hashAndSalt(pwd []byte) string {
+func hashAndSalt(ctx context.Context, pwd []byte) string {
+	_, span := tracing.Start(ctx, "bcrypt.GenerateFromPassword") // bcrypt is slow on purpose, trace it
+	defer span.End()
+
 	hash, err := bcrypt.GenerateFromPassword(pwd, bcrypt.MinCost) //hash password
 	if err != nil {
 		log.Println(err)
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+)
+
+func TestUserRepositoryInsertUserRollback(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	// Without the shelves table the default shelves cannot be created and the user is rolled back
+	if err := db.Migrator().DropTable(&entity.ShelfEntry{}, &entity.Shelf{}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := repo.InsertUser(ctx, entity.User{Name: "user", Email: "user@test", Password: "secret"})
+	if err == nil {
+		t.Fatal("InsertUser() error = nil, want the error of the default shelves")
+	}
+	if user.ID != 0 {
+		t.Errorf("InsertUser() id = %d, want 0", user.ID)
+	}
+	var count int64
+	db.Model(&entity.User{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d users stored, want 0", count)
+	}
+}
+
+func TestUserRepositoryUpdateUserRollback(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user, err := repo.InsertUser(ctx, entity.User{Name: "user", Email: "user@test", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the audit table the change cannot be recorded and the update is rolled back
+	if err := db.Migrator().DropTable(&entity.AuditEntry{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.UpdateUser(ctx, entity.User{ID: user.ID, Name: "renamed", Email: "user@test"}); err == nil {
+		t.Fatal("UpdateUser() error = nil, want the error of the audit")
+	}
+	if got := repo.ProfileUser(ctx, int64(user.ID)); got.Name != "user" {
+		t.Errorf("name = %q after the rollback, want %q", got.Name, "user")
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyQueued is returned when a user joins the waitlist of a book they already wait for
+var ErrAlreadyQueued = errors.New("already in the waitlist")
+
+// ErrNoOffer is returned when a user accepts a book that is not offered to them
+var ErrNoOffer = errors.New("the book is not offered to you")
+
+// WaitlistRepository is contract what waitlistRepository can do to db, every change locks the book row so the queue moves one step at a time
+type WaitlistRepository interface {
+	GetQueue(ctx context.Context, bookID uint64, offerTTL time.Duration) ([]entity.WaitlistEntry, error)            // get the queue of the book in order, after moving on expired offers
+	Join(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) (entity.WaitlistEntry, error)   // add the user at the end of the queue
+	Leave(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) error                          // remove the user from the queue
+	Accept(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) (entity.WaitlistEntry, error) // take the book offered to the user
+	SetAvailability(ctx context.Context, bookID uint64, availability string, offerTTL time.Duration) error          // change the availability of the book
+}
+
+// waitlistConnection is a struct that implements connection to db with gorm
+type waitlistConnection struct {
+	connection *gorm.DB // connection to database
+}
+
+// NewWaitlistRepository method is used to create a new instance of waitlistConnection
+func NewWaitlistRepository(connection *gorm.DB) WaitlistRepository {
+	return &waitlistConnection{connection: connection}
+}
+
+// GetQueue method is used to get the waiting and offered entries of the book with their user and position, expired offers move on first
+func (db *waitlistConnection) GetQueue(ctx context.Context, bookID uint64, offerTTL time.Duration) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry // create variable entries
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := advanceQueue(tx, bookID, offerTTL); err != nil {
+			return err
+		}
+		return tx.Preload("User").Where("book_id = ? AND status IN ?", bookID, queueStatuses).Order("id").Find(&entries).Error
+	})
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries, err
+}
+
+// Join method is used to add the user at the end of the queue of the book, they are offered the book at once when it is available and nobody waits
+func (db *waitlistConnection) Join(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) (entity.WaitlistEntry, error) {
+	entry := entity.WaitlistEntry{BookID: bookID, UserID: userID, Status: entity.WaitlistWaiting}
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := advanceQueue(tx, bookID, offerTTL); err != nil {
+			return err
+		}
+		var queued int64
+		if err := tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND user_id = ? AND status IN ?", bookID, userID, queueStatuses).Count(&queued).Error; err != nil {
+			return err
+		}
+		if queued > 0 {
+			return ErrAlreadyQueued
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		if err := advanceQueue(tx, bookID, offerTTL); err != nil {
+			return err
+		}
+		return queueEntry(tx, &entry)
+	})
+	return entry, err
+}
+
+// Leave method is used to remove the user from the queue of the book, an offer they held moves on to the next person
+func (db *waitlistConnection) Leave(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		result := tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND user_id = ? AND status IN ?", bookID, userID, queueStatuses).Update("status", entity.WaitlistLeft)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return advanceQueue(tx, bookID, offerTTL)
+	})
+}
+
+// Accept method is used to take the book offered to the user before the offer expires, the book is in use afterwards
+func (db *waitlistConnection) Accept(ctx context.Context, bookID uint64, userID uint64, offerTTL time.Duration) (entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry // create variable entry
+	err := db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := advanceQueue(tx, bookID, offerTTL); err != nil {
+			return err
+		}
+		err := tx.Where("book_id = ? AND user_id = ? AND status = ?", bookID, userID, entity.WaitlistOffered).Take(&entry).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoOffer
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entry).Update("status", entity.WaitlistAccepted).Error; err != nil {
+			return err
+		}
+		return tx.Table("books").Where("id = ?", bookID).Update("availability", entity.AvailabilityInUse).Error
+	})
+	return entry, err
+}
+
+// SetAvailability method is used to change the availability of the book, an available book is offered to the first person in line
+// and a book back in use takes its pending offer back, the person keeps their place
+func (db *waitlistConnection) SetAvailability(ctx context.Context, bookID uint64, availability string, offerTTL time.Duration) error {
+	return db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+		if err := tx.Table("books").Where("id = ?", bookID).Update("availability", availability).Error; err != nil {
+			return err
+		}
+		if availability == entity.AvailabilityInUse {
+			return tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND status = ?", bookID, entity.WaitlistOffered).
+				Updates(map[string]interface{}{"status": entity.WaitlistWaiting, "offered_at": nil, "offer_expires_at": nil}).Error
+		}
+		return advanceQueue(tx, bookID, offerTTL)
+	})
+}
+
+// queueStatuses are the statuses of the entries in the queue
+var queueStatuses = []string{entity.WaitlistWaiting, entity.WaitlistOffered}
+
+// advanceQueue expires the offers past their deadline and offers the available book to the first person in line when nobody holds an offer,
+// the book row must be locked by the transaction
+func advanceQueue(tx *gorm.DB, bookID uint64, offerTTL time.Duration) error {
+	now := time.Now()
+	err := tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND status = ? AND offer_expires_at <= ?", bookID, entity.WaitlistOffered, now).
+		Update("status", entity.WaitlistExpired).Error
+	if err != nil {
+		return err
+	}
+
+	var book entity.Book
+	if err := tx.Select("id", "availability").Take(&book, bookID).Error; err != nil {
+		return err
+	}
+	if book.Availability != entity.AvailabilityAvailable {
+		return nil
+	}
+
+	var offered int64
+	if err := tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND status = ?", bookID, entity.WaitlistOffered).Count(&offered).Error; err != nil {
+		return err
+	}
+	if offered > 0 {
+		return nil
+	}
+
+	var next entity.WaitlistEntry
+	err = tx.Where("book_id = ? AND status = ?", bookID, entity.WaitlistWaiting).Order("id").Take(&next).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // nobody waits
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&next).Updates(map[string]interface{}{"status": entity.WaitlistOffered, "offered_at": now, "offer_expires_at": now.Add(offerTTL)}).Error
+}
+
+// queueEntry reloads the entry and sets its position in the queue
+func queueEntry(tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	if err := tx.Take(entry, entry.ID).Error; err != nil {
+		return err
+	}
+	var ahead int64
+	if err := tx.Model(&entity.WaitlistEntry{}).Where("book_id = ? AND status IN ? AND id < ?", entry.BookID, queueStatuses, entry.ID).Count(&ahead).Error; err != nil {
+		return err
+	}
+	entry.Position = int(ahead) + 1
+	return nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// defaultAuditLimit is the number of entries returned by Search when no limit is asked
+const defaultAuditLimit = 50
+
+var (
+	ErrAuditEntryNotFound = errors.New("history entry not found for the book")
+	ErrNotRevertable      = errors.New("the book cannot be reverted to a deletion")
+	ErrDuplicateISBN      = repository.ErrDuplicateISBN
+)
+
+// AuditService is a contract about what audit service can do
+type AuditService interface {
+	GetBookHistory(ctx context.Context, bookID uint64) []entity.AuditEntry              // Get the history of a book, newest first
+	Search(ctx context.Context, q dto.AuditFilterDTORequest) []entity.AuditEntry        // Get the entries of the audit log matching the filter
+	RevertBook(ctx context.Context, bookID uint64, entryID uint64) (entity.Book, error) // Put a book back to a version of its history
+}
+
+// Create a auditService struct to implement AuditService interface
+type auditService struct {
+	auditRepository repository.AuditRepository
+	bookRepository  repository.BookRepository
+	logger          *zap.Logger
+}
+
+// NewAuditService method is used to create a new instance of auditService
+func NewAuditService(auditRepo repository.AuditRepository, bookRepo repository.BookRepository, logger *zap.Logger) AuditService {
+	return &auditService{auditRepository: auditRepo, bookRepository: bookRepo, logger: logger}
+}
+
+// GetBookHistory method is used to get every create, update and revert of the book, newest first
+func (s *auditService) GetBookHistory(ctx context.Context, bookID uint64) []entity.AuditEntry {
+	ctx, span := tracing.Start(ctx, "AuditService.GetBookHistory")
+	defer span.End()
+	return s.auditRepository.GetByEntity(ctx, entity.AuditBook, bookID)
+}
+
+// Search method is used to get the entries of the audit log matching the filter, newest first
+func (s *auditService) Search(ctx context.Context, q dto.AuditFilterDTORequest) []entity.AuditEntry {
+	ctx, span := tracing.Start(ctx, "AuditService.Search")
+	defer span.End()
+	filter := repository.AuditFilter{
+		EntityType: q.EntityType,
+		EntityID:   q.EntityID,
+		ActorID:    q.ActorID,
+		Action:     q.Action,
+		RequestID:  q.RequestID,
+		From:       parseAuditTime(q.From),
+		To:         parseAuditTime(q.To),
+		BeforeID:   q.BeforeID,
+		Limit:      q.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	return s.auditRepository.Search(ctx, filter)
+}
+
+// RevertBook method is used to put the book back to the version recorded by an entry of its history
+func (s *auditService) RevertBook(ctx context.Context, bookID uint64, entryID uint64) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.RevertBook")
+	defer span.End()
+	entry := s.auditRepository.GetByID(ctx, entryID)
+	if entry.ID == 0 || entry.EntityType != entity.AuditBook || entry.EntityID != bookID {
+		return entity.Book{}, ErrAuditEntryNotFound
+	}
+	if entry.Action == entity.AuditDelete || len(entry.Snapshot) == 0 {
+		return entity.Book{}, ErrNotRevertable
+	}
+	book := s.bookRepository.GetByID(ctx, bookID)
+	if isbn, ok := entry.Snapshot["isbn"].(string); ok && s.bookRepository.IsDuplicateISBN(ctx, book.UserID, isbn, bookID).Error == nil {
+		return entity.Book{}, ErrDuplicateISBN
+	}
+	book, err := s.bookRepository.RevertMyBook(ctx, bookID, entry)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to revert book", zap.Uint64("book_id", bookID), zap.Uint64("entry_id", entryID), zap.Error(err))
+		return book, err
+	}
+	logging.With(ctx, s.logger).Info("Book reverted", zap.Uint64("book_id", bookID), zap.Uint64("entry_id", entryID))
+	return showShareToken(ctx, book), nil
+}
+
+// parseAuditTime returns the time of a validated RFC 3339 value, nil when it is empty
+func parseAuditTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -1,54 +1,62 @@
 package services
 
 import (
-	"log"
+	"context"
 
 	"github.com/mashingan/smapping"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthService is a contract about some auth service can do
 type AuthService interface {
 	//VerifyCredential is verify user credential
-	VerifyCredential(email string, password string) interface{}
+	VerifyCredential(ctx context.Context, email string, password string) interface{}
 	//CreateUser is insert user to db and return user entity to caller function
-	CreateUser(user dto.RegisterDTORequest) entity.User
+	CreateUser(ctx context.Context, user dto.RegisterDTORequest) (entity.User, error)
 	//FindByEmail is find user by email
-	FindByEmail(email string) entity.User
+	FindByEmail(ctx context.Context, email string) entity.User
 	//IsDuplicateEmail is check duplicate email
-	IsDuplicateEmail(email string) bool
+	IsDuplicateEmail(ctx context.Context, email string) bool
 }
 
 // Create a new authService with the given userRepository.
 type authService struct {
 	userRepository repository.UserRepository
+	logger         *zap.Logger
 }
 
 // NewAuthService is creates a new instance of AuthService with the given userRepository.
-func NewAuthService(userRepository repository.UserRepository) AuthService {
-	return &authService{userRepository: userRepository}
+func NewAuthService(userRepository repository.UserRepository, logger *zap.Logger) AuthService {
+	return &authService{userRepository: userRepository, logger: logger}
 }
 
 // VerifyCredential is verify user credential and return user entity to caller function
-func (s *authService) VerifyCredential(email string, password string) interface{} {
+func (s *authService) VerifyCredential(ctx context.Context, email string, password string) interface{} {
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyCredential")
+	defer span.End()
+
 	//verify user credential and return user entity to caller function
-	res := s.userRepository.VerifyCredential(email, password)
+	res := s.userRepository.VerifyCredential(ctx, email, password)
 
 	//if res is user entity then return user entity to caller function
 	if v, ok := res.(entity.User); ok {
 		/*
 			compare password with hashed password and return true if password is matched or return false if password is not matched
 		*/
-		comparedPassword := comparePassword(v.Password, []byte(password))
+		comparedPassword := comparePassword(ctx, v.Password, []byte(password))
 		/*
 			if email is matched and password is matched then return user entity to caller function
 		*/
 		if v.Email == email && comparedPassword {
 			return res //return user entity to caller function
 		}
+		logging.With(ctx, s.logger).Info("Login failed: password mismatch", zap.Uint64("user_id", v.ID))
 
 		//return false if email is not matched or password is not matched
 		return false
@@ -58,7 +66,9 @@ func (s *authService) VerifyCredential(email string, password string) interface{
 }
 
 // CreateUser is insert user to db and return user entity to caller function
-func (s *authService) CreateUser(user dto.RegisterDTORequest) entity.User {
+func (s *authService) CreateUser(ctx context.Context, user dto.RegisterDTORequest) (entity.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.CreateUser")
+	defer span.End()
 
 	userToCreate := entity.User{} // create user entity
 
@@ -67,31 +77,41 @@ func (s *authService) CreateUser(user dto.RegisterDTORequest) entity.User {
 	*/
 	err := smapping.FillStruct(&userToCreate, smapping.MapFields(&user))
 	if err != nil {
-		log.Fatalf("Failed map %v", err)
+		logging.With(ctx, s.logger).Fatal("Failed map", zap.Error(err))
 	}
 
 	//insert user to db and return user entity to caller function
-	res := s.userRepository.InsertUser(userToCreate)
-	return res //return user entity to caller function
+	res, err := s.userRepository.InsertUser(ctx, userToCreate)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to register user", zap.Error(err))
+		return res, err
+	}
+	logging.With(ctx, s.logger).Info("User registered", zap.Uint64("user_id", res.ID))
+	return res, nil //return user entity to caller function
 
 }
 
 // FindByEmail is find user by email and return user entity to caller function
-func (s *authService) FindByEmail(email string) entity.User {
+func (s *authService) FindByEmail(ctx context.Context, email string) entity.User {
+	ctx, span := tracing.Start(ctx, "AuthService.FindByEmail")
+	defer span.End()
 
 	//find user by email and return user entity to caller function
-	return s.userRepository.FindByEmail(email)
+	return s.userRepository.FindByEmail(ctx, email)
 
 }
 
 /*
 IsDuplicateEmail is check duplicate email and return true if duplicate email is found or return false if duplicate email is not found
 */
-func (s *authService) IsDuplicateEmail(email string) bool {
+func (s *authService) IsDuplicateEmail(ctx context.Context, email string) bool {
+	ctx, span := tracing.Start(ctx, "AuthService.IsDuplicateEmail")
+	defer span.End()
+
 	/*
 		check duplicate email and return true if duplicate email is found or return false if duplicate email is not found
 	*/
-	res := s.userRepository.IsDuplicateEmail(email)
+	res := s.userRepository.IsDuplicateEmail(ctx, email)
 
 	//if error is not nil then duplicate email is found
 	return !(res.Error == nil)
@@ -101,7 +121,9 @@ func (s *authService) IsDuplicateEmail(email string) bool {
 /*
 comparePassword is compare password with hashed password and return true if password is matched or return false if password is not matched
 */
-func comparePassword(hashedPwd string, plainPassword []byte) bool {
+func comparePassword(ctx context.Context, hashedPwd string, plainPassword []byte) bool {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword") // bcrypt is slow on purpose, trace it
+	defer span.End()
 
 	//convert hashed password to byte array
 	byteHash := []byte(hashedPwd)
@@ -109,7 +131,6 @@ func comparePassword(hashedPwd string, plainPassword []byte) bool {
 	//compare password with hashed password
 	err := bcrypt.CompareHashAndPassword(byteHash, plainPassword)
 	if err != nil {
-		log.Println(err)
 		return false
 	}
 	/*
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mashingan/smapping"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// AuthorService is a contract about what author service can do
+type AuthorService interface {
+	GetAll(ctx context.Context) []entity.Author                                            // Get all author
+	GetByID(ctx context.Context, authorID uint64) entity.Author                            // Get an author with their books
+	CreateAuthor(ctx context.Context, a dto.AuthorCreateDTORequest) entity.Author          // Create a new author
+	UpdateAuthor(ctx context.Context, a dto.AuthorUpdateDTORequest) (entity.Author, error) // Update an author
+	DeleteAuthor(ctx context.Context, a entity.Author)                                     // Delete an author
+	HasBooks(ctx context.Context, authorID uint64) bool                                    // Check if the author has books
+	IsDuplicateName(ctx context.Context, name string, authorID uint64) bool                // Check if another author has the same name
+}
+
+// Create a authorService struct to implement AuthorService interface
+type authorService struct {
+	authorRepository repository.AuthorRepository
+	logger           *zap.Logger
+}
+
+// NewAuthorService method is used to create a new instance of authorService
+func NewAuthorService(authorRepo repository.AuthorRepository, logger *zap.Logger) AuthorService {
+	return &authorService{authorRepository: authorRepo, logger: logger}
+}
+
+// GetAll method is used to get all author
+func (s *authorService) GetAll(ctx context.Context) []entity.Author {
+	ctx, span := tracing.Start(ctx, "AuthorService.GetAll")
+	defer span.End()
+	return s.authorRepository.GetAll(ctx)
+}
+
+// GetByID method is used to get an author with their books
+func (s *authorService) GetByID(ctx context.Context, authorID uint64) entity.Author {
+	ctx, span := tracing.Start(ctx, "AuthorService.GetByID")
+	defer span.End()
+	return s.authorRepository.GetByID(ctx, authorID)
+}
+
+// CreateAuthor method is used to create a new author
+func (s *authorService) CreateAuthor(ctx context.Context, a dto.AuthorCreateDTORequest) entity.Author {
+	ctx, span := tracing.Start(ctx, "AuthorService.CreateAuthor")
+	defer span.End()
+	author := entity.Author{}                                   // author is a new instance of Author
+	err := smapping.FillStruct(&author, smapping.MapFields(&a)) // Fill the author with the author data
+	if err != nil {
+		logging.With(ctx, s.logger).Fatal("Failed to map fields", zap.Error(err))
+	}
+	result := s.authorRepository.InsertAuthor(ctx, author) // Create the author
+	logging.With(ctx, s.logger).Info("Author created", zap.Uint64("author_id", result.ID))
+	return result
+}
+
+// UpdateAuthor method is used to update an author
+func (s *authorService) UpdateAuthor(ctx context.Context, a dto.AuthorUpdateDTORequest) (entity.Author, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.UpdateAuthor")
+	defer span.End()
+	author := entity.Author{}                                   // author is a new instance of Author
+	err := smapping.FillStruct(&author, smapping.MapFields(&a)) // Fill the author with the author data
+	if err != nil {
+		logging.With(ctx, s.logger).Fatal("Failed to map fields", zap.Error(err))
+	}
+	if _, err := s.authorRepository.UpdateAuthor(ctx, author); err != nil { // Update the author
+		logging.With(ctx, s.logger).Error("Failed to update author", zap.Uint64("author_id", author.ID), zap.Error(err))
+		return entity.Author{}, err
+	}
+	logging.With(ctx, s.logger).Info("Author updated", zap.Uint64("author_id", author.ID))
+	return s.authorRepository.GetByID(ctx, author.ID), nil
+}
+
+// DeleteAuthor method is used to delete an author
+func (s *authorService) DeleteAuthor(ctx context.Context, a entity.Author) {
+	ctx, span := tracing.Start(ctx, "AuthorService.DeleteAuthor")
+	defer span.End()
+	s.authorRepository.DeleteAuthor(ctx, a) // delete author
+	logging.With(ctx, s.logger).Info("Author deleted", zap.Uint64("author_id", a.ID))
+}
+
+// HasBooks method is used to check if the author has books
+func (s *authorService) HasBooks(ctx context.Context, authorID uint64) bool {
+	ctx, span := tracing.Start(ctx, "AuthorService.HasBooks")
+	defer span.End()
+	return s.authorRepository.CountBooks(ctx, authorID) > 0
+}
+
+// IsDuplicateName method is used to check if another author (than authorID) has the same name
+func (s *authorService) IsDuplicateName(ctx context.Context, name string, authorID uint64) bool {
+	ctx, span := tracing.Start(ctx, "AuthorService.IsDuplicateName")
+	defer span.End()
+	res := s.authorRepository.IsDuplicateName(ctx, name, authorID)
+	return res.Error == nil // An author was found
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// BookCopyService is a contract about what book copy service can do
+type BookCopyService interface {
+	GetByBook(ctx context.Context, bookID uint64, f dto.BookCopyFilterDTORequest) []entity.BookCopy // Get all copy of a book
+	GetByID(ctx context.Context, copyID uint64) entity.BookCopy                                     // Get a copy by copyID
+	CreateCopy(ctx context.Context, c dto.BookCopyCreateDTORequest) (entity.BookCopy, error)        // Create a new copy
+	UpdateCopy(ctx context.Context, c dto.BookCopyUpdateDTORequest) (entity.BookCopy, error)        // Update a copy
+	DeleteCopy(ctx context.Context, c entity.BookCopy) error                                        // Delete a copy
+	IsDuplicateBarcode(ctx context.Context, barcode string, copyID uint64) bool                     // Check another copy has the barcode
+}
+
+// Create a bookCopyService struct to implement BookCopyService interface
+type bookCopyService struct {
+	bookCopyRepository repository.BookCopyRepository
+	logger             *zap.Logger
+}
+
+// NewBookCopyService method is used to create a new instance of bookCopyService
+func NewBookCopyService(bookCopyRepo repository.BookCopyRepository, logger *zap.Logger) BookCopyService {
+	return &bookCopyService{bookCopyRepository: bookCopyRepo, logger: logger}
+}
+
+// GetByBook method is used to get all copy of a book ordered by barcode
+func (s *bookCopyService) GetByBook(ctx context.Context, bookID uint64, f dto.BookCopyFilterDTORequest) []entity.BookCopy {
+	ctx, span := tracing.Start(ctx, "BookCopyService.GetByBook")
+	defer span.End()
+	return s.bookCopyRepository.GetByBook(ctx, bookID, f.Status)
+}
+
+// GetByID method is used to get a copy by copyID
+func (s *bookCopyService) GetByID(ctx context.Context, copyID uint64) entity.BookCopy {
+	ctx, span := tracing.Start(ctx, "BookCopyService.GetByID")
+	defer span.End()
+	return s.bookCopyRepository.GetByID(ctx, copyID)
+}
+
+// CreateCopy method is used to create a new copy, the counts of the book are refreshed in the same transaction
+func (s *bookCopyService) CreateCopy(ctx context.Context, c dto.BookCopyCreateDTORequest) (entity.BookCopy, error) {
+	ctx, span := tracing.Start(ctx, "BookCopyService.CreateCopy")
+	defer span.End()
+	bookCopy := entity.BookCopy{BookID: c.BookID, Barcode: c.Barcode, Condition: c.Condition, Location: c.Location, Status: c.Status}
+	if bookCopy.Status == "" {
+		bookCopy.Status = entity.CopyAvailable
+	}
+	result, err := s.bookCopyRepository.InsertCopy(ctx, bookCopy)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to create copy", zap.Uint64("book_id", c.BookID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Copy created", zap.Uint64("copy_id", result.ID), zap.Uint64("book_id", result.BookID))
+	return result, nil
+}
+
+// UpdateCopy method is used to update barcode, condition, location and status of a copy
+func (s *bookCopyService) UpdateCopy(ctx context.Context, c dto.BookCopyUpdateDTORequest) (entity.BookCopy, error) {
+	ctx, span := tracing.Start(ctx, "BookCopyService.UpdateCopy")
+	defer span.End()
+	bookCopy := s.bookCopyRepository.GetByID(ctx, c.ID) // Keep the book of the copy
+	bookCopy.Barcode = c.Barcode
+	bookCopy.Condition = c.Condition
+	bookCopy.Location = c.Location
+	bookCopy.Status = c.Status
+	result, err := s.bookCopyRepository.UpdateCopy(ctx, bookCopy)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to update copy", zap.Uint64("copy_id", c.ID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Copy updated", zap.Uint64("copy_id", result.ID), zap.String("status", result.Status))
+	return result, nil
+}
+
+// DeleteCopy method is used to delete a copy
+func (s *bookCopyService) DeleteCopy(ctx context.Context, c entity.BookCopy) error {
+	ctx, span := tracing.Start(ctx, "BookCopyService.DeleteCopy")
+	defer span.End()
+	if err := s.bookCopyRepository.DeleteCopy(ctx, c); err != nil {
+		logging.With(ctx, s.logger).Error("Failed to delete copy", zap.Uint64("copy_id", c.ID), zap.Error(err))
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Copy deleted", zap.Uint64("copy_id", c.ID), zap.Uint64("book_id", c.BookID))
+	return nil
+}
+
+// IsDuplicateBarcode method is used to check if another copy has the barcode
+func (s *bookCopyService) IsDuplicateBarcode(ctx context.Context, barcode string, copyID uint64) bool {
+	ctx, span := tracing.Start(ctx, "BookCopyService.IsDuplicateBarcode")
+	defer span.End()
+	res := s.bookCopyRepository.IsDuplicateBarcode(ctx, barcode, copyID)
+	return res.Error == nil // A copy was found
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/ebook"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/storage"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// ErrEbookTooLarge is returned by BookFileService.UploadFile when the file exceeds the maximum size
+var ErrEbookTooLarge = errors.New("e-book file is too large")
+
+// ebookContentTypes are the content types the files of each format are stored and served with
+var ebookContentTypes = map[string]string{
+	ebook.FormatEPUB: "application/epub+zip",
+	ebook.FormatPDF:  "application/pdf",
+}
+
+// BookFileService is a contract about what book file service can do
+type BookFileService interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.BookFile // Get all file of the book
+	GetByID(ctx context.Context, fileID uint64) entity.BookFile     // Get file by id
+	// Store the EPUB or PDF file of the book, replacing the file in the same format
+	UploadFile(ctx context.Context, book entity.Book, r io.ReaderAt, size int64, fileName string) (dto.BookFileUploadDTOResponse, error)
+	DownloadURL(ctx context.Context, f entity.BookFile) (dto.BookFileDownloadDTOResponse, error) // Sign an expiring download URL of the file
+	DeleteFile(ctx context.Context, f entity.BookFile) error                                     // Remove the file
+	DeleteAll(ctx context.Context, bookID uint64) error                                          // Remove all file of the book
+	MaxSize() int64                                                                              // Largest accepted file in bytes
+}
+
+// Create a bookFileService struct to implement BookFileService interface
+type bookFileService struct {
+	bookFileRepository repository.BookFileRepository
+	store              storage.BlobStore
+	maxSize            int64
+	urlTTL             time.Duration
+	logger             *zap.Logger
+}
+
+// NewBookFileService method is used to create a new instance of bookFileService
+func NewBookFileService(bookFileRepo repository.BookFileRepository, store storage.BlobStore, maxSize int64, urlTTL time.Duration, logger *zap.Logger) BookFileService {
+	return &bookFileService{bookFileRepository: bookFileRepo, store: store, maxSize: maxSize, urlTTL: urlTTL, logger: logger}
+}
+
+// MaxSize method is used to get the largest accepted file in bytes
+func (s *bookFileService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// GetByBook method is used to get all file of the book
+func (s *bookFileService) GetByBook(ctx context.Context, bookID uint64) []entity.BookFile {
+	ctx, span := tracing.Start(ctx, "BookFileService.GetByBook")
+	defer span.End()
+	return s.bookFileRepository.GetByBook(ctx, bookID)
+}
+
+// GetByID method is used to get file by id
+func (s *bookFileService) GetByID(ctx context.Context, fileID uint64) entity.BookFile {
+	ctx, span := tracing.Start(ctx, "BookFileService.GetByID")
+	defer span.End()
+	return s.bookFileRepository.GetByID(ctx, fileID)
+}
+
+/*
+UploadFile method is used to check the format of the file by its content, store it under a private key
+and replace the previous file of the book in the same format, the metadata of an EPUB is returned with
+the book filled from it so the user can apply it
+*/
+func (s *bookFileService) UploadFile(ctx context.Context, book entity.Book, r io.ReaderAt, size int64, fileName string) (dto.BookFileUploadDTOResponse, error) {
+	ctx, span := tracing.Start(ctx, "BookFileService.UploadFile")
+	defer span.End()
+
+	var result dto.BookFileUploadDTOResponse
+	if size > s.maxSize {
+		return result, ErrEbookTooLarge
+	}
+
+	// Trust the content, not the file name or the declared content type
+	format, err := ebook.DetectFormat(r, size)
+	if err != nil {
+		return result, err
+	}
+	if format == ebook.FormatEPUB {
+		metadata, err := ebook.ParseEPUB(r, size)
+		if err != nil {
+			return result, err
+		}
+		suggested := suggestBook(book, metadata)
+		result.Metadata = &metadata
+		result.Book = &suggested
+	}
+
+	key, err := ebookKey(book.ID, format)
+	if err != nil {
+		return result, err
+	}
+	contentType := ebookContentTypes[format]
+	if err := s.store.Put(ctx, key, io.NewSectionReader(r, 0, size), size, contentType); err != nil {
+		return result, err
+	}
+
+	previous := s.bookFileRepository.GetByFormat(ctx, book.ID, format)
+	file, err := s.bookFileRepository.SaveFile(ctx, entity.BookFile{
+		BookID:      book.ID,
+		Format:      format,
+		FileName:    ebookFileName(fileName, book.Title, format),
+		ContentType: contentType,
+		Size:        size,
+		Key:         key,
+	})
+	if err != nil {
+		s.deleteBlob(ctx, key) // nothing references the new file
+		return result, err
+	}
+	if previous.ID != 0 {
+		s.deleteBlob(ctx, previous.Key) // the previous file is not referenced anymore
+	}
+
+	logging.With(ctx, s.logger).Info("E-book uploaded", zap.Uint64("book_id", book.ID), zap.String("format", format), zap.Int64("size", size))
+	result.File = file
+	return result, nil
+}
+
+// DownloadURL method is used to sign a download URL of the file that expires after the configured time
+func (s *bookFileService) DownloadURL(ctx context.Context, f entity.BookFile) (dto.BookFileDownloadDTOResponse, error) {
+	ctx, span := tracing.Start(ctx, "BookFileService.DownloadURL")
+	defer span.End()
+	url, err := s.store.SignedURL(ctx, f.Key, f.FileName, s.urlTTL)
+	if err != nil {
+		return dto.BookFileDownloadDTOResponse{}, err
+	}
+	return dto.BookFileDownloadDTOResponse{URL: url, ExpiresAt: time.Now().Add(s.urlTTL).UTC()}, nil
+}
+
+// DeleteFile method is used to remove the file and its stored blob
+func (s *bookFileService) DeleteFile(ctx context.Context, f entity.BookFile) error {
+	ctx, span := tracing.Start(ctx, "BookFileService.DeleteFile")
+	defer span.End()
+	if err := s.bookFileRepository.DeleteFile(ctx, f); err != nil {
+		return err
+	}
+	s.deleteBlob(ctx, f.Key)
+	logging.With(ctx, s.logger).Info("E-book deleted", zap.Uint64("book_id", f.BookID), zap.String("format", f.Format))
+	return nil
+}
+
+// DeleteAll method is used to remove all file of the book, called before the book is deleted
+func (s *bookFileService) DeleteAll(ctx context.Context, bookID uint64) error {
+	ctx, span := tracing.Start(ctx, "BookFileService.DeleteAll")
+	defer span.End()
+	for _, f := range s.bookFileRepository.GetByBook(ctx, bookID) {
+		if err := s.DeleteFile(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBlob removes the stored file, failures are only logged as the file is already unlinked
+func (s *bookFileService) deleteBlob(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		logging.With(ctx, s.logger).Warn("Failed to delete e-book file", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// suggestBook returns the update request of the book with the title, authors and ISBN found in the metadata
+func suggestBook(book entity.Book, metadata ebook.Metadata) dto.BookUpdateDTORequest {
+	suggested := dto.BookUpdateDTORequest{
+		ID:          book.ID,
+		Title:       book.Title,
+		Author:      book.Author,
+		Price:       book.Price,
+		Description: book.Description,
+	}
+	if book.ISBN != nil {
+		suggested.ISBN = *book.ISBN
+	}
+	for _, author := range book.Authors {
+		suggested.AuthorIDs = append(suggested.AuthorIDs, author.ID)
+	}
+	for _, category := range book.Categories {
+		suggested.CategoryIDs = append(suggested.CategoryIDs, category.ID)
+	}
+	for _, tag := range book.Tags {
+		suggested.TagNames = append(suggested.TagNames, tag.Name)
+	}
+
+	if metadata.Title != "" {
+		suggested.Title = truncate(metadata.Title, maxBookTextLength)
+	}
+	if len(metadata.Creators) > 0 {
+		// The creators are names only, the author links are left to the user
+		suggested.Author = truncate(strings.Join(metadata.Creators, ", "), maxBookTextLength)
+		suggested.AuthorIDs = nil
+	}
+	// Identifiers are often ISBNs written as URNs, other schemes are ignored
+	isbn := strings.TrimPrefix(strings.ToLower(metadata.Identifier), "urn:isbn:")
+	if helper.IsValidISBN(isbn) {
+		suggested.ISBN = helper.NormalizeISBN(isbn)
+	}
+	return suggested
+}
+
+// ebookKey returns a new random private storage key of a file of the book
+func ebookKey(bookID uint64, format string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ebooks/%d/%s/book.%s", bookID, hex.EncodeToString(b), format), nil
+}
+
+// ebookFileName returns the uploaded file name with the extension of the format, or one made from the title
+func ebookFileName(fileName string, title string, format string) string {
+	base := path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	name := strings.TrimSuffix(base, path.Ext(base))
+	if name == "" || name == "." || name == "/" {
+		name = helper.Slugify(title)
+	}
+	if name == "" {
+		name = "book"
+	}
+	// Quotes and control characters would break the Content-Disposition header
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	return truncate(name, 200) + "." + format
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/bookimport"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// importBatchSize is the number of rows inserted in one transaction
+const importBatchSize = 100
+
+// BookImportService is a contract about what book import service can do
+type BookImportService interface {
+	// Import the rows of the user, in the background when there are more than Limits().SyncRows
+	Import(ctx context.Context, userID uint64, format string, rows []bookimport.Row) (entity.ImportJob, error)
+	GetJob(ctx context.Context, jobID uint64) entity.ImportJob // Get import job by id
+	Limits() bookimport.Limits                                 // Size limits of the imports
+}
+
+// Create a bookImportService struct to implement BookImportService interface
+type bookImportService struct {
+	importJobRepository repository.ImportJobRepository
+	bookService         BookService
+	limits              bookimport.Limits
+	workers             chan struct{} // one slot per background import allowed to run
+	logger              *zap.Logger
+}
+
+// NewBookImportService method is used to create a new instance of bookImportService
+func NewBookImportService(importJobRepo repository.ImportJobRepository, bookServ BookService, limits bookimport.Limits, logger *zap.Logger) BookImportService {
+	return &bookImportService{importJobRepository: importJobRepo, bookService: bookServ, limits: limits, workers: make(chan struct{}, limits.Workers), logger: logger}
+}
+
+// Limits method is used to get the size limits of the imports
+func (s *bookImportService) Limits() bookimport.Limits {
+	return s.limits
+}
+
+// GetJob method is used to get import job by id
+func (s *bookImportService) GetJob(ctx context.Context, jobID uint64) entity.ImportJob {
+	ctx, span := tracing.Start(ctx, "BookImportService.GetJob")
+	defer span.End()
+	return s.importJobRepository.GetByID(ctx, jobID)
+}
+
+/*
+Import method is used to create an import job for the rows of the user. Small imports run before
+returning the job with its report, larger ones are queued and the returned job is still pending
+*/
+func (s *bookImportService) Import(ctx context.Context, userID uint64, format string, rows []bookimport.Row) (entity.ImportJob, error) {
+	ctx, span := tracing.Start(ctx, "BookImportService.Import")
+	defer span.End()
+
+	job, err := s.importJobRepository.InsertJob(ctx, entity.ImportJob{UserID: userID, Format: format, Status: entity.ImportPending, TotalRows: len(rows)})
+	if err != nil {
+		return job, err
+	}
+	logging.With(ctx, s.logger).Info("Import started", zap.Uint64("job_id", job.ID), zap.Uint64("user_id", userID), zap.Int("rows", len(rows)))
+
+	if len(rows) <= s.limits.SyncRows {
+		return s.run(ctx, job, rows), nil
+	}
+
+	// The job outlives the request, keep its values (request id, trace) but not its cancellation
+	background := detachedContext{parent: ctx}
+	go func() {
+		s.workers <- struct{}{}
+		defer func() { <-s.workers }()
+		defer func() {
+			if r := recover(); r != nil {
+				logging.With(background, s.logger).Error("Import panicked", zap.Uint64("job_id", job.ID), zap.Any("panic", r))
+				job.Status = entity.ImportFailed
+				job.Error = "internal error"
+				s.finish(background, job)
+			}
+		}()
+		s.run(background, job, rows)
+	}()
+	return job, nil
+}
+
+// run imports the rows batch by batch, saving the progress of the job after every batch and the report at the end
+func (s *bookImportService) run(ctx context.Context, job entity.ImportJob, rows []bookimport.Row) entity.ImportJob {
+	ctx, span := tracing.Start(ctx, "BookImportService.run")
+	defer span.End()
+
+	job.Status = entity.ImportRunning
+	s.save(ctx, job)
+
+	job.Report = make([]entity.ImportRowResult, len(rows))
+	isbnLines := make(map[string]int) // lines of the ISBNs seen in the file
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		// Check the rows of the batch, only the valid ones are inserted
+		var books []dto.BookCreateDTORequest
+		var indexes []int
+		for i := start; i < end; i++ {
+			row := rows[i]
+			row.Book.UserID = job.UserID // The books always belong to the importing user
+			job.Report[i].Line = row.Line
+			if err := s.check(ctx, row, isbnLines); err != nil {
+				job.Report[i].Error = err.Error()
+				continue
+			}
+			books = append(books, row.Book)
+			indexes = append(indexes, i)
+		}
+
+		if len(books) > 0 {
+			created, errs := s.bookService.CreateMyBooks(ctx, books)
+			for j, i := range indexes {
+				if errs[j] != nil {
+					job.Report[i].Error = errs[j].Error()
+					continue
+				}
+				job.Report[i].BookID = created[j].ID
+			}
+		}
+
+		for i := start; i < end; i++ {
+			if job.Report[i].Error != "" {
+				job.FailedRows++
+			} else {
+				job.CreatedRows++
+			}
+		}
+		job.ProcessedRows = end
+		if end < len(rows) {
+			s.save(ctx, job)
+		}
+	}
+
+	job.Status = entity.ImportDone
+	job = s.finish(ctx, job)
+	logging.With(ctx, s.logger).Info("Import done", zap.Uint64("job_id", job.ID), zap.Int("created", job.CreatedRows), zap.Int("failed", job.FailedRows))
+	return job
+}
+
+// check applies the rules of the create book endpoint that need the database, and rejects ISBNs repeated in the file
+func (s *bookImportService) check(ctx context.Context, row bookimport.Row, isbnLines map[string]int) error {
+	if row.Err != nil {
+		return row.Err
+	}
+	b := row.Book
+	if !s.bookService.IsValidAuthorIDs(ctx, b.AuthorIDs) {
+		return fmt.Errorf("unknown author id")
+	}
+	if !s.bookService.IsValidCategoryIDs(ctx, b.CategoryIDs) {
+		return fmt.Errorf("unknown category id")
+	}
+	if b.ISBN != "" {
+		isbn := helper.NormalizeISBN(b.ISBN)
+		if line, ok := isbnLines[isbn]; ok {
+			return fmt.Errorf("same ISBN as line %d", line)
+		}
+		if s.bookService.IsDuplicateISBN(ctx, b.UserID, b.ISBN, 0) {
+			return fmt.Errorf("you already have a book with this ISBN")
+		}
+		isbnLines[isbn] = row.Line
+	}
+	return nil
+}
+
+// finish saves the job as finished now
+func (s *bookImportService) finish(ctx context.Context, job entity.ImportJob) entity.ImportJob {
+	now := time.Now()
+	job.FinishedAt = &now
+	s.save(ctx, job)
+	return job
+}
+
+// save stores the job, failures are only logged as the import goes on
+func (s *bookImportService) save(ctx context.Context, job entity.ImportJob) {
+	if err := s.importJobRepository.SaveJob(ctx, job); err != nil {
+		logging.With(ctx, s.logger).Error("Failed to save import job", zap.Uint64("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// detachedContext keeps the values of its parent but is never canceled and has no deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
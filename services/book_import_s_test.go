@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+type contextKey struct{}
+
+func TestDetachedContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "request"))
+	ctx := detachedContext{parent: parent}
+	cancel()
+
+	if ctx.Err() != nil || ctx.Done() != nil {
+		t.Errorf("detached context ended with its parent: %v", ctx.Err())
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("detached context has a deadline")
+	}
+	if got := ctx.Value(contextKey{}); got != "request" {
+		t.Errorf("Value() = %v, want the value of the parent", got)
+	}
+}
@@ -1,80 +1,336 @@
 package services
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
 
 	"github.com/mashingan/smapping"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// exportBatchSize is the number of books read at once by Export
+const exportBatchSize = 500
+
+// Actions on a book checked by IsAllowedActionBook
+const (
+	BookActionView   = "view"   // the owner and every collaborator
+	BookActionUpdate = "update" // the owner and the editors: details, cover and history
+	BookActionManage = "manage" // the owner only: delete, transfer, share, lend and sell
 )
 
 type BookService interface {
-	CreateMyBook(b dto.BookCreateDTORequest) entity.Book   // Create a new book
-	UpdateMyBook(b dto.BookUpdateDTORequest) entity.Book   // Update a book
-	DeleteMyBook(b entity.Book)                            // Delete a book
-	GetAll() []entity.Book                                 // Get all book
-	GetByID(bookID uint64) entity.Book                     // Get a book by bookID
-	GetAllMyBook() []entity.Book                           // Get all book by userID
-	IsAllowedActionBook(userID string, bookID uint64) bool // Check userID is allowed to access bookID
+	CreateMyBook(ctx context.Context, b dto.BookCreateDTORequest) (entity.Book, error) // Create a new book
+	UpdateMyBook(ctx context.Context, b dto.BookUpdateDTORequest) (entity.Book, error) // Update a book
+	DeleteMyBook(ctx context.Context, b entity.Book) error                             // Delete a book
+	GetAll(ctx context.Context, f dto.BookFilterDTORequest) []entity.Book              // Get all book matching the filter
+	GetByID(ctx context.Context, bookID uint64) entity.Book                            // Get a book by bookID
+	GetByShareToken(ctx context.Context, token string) entity.Book                     // Get the unlisted book of a share link
+	GetAllMyBook(ctx context.Context) []entity.Book                                    // Get all book by userID
+	// Check userID is allowed the action on bookID through their role
+	IsAllowedActionBook(ctx context.Context, userID uint64, bookID uint64, action string) bool
+	GetByISBN(ctx context.Context, isbn string) []entity.Book                            // Get all book with an ISBN-10 or ISBN-13
+	IsDuplicateISBN(ctx context.Context, userID uint64, isbn string, bookID uint64) bool // Check userID has another book with the isbn
+	IsValidAuthorIDs(ctx context.Context, authorIDs []uint64) bool                       // Check every authorID exists
+	IsValidCategoryIDs(ctx context.Context, categoryIDs []uint64) bool                   // Check every categoryID exists
+	// Create the books in one transaction, the errors are returned at the index of their book
+	CreateMyBooks(ctx context.Context, books []dto.BookCreateDTORequest) ([]entity.Book, []error)
+	// Read the books matching the filter in batches, fn gets every batch
+	Export(ctx context.Context, f dto.BookExportDTORequest, fn func(books []entity.Book) error) error
 }
 
 // Create a bookService struct to implement BookService interface
 type bookService struct {
-	bookRepository repository.BookRepository
+	bookRepository     repository.BookRepository
+	authorRepository   repository.AuthorRepository
+	categoryRepository repository.CategoryRepository
+	tagRepository      repository.TagRepository
+	logger             *zap.Logger
 }
 
 // NewBookService method is used to create a new instance of bookService
-func NewBookService(bookRepo repository.BookRepository) BookService {
-	return &bookService{bookRepository: bookRepo}
+func NewBookService(bookRepo repository.BookRepository, authorRepo repository.AuthorRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, logger *zap.Logger) BookService {
+	return &bookService{bookRepository: bookRepo, authorRepository: authorRepo, categoryRepository: categoryRepo, tagRepository: tagRepo, logger: logger}
 }
 
-// GetAll method is used to get all book
-func (s *bookService) GetAll() []entity.Book {
-	return s.bookRepository.GetAll()
+// GetAll method is used to get all book matching the filter, the category filter includes the subcategories
+func (s *bookService) GetAll(ctx context.Context, f dto.BookFilterDTORequest) []entity.Book {
+	ctx, span := tracing.Start(ctx, "BookService.GetAll")
+	defer span.End()
+	filter := repository.BookFilter{Tag: helper.NormalizeTag(f.Tag), Sort: f.Sort, ViewerID: viewerID(ctx)}
+	if f.Category != "" {
+		var category entity.Category
+		if id, err := strconv.ParseUint(f.Category, 10, 64); err == nil {
+			category = s.categoryRepository.GetByID(ctx, id)
+		} else {
+			category = s.categoryRepository.GetBySlug(ctx, helper.Slugify(f.Category))
+		}
+		if category.ID == 0 {
+			return []entity.Book{} // Unknown category has no books
+		}
+		filter.CategoryPath = category.Path
+	}
+	return showShareTokens(ctx, s.bookRepository.GetAll(ctx, filter))
+}
+
+// GetByID method is used to get a book by bookID, a private book is only found by its owner and collaborators
+func (s *bookService) GetByID(ctx context.Context, bookID uint64) entity.Book {
+	ctx, span := tracing.Start(ctx, "BookService.GetByID")
+	defer span.End()
+	book := s.bookRepository.GetByID(ctx, bookID)
+	if book.Visibility == entity.VisibilityPrivate && s.bookRepository.GetRole(ctx, bookID, viewerID(ctx)) == "" {
+		return entity.Book{} // Private books are only found by their owner and collaborators
+	}
+	return showShareToken(ctx, book)
 }
 
-// GetByID method is used to get a book by bookID
-func (s *bookService) GetByID(bookID uint64) entity.Book {
-	return s.bookRepository.GetByID(bookID)
+// GetByShareToken method is used to get the unlisted book of a share link
+func (s *bookService) GetByShareToken(ctx context.Context, token string) entity.Book {
+	ctx, span := tracing.Start(ctx, "BookService.GetByShareToken")
+	defer span.End()
+	return s.bookRepository.GetByShareToken(ctx, token)
 }
 
-// GetAllMyBook method is used to get all book by userID
-func (s *bookService) GetAllMyBook() []entity.Book {
-	return s.bookRepository.GetAllMyBook()
+// GetAllMyBook method is used to get all book owned by or shared with the user
+func (s *bookService) GetAllMyBook(ctx context.Context) []entity.Book {
+	ctx, span := tracing.Start(ctx, "BookService.GetAllMyBook")
+	defer span.End()
+	return showShareTokens(ctx, s.bookRepository.GetAllMyBook(ctx, viewerID(ctx)))
 }
 
 // CreateMyBook method is used to create a book by userID
-func (s *bookService) CreateMyBook(b dto.BookCreateDTORequest) entity.Book {
+func (s *bookService) CreateMyBook(ctx context.Context, b dto.BookCreateDTORequest) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.CreateMyBook")
+	defer span.End()
+	result, err := s.bookRepository.CreateMyBook(ctx, s.newBook(ctx, b)) // Create the book
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to create book", zap.Uint64("user_id", b.UserID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Book created", zap.Uint64("book_id", result.ID), zap.Uint64("user_id", result.UserID))
+	return showShareToken(ctx, result), nil
+}
+
+// CreateMyBooks method is used to create the books in one transaction, a failing book does not stop the others
+func (s *bookService) CreateMyBooks(ctx context.Context, books []dto.BookCreateDTORequest) ([]entity.Book, []error) {
+	ctx, span := tracing.Start(ctx, "BookService.CreateMyBooks")
+	defer span.End()
+	entities := make([]entity.Book, len(books))
+	for i, b := range books {
+		entities[i] = s.newBook(ctx, b)
+	}
+	return s.bookRepository.CreateMyBooks(ctx, entities)
+}
+
+// Export method is used to read the books matching the filter in batches of exportBatchSize
+func (s *bookService) Export(ctx context.Context, f dto.BookExportDTORequest, fn func(books []entity.Book) error) error {
+	ctx, span := tracing.Start(ctx, "BookService.Export")
+	defer span.End()
+	filter := repository.ExportFilter{
+		UserID:   f.UserID,
+		Author:   strings.TrimSpace(f.Author),
+		Title:    strings.TrimSpace(f.Title),
+		MinPrice: f.MinPrice,
+		MaxPrice: f.MaxPrice,
+		Currency: f.Currency,
+	}
+	return s.bookRepository.Export(ctx, filter, exportBatchSize, fn)
+}
+
+// newBook returns the book of the create request linked to its authors, categories and tags
+func (s *bookService) newBook(ctx context.Context, b dto.BookCreateDTORequest) entity.Book {
 	book := entity.Book{}                                     // book is a new instance of Book
 	err := smapping.FillStruct(&book, smapping.MapFields(&b)) // Fill the book with the book data
 	if err != nil {
-		log.Fatalf("Failed to map fields %v: ", err)
+		logging.With(ctx, s.logger).Fatal("Failed to map fields", zap.Error(err))
+	}
+	book.Price = b.Price.WithDefaultCurrency()
+	book.ISBN = normalizeISBN(b.ISBN)                           // Store the ISBN as ISBN-13
+	book.Authors = s.resolveAuthors(ctx, b.AuthorIDs, b.Author) // Link the book to its authors
+	book.Categories = s.categoryRepository.GetByIDs(ctx, b.CategoryIDs)
+	book.Tags = s.tagRepository.FindOrCreateByNames(ctx, normalizeTags(b.TagNames))
+	if book.Visibility == "" {
+		book.Visibility = entity.VisibilityPublic
 	}
-	result := s.bookRepository.CreateMyBook(book) // Create the book
-	return result
+	book.ShareToken = shareToken(book.Visibility, nil)
+	return book
 }
 
 // UpdateMyBook method is used to update a book by userID
-func (s *bookService) UpdateMyBook(b dto.BookUpdateDTORequest) entity.Book {
+func (s *bookService) UpdateMyBook(ctx context.Context, b dto.BookUpdateDTORequest) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.UpdateMyBook")
+	defer span.End()
 	book := entity.Book{}                                     // book is a new instance of Book
 	err := smapping.FillStruct(&book, smapping.MapFields(&b)) // Fill the book with the book data
 	if err != nil {
-		log.Fatalf("Failed to map fields %v: ", err)
+		logging.With(ctx, s.logger).Fatal("Failed to map fields", zap.Error(err))
+	}
+	book.Price = b.Price.WithDefaultCurrency()
+	book.ISBN = normalizeISBN(b.ISBN)                           // Store the ISBN as ISBN-13
+	book.Authors = s.resolveAuthors(ctx, b.AuthorIDs, b.Author) // Link the book to its authors
+	book.Categories = s.categoryRepository.GetByIDs(ctx, b.CategoryIDs)
+	book.Tags = s.tagRepository.FindOrCreateByNames(ctx, normalizeTags(b.TagNames))
+	current := s.bookRepository.GetByID(ctx, b.ID)
+	if book.Visibility == "" {
+		book.Visibility = current.Visibility // Keep the visibility when it is not sent
+	}
+	book.ShareToken = shareToken(book.Visibility, current.ShareToken)
+	result, err := s.bookRepository.UpdateMyBook(ctx, book) // Update the book
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to update book", zap.Uint64("book_id", b.ID), zap.Error(err))
+		return result, err
 	}
-	result := s.bookRepository.UpdateMyBook(book) // Update the book
-	return result
+	logging.With(ctx, s.logger).Info("Book updated", zap.Uint64("book_id", result.ID), zap.Uint64("user_id", result.UserID))
+	return showShareToken(ctx, result), nil
 }
 
 // DeleteMyBook method is used to delete a book by userID
-func (s *bookService) DeleteMyBook(b entity.Book) {
-	s.bookRepository.DeleteMyBook(b) // delete book
+func (s *bookService) DeleteMyBook(ctx context.Context, b entity.Book) error {
+	ctx, span := tracing.Start(ctx, "BookService.DeleteMyBook")
+	defer span.End()
+	if err := s.bookRepository.DeleteMyBook(ctx, b); err != nil { // delete book
+		logging.With(ctx, s.logger).Error("Failed to delete book", zap.Uint64("book_id", b.ID), zap.Error(err))
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Book deleted", zap.Uint64("book_id", b.ID))
+	return nil
+}
+
+// IsAllowedActionBook method is used to check userID is allowed the action on bookID by their role on the book
+func (s *bookService) IsAllowedActionBook(ctx context.Context, userID uint64, bookID uint64, action string) bool {
+	ctx, span := tracing.Start(ctx, "BookService.IsAllowedActionBook")
+	defer span.End()
+	switch role := s.bookRepository.GetRole(ctx, bookID, userID); action {
+	case BookActionView:
+		return role != ""
+	case BookActionUpdate:
+		return role == entity.CollaboratorOwner || role == entity.CollaboratorEditor
+	default:
+		return role == entity.CollaboratorOwner
+	}
+}
+
+// GetByISBN method is used to get all book with an ISBN-10 or ISBN-13
+func (s *bookService) GetByISBN(ctx context.Context, isbn string) []entity.Book {
+	ctx, span := tracing.Start(ctx, "BookService.GetByISBN")
+	defer span.End()
+	return showShareTokens(ctx, s.bookRepository.GetByISBN(ctx, helper.NormalizeISBN(isbn), viewerID(ctx)))
+}
+
+// IsDuplicateISBN method is used to check if userID has another book (than bookID) with the isbn
+func (s *bookService) IsDuplicateISBN(ctx context.Context, userID uint64, isbn string, bookID uint64) bool {
+	ctx, span := tracing.Start(ctx, "BookService.IsDuplicateISBN")
+	defer span.End()
+	if isbn == "" {
+		return false // Books without ISBN are never duplicates
+	}
+	res := s.bookRepository.IsDuplicateISBN(ctx, userID, helper.NormalizeISBN(isbn), bookID)
+	return res.Error == nil // A book was found
+}
+
+// viewerID returns the authenticated user of the request, 0 when it is anonymous
+func viewerID(ctx context.Context) uint64 {
+	principal, _ := auth.FromContext(ctx)
+	return principal.UserID
+}
+
+// showShareToken fills the share token of the response when the viewer owns the book, it is hidden from everyone else
+func showShareToken(ctx context.Context, b entity.Book) entity.Book {
+	if b.ID != 0 && b.UserID == viewerID(ctx) {
+		b.OwnerShareToken = b.ShareToken
+	}
+	return b
+}
+
+// showShareTokens fills the share tokens of the books owned by the viewer
+func showShareTokens(ctx context.Context, books []entity.Book) []entity.Book {
+	for i := range books {
+		books[i] = showShareToken(ctx, books[i])
+	}
+	return books
+}
+
+// shareToken returns the token of the share link of a book with the visibility, an unlisted book keeps its current token
+func shareToken(visibility string, current *string) *string {
+	if visibility != entity.VisibilityUnlisted {
+		return nil // Only unlisted books have a share link
+	}
+	if current != nil {
+		return current
+	}
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/rand never returns an error on supported platforms
+	token := hex.EncodeToString(b)
+	return &token
 }
 
-// IsAllowedActionBook method is used to check userID is allowed to access bookID or not by userID
-func (s *bookService) IsAllowedActionBook(userID string, bookID uint64) bool {
-	b := s.bookRepository.GetByID(bookID) // Get a book by bookID
-	id := fmt.Sprintf("%v", b.UserID)     // Get userID from book
-	return userID == id                   // Check userID is allowed to access bookID
+// normalizeISBN converts the isbn to ISBN-13, returns nil when the isbn is empty
+func normalizeISBN(isbn string) *string {
+	normalized := helper.NormalizeISBN(isbn)
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// IsValidAuthorIDs method is used to check if every authorID exists
+func (s *bookService) IsValidAuthorIDs(ctx context.Context, authorIDs []uint64) bool {
+	ctx, span := tracing.Start(ctx, "BookService.IsValidAuthorIDs")
+	defer span.End()
+	unique := make(map[uint64]bool, len(authorIDs))
+	for _, id := range authorIDs {
+		unique[id] = true
+	}
+	return len(s.authorRepository.GetByIDs(ctx, authorIDs)) == len(unique)
+}
+
+/*
+resolveAuthors returns the authors by ID when given, otherwise the authors named in the free-text author field.
+The named authors have no ID yet, the repository finds or creates them in the transaction of the book.
+*/
+func (s *bookService) resolveAuthors(ctx context.Context, authorIDs []uint64, author string) []entity.Author {
+	if len(authorIDs) > 0 {
+		return s.authorRepository.GetByIDs(ctx, authorIDs)
+	}
+	names := helper.SplitAuthorNames(author)
+	authors := make([]entity.Author, len(names))
+	for i, name := range names {
+		authors[i] = entity.Author{Name: name}
+	}
+	return authors
+}
+
+// IsValidCategoryIDs method is used to check if every categoryID exists
+func (s *bookService) IsValidCategoryIDs(ctx context.Context, categoryIDs []uint64) bool {
+	ctx, span := tracing.Start(ctx, "BookService.IsValidCategoryIDs")
+	defer span.End()
+	unique := make(map[uint64]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		unique[id] = true
+	}
+	return len(s.categoryRepository.GetByIDs(ctx, categoryIDs)) == len(unique)
+}
+
+// normalizeTags lower-cases the tags and removes the empty and repeated ones
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := helper.NormalizeTag(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
 }
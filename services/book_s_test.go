@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"go.uber.org/zap"
+)
+
+// fakeBookRepository serves a single book, the methods not overridden panic when called
+type fakeBookRepository struct {
+	repository.BookRepository
+	book  entity.Book
+	roles map[uint64]string // role of every user on the book
+}
+
+func (r *fakeBookRepository) GetByID(ctx context.Context, bookID uint64) entity.Book {
+	if bookID != r.book.ID {
+		return entity.Book{}
+	}
+	return r.book
+}
+
+func (r *fakeBookRepository) GetRole(ctx context.Context, bookID uint64, userID uint64) string {
+	if bookID != r.book.ID {
+		return ""
+	}
+	return r.roles[userID]
+}
+
+func TestBookServiceGetByIDVisibility(t *testing.T) {
+	const owner, collaborator, other = 1, 2, 3
+	tests := []struct {
+		name       string
+		visibility string
+		viewerID   uint64
+		found      bool
+	}{
+		{"anonymous private", entity.VisibilityPrivate, 0, false},
+		{"owner private", entity.VisibilityPrivate, owner, true},
+		{"collaborator private", entity.VisibilityPrivate, collaborator, true},
+		{"other user private", entity.VisibilityPrivate, other, false},
+		{"anonymous unlisted", entity.VisibilityUnlisted, 0, true},
+		{"other user unlisted", entity.VisibilityUnlisted, other, true},
+		{"anonymous public", entity.VisibilityPublic, 0, true},
+		{"other user public", entity.VisibilityPublic, other, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBookRepository{
+				book:  entity.Book{ID: 10, Title: "title", UserID: owner, Visibility: tt.visibility},
+				roles: map[uint64]string{owner: entity.CollaboratorOwner, collaborator: entity.CollaboratorViewer},
+			}
+			service := NewBookService(repo, nil, nil, nil, zap.NewNop())
+			ctx := context.Background()
+			if tt.viewerID != 0 {
+				ctx = auth.NewContext(ctx, auth.AuthPrincipal{UserID: tt.viewerID})
+			}
+			if got := service.GetByID(ctx, 10); (got.ID != 0) != tt.found {
+				t.Errorf("GetByID() = %+v, want found %v", got, tt.found)
+			}
+		})
+	}
+}
+
+func TestBookServiceGetByIDShareToken(t *testing.T) {
+	const owner, collaborator, other = 1, 2, 3
+	tests := []struct {
+		name     string
+		viewerID uint64
+		want     bool
+	}{
+		{"anonymous", 0, false},
+		{"owner", owner, true},
+		{"collaborator", collaborator, false},
+		{"other user", other, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := "0123456789abcdef0123456789abcdef"
+			repo := &fakeBookRepository{
+				book:  entity.Book{ID: 10, Title: "title", UserID: owner, Visibility: entity.VisibilityUnlisted, ShareToken: &token},
+				roles: map[uint64]string{owner: entity.CollaboratorOwner, collaborator: entity.CollaboratorEditor},
+			}
+			service := NewBookService(repo, nil, nil, nil, zap.NewNop())
+			ctx := context.Background()
+			if tt.viewerID != 0 {
+				ctx = auth.NewContext(ctx, auth.AuthPrincipal{UserID: tt.viewerID})
+			}
+			data, err := json.Marshal(service.GetByID(ctx, 10))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), token); got != tt.want {
+				t.Errorf("share token in %s = %v, want %v", data, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/money"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// CartService is a contract about what cart service can do
+type CartService interface {
+	GetCart(ctx context.Context, userID uint64) dto.CartDTOResponse                          // Get the cart of the user with its total
+	AddItem(ctx context.Context, item dto.CartItemCreateDTORequest) (entity.CartItem, error) // Add a book to the cart
+	RemoveItem(ctx context.Context, userID uint64, bookID uint64) error                      // Remove a book from the cart
+	ClearCart(ctx context.Context, userID uint64) error                                      // Remove every book from the cart
+	IsInCart(ctx context.Context, userID uint64, bookID uint64) bool                         // Check the book is in the cart of the user
+}
+
+// Create a cartService struct to implement CartService interface
+type cartService struct {
+	cartRepository repository.CartRepository
+	logger         *zap.Logger
+}
+
+// NewCartService method is used to create a new instance of cartService
+func NewCartService(cartRepo repository.CartRepository, logger *zap.Logger) CartService {
+	return &cartService{cartRepository: cartRepo, logger: logger}
+}
+
+// GetCart method is used to get the items of the cart of the user and the sums of the current prices of their books, one per currency
+func (s *cartService) GetCart(ctx context.Context, userID uint64) dto.CartDTOResponse {
+	ctx, span := tracing.Start(ctx, "CartService.GetCart")
+	defer span.End()
+	cart := dto.CartDTOResponse{Items: s.cartRepository.GetCart(ctx, userID), Totals: []money.Money{}}
+	totals := make(map[string]int) // index of the total of each currency
+	for _, item := range cart.Items {
+		if item.Book == nil {
+			continue
+		}
+		i, ok := totals[item.Book.Price.Currency]
+		if !ok {
+			i = len(cart.Totals)
+			totals[item.Book.Price.Currency] = i
+			cart.Totals = append(cart.Totals, money.New(0, item.Book.Price.Currency))
+		}
+		cart.Totals[i].Amount += item.Book.Price.Amount
+	}
+	return cart
+}
+
+// AddItem method is used to add a book to the cart of the user
+func (s *cartService) AddItem(ctx context.Context, item dto.CartItemCreateDTORequest) (entity.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.AddItem")
+	defer span.End()
+	result, err := s.cartRepository.InsertItem(ctx, entity.CartItem{UserID: item.UserID, BookID: item.BookID})
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to add book to cart", zap.Uint64("book_id", item.BookID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Book added to cart", zap.Uint64("book_id", item.BookID), zap.Uint64("user_id", item.UserID))
+	return result, nil
+}
+
+// RemoveItem method is used to remove a book from the cart of the user
+func (s *cartService) RemoveItem(ctx context.Context, userID uint64, bookID uint64) error {
+	ctx, span := tracing.Start(ctx, "CartService.RemoveItem")
+	defer span.End()
+	return s.cartRepository.DeleteItem(ctx, userID, bookID)
+}
+
+// ClearCart method is used to remove every book from the cart of the user
+func (s *cartService) ClearCart(ctx context.Context, userID uint64) error {
+	ctx, span := tracing.Start(ctx, "CartService.ClearCart")
+	defer span.End()
+	return s.cartRepository.ClearCart(ctx, userID)
+}
+
+// IsInCart method is used to check if the book is in the cart of the user
+func (s *cartService) IsInCart(ctx context.Context, userID uint64, bookID uint64) bool {
+	ctx, span := tracing.Start(ctx, "CartService.IsInCart")
+	defer span.End()
+	res := s.cartRepository.IsInCart(ctx, userID, bookID)
+	return res.Error == nil // An item was found
+}
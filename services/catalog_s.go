@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/catalog"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// maxBookTextLength is the size of the varchar columns of the book table
+const maxBookTextLength = 255
+
+// CatalogService is a contract of what the catalog service can do
+type CatalogService interface {
+	// PrefillBook fetches the metadata of the isbn and returns it as a book create request
+	PrefillBook(ctx context.Context, isbn string) (dto.BookFromISBNDTOResponse, error)
+}
+
+// Create a catalogService struct to implement CatalogService interface
+type catalogService struct {
+	provider catalog.CatalogProvider
+	logger   *zap.Logger
+}
+
+// NewCatalogService method is used to create a new instance of catalogService
+func NewCatalogService(provider catalog.CatalogProvider, logger *zap.Logger) CatalogService {
+	return &catalogService{provider: provider, logger: logger}
+}
+
+// PrefillBook fetches the metadata of the isbn and returns it as a book create request
+func (s *catalogService) PrefillBook(ctx context.Context, isbn string) (dto.BookFromISBNDTOResponse, error) {
+	ctx, span := tracing.Start(ctx, "CatalogService.PrefillBook")
+	defer span.End()
+
+	isbn = helper.NormalizeISBN(isbn) // Catalogs are queried with the ISBN-13
+	metadata, err := s.provider.LookupISBN(ctx, isbn)
+	if err != nil {
+		logging.With(ctx, s.logger).Info("Catalog lookup failed", zap.String("isbn", isbn), zap.Error(err))
+		return dto.BookFromISBNDTOResponse{}, err
+	}
+
+	// The price is not known by the catalog, the user fills it before creating the book
+	book := dto.BookCreateDTORequest{
+		Title:       truncate(metadata.Title, maxBookTextLength),
+		Author:      truncate(strings.Join(metadata.Authors, ", "), maxBookTextLength),
+		Description: truncate(metadata.Description, maxBookTextLength),
+		ISBN:        isbn,
+	}
+	return dto.BookFromISBNDTOResponse{Book: book, Metadata: metadata}, nil
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
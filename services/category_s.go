@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// CategoryService is a contract about what category service can do
+type CategoryService interface {
+	GetAll(ctx context.Context) []entity.Category                                                // Get all category with their book count
+	GetByID(ctx context.Context, categoryID uint64) entity.Category                              // Get a category by categoryID
+	GetByIDOrSlug(ctx context.Context, idOrSlug string) entity.Category                          // Get a category by id or slug
+	CreateCategory(ctx context.Context, c dto.CategoryCreateDTORequest) (entity.Category, error) // Create a new category
+	UpdateCategory(ctx context.Context, c dto.CategoryUpdateDTORequest) entity.Category          // Update name and slug of a category
+	MoveCategory(ctx context.Context, c entity.Category, parentID *uint64) error                 // Move a category and its subcategories
+	DeleteCategory(ctx context.Context, c entity.Category) error                                 // Delete a category
+	HasChildren(ctx context.Context, categoryID uint64) bool                                     // Check if the category has subcategories
+	IsDuplicateSlug(ctx context.Context, slug string, categoryID uint64) bool                    // Check if another category has the slug
+}
+
+// Create a categoryService struct to implement CategoryService interface
+type categoryService struct {
+	categoryRepository repository.CategoryRepository
+	logger             *zap.Logger
+}
+
+// NewCategoryService method is used to create a new instance of categoryService
+func NewCategoryService(categoryRepo repository.CategoryRepository, logger *zap.Logger) CategoryService {
+	return &categoryService{categoryRepository: categoryRepo, logger: logger}
+}
+
+// GetAll method is used to get all category with their book count
+func (s *categoryService) GetAll(ctx context.Context) []entity.Category {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetAll")
+	defer span.End()
+	return s.categoryRepository.GetAll(ctx)
+}
+
+// GetByID method is used to get a category by categoryID
+func (s *categoryService) GetByID(ctx context.Context, categoryID uint64) entity.Category {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetByID")
+	defer span.End()
+	return s.categoryRepository.GetByID(ctx, categoryID)
+}
+
+// GetByIDOrSlug method is used to get a category by numeric id or by slug
+func (s *categoryService) GetByIDOrSlug(ctx context.Context, idOrSlug string) entity.Category {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetByIDOrSlug")
+	defer span.End()
+	if id, err := strconv.ParseUint(idOrSlug, 10, 64); err == nil {
+		return s.categoryRepository.GetByID(ctx, id)
+	}
+	return s.categoryRepository.GetBySlug(ctx, helper.Slugify(idOrSlug))
+}
+
+// CreateCategory method is used to create a new category below its parent
+func (s *categoryService) CreateCategory(ctx context.Context, c dto.CategoryCreateDTORequest) (entity.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateCategory")
+	defer span.End()
+	category := entity.Category{Name: c.Name, Slug: c.Slug, ParentID: c.ParentID}
+	result, err := s.categoryRepository.InsertCategory(ctx, category) // Create the category
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to create category", zap.String("slug", category.Slug), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Category created", zap.Uint64("category_id", result.ID), zap.String("path", result.Path))
+	return result, nil
+}
+
+// UpdateCategory method is used to update name and slug of a category
+func (s *categoryService) UpdateCategory(ctx context.Context, c dto.CategoryUpdateDTORequest) entity.Category {
+	ctx, span := tracing.Start(ctx, "CategoryService.UpdateCategory")
+	defer span.End()
+	category := entity.Category{ID: c.ID, Name: c.Name, Slug: c.Slug}
+	result := s.categoryRepository.UpdateCategory(ctx, category) // Update the category
+	logging.With(ctx, s.logger).Info("Category updated", zap.Uint64("category_id", result.ID))
+	return result
+}
+
+// MoveCategory method is used to move a category and its subcategories below parentID, nil moves it to the root
+func (s *categoryService) MoveCategory(ctx context.Context, c entity.Category, parentID *uint64) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.MoveCategory")
+	defer span.End()
+	if err := s.categoryRepository.MoveCategory(ctx, c, parentID); err != nil {
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Category moved", zap.Uint64("category_id", c.ID), zap.Uint64p("parent_id", parentID))
+	return nil
+}
+
+// DeleteCategory method is used to delete a category
+func (s *categoryService) DeleteCategory(ctx context.Context, c entity.Category) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteCategory")
+	defer span.End()
+	if err := s.categoryRepository.DeleteCategory(ctx, c); err != nil { // delete category
+		logging.With(ctx, s.logger).Error("Failed to delete category", zap.Uint64("category_id", c.ID), zap.Error(err))
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Category deleted", zap.Uint64("category_id", c.ID))
+	return nil
+}
+
+// HasChildren method is used to check if the category has subcategories
+func (s *categoryService) HasChildren(ctx context.Context, categoryID uint64) bool {
+	ctx, span := tracing.Start(ctx, "CategoryService.HasChildren")
+	defer span.End()
+	return s.categoryRepository.CountChildren(ctx, categoryID) > 0
+}
+
+// IsDuplicateSlug method is used to check if another category has the slug
+func (s *categoryService) IsDuplicateSlug(ctx context.Context, slug string, categoryID uint64) bool {
+	ctx, span := tracing.Start(ctx, "CategoryService.IsDuplicateSlug")
+	defer span.End()
+	res := s.categoryRepository.IsDuplicateSlug(ctx, slug, categoryID)
+	return res.Error == nil // A category was found
+}
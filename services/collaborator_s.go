@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUserNotFound = errors.New("no user with this email")
+	ErrInviteOwner  = errors.New("the owner cannot be invited to their own book")
+)
+
+// CollaboratorService is a contract about what collaborator service can do
+type CollaboratorService interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.BookCollaborator                             // Get the collaborators of a book
+	GetByID(ctx context.Context, collaboratorID uint64) entity.BookCollaborator                         // Get a collaborator by collaboratorID
+	GetInvitations(ctx context.Context, userID uint64) []entity.BookCollaborator                        // Get the invitations waiting for the answer of a user
+	Invite(ctx context.Context, c dto.CollaboratorInviteDTORequest) (entity.BookCollaborator, error)    // Invite a user to a book
+	Accept(ctx context.Context, collaboratorID uint64, userID uint64) (entity.BookCollaborator, error)  // Accept an invitation
+	Decline(ctx context.Context, collaboratorID uint64, userID uint64) (entity.BookCollaborator, error) // Decline an invitation
+	Revoke(ctx context.Context, c entity.BookCollaborator) error                                        // Remove a collaborator from a book
+	TransferBook(ctx context.Context, bookID uint64, newOwnerID uint64) (entity.Book, error)            // Give a book to a collaborator
+}
+
+// Create a collaboratorService struct to implement CollaboratorService interface
+type collaboratorService struct {
+	collaboratorRepository repository.CollaboratorRepository
+	bookRepository         repository.BookRepository
+	userRepository         repository.UserRepository
+	logger                 *zap.Logger
+}
+
+// NewCollaboratorService method is used to create a new instance of collaboratorService
+func NewCollaboratorService(collaboratorRepo repository.CollaboratorRepository, bookRepo repository.BookRepository, userRepo repository.UserRepository, logger *zap.Logger) CollaboratorService {
+	return &collaboratorService{collaboratorRepository: collaboratorRepo, bookRepository: bookRepo, userRepository: userRepo, logger: logger}
+}
+
+// GetByBook method is used to get the collaborators of the book, invited and declined ones included
+func (s *collaboratorService) GetByBook(ctx context.Context, bookID uint64) []entity.BookCollaborator {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.GetByBook")
+	defer span.End()
+	return s.collaboratorRepository.GetByBook(ctx, bookID)
+}
+
+// GetByID method is used to get a collaborator by collaboratorID
+func (s *collaboratorService) GetByID(ctx context.Context, collaboratorID uint64) entity.BookCollaborator {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.GetByID")
+	defer span.End()
+	return s.collaboratorRepository.GetByID(ctx, collaboratorID)
+}
+
+// GetInvitations method is used to get the invitations waiting for the answer of the user
+func (s *collaboratorService) GetInvitations(ctx context.Context, userID uint64) []entity.BookCollaborator {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.GetInvitations")
+	defer span.End()
+	return s.collaboratorRepository.GetInvitations(ctx, userID)
+}
+
+// Invite method is used to invite the user of the email to the book with the role
+func (s *collaboratorService) Invite(ctx context.Context, c dto.CollaboratorInviteDTORequest) (entity.BookCollaborator, error) {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.Invite")
+	defer span.End()
+	user := s.userRepository.FindByEmail(ctx, strings.TrimSpace(c.Email))
+	if user.ID == 0 {
+		return entity.BookCollaborator{}, ErrUserNotFound
+	}
+	if s.bookRepository.GetRole(ctx, c.BookID, user.ID) == entity.CollaboratorOwner {
+		return entity.BookCollaborator{}, ErrInviteOwner
+	}
+	collaborator, err := s.collaboratorRepository.Invite(ctx, entity.BookCollaborator{BookID: c.BookID, UserID: user.ID, Role: c.Role, InviterID: c.InviterID})
+	if err != nil {
+		return collaborator, err
+	}
+	logging.With(ctx, s.logger).Info("Collaborator invited", zap.Uint64("book_id", c.BookID), zap.Uint64("user_id", user.ID), zap.String("role", c.Role))
+	return collaborator, nil
+}
+
+// Accept method is used to accept the invitation of the user, they get their role on the book
+func (s *collaboratorService) Accept(ctx context.Context, collaboratorID uint64, userID uint64) (entity.BookCollaborator, error) {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.Accept")
+	defer span.End()
+	return s.answer(ctx, collaboratorID, userID, entity.CollaboratorAccepted)
+}
+
+// Decline method is used to decline the invitation of the user
+func (s *collaboratorService) Decline(ctx context.Context, collaboratorID uint64, userID uint64) (entity.BookCollaborator, error) {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.Decline")
+	defer span.End()
+	return s.answer(ctx, collaboratorID, userID, entity.CollaboratorDeclined)
+}
+
+// Revoke method is used to remove the collaborator from the book, or cancel their invitation
+func (s *collaboratorService) Revoke(ctx context.Context, c entity.BookCollaborator) error {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.Revoke")
+	defer span.End()
+	if err := s.collaboratorRepository.Revoke(ctx, c); err != nil {
+		logging.With(ctx, s.logger).Error("Failed to revoke collaborator", zap.Uint64("book_id", c.BookID), zap.Uint64("user_id", c.UserID), zap.Error(err))
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Collaborator revoked", zap.Uint64("book_id", c.BookID), zap.Uint64("user_id", c.UserID))
+	return nil
+}
+
+// TransferBook method is used to give the book to a collaborator who accepted the invitation, the previous owner becomes an editor
+func (s *collaboratorService) TransferBook(ctx context.Context, bookID uint64, newOwnerID uint64) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "CollaboratorService.TransferBook")
+	defer span.End()
+	book := s.bookRepository.GetByID(ctx, bookID)
+	if book.ISBN != nil && s.bookRepository.IsDuplicateISBN(ctx, newOwnerID, *book.ISBN, bookID).Error == nil {
+		return book, ErrDuplicateISBN
+	}
+	if err := s.collaboratorRepository.TransferBook(ctx, bookID, newOwnerID); err != nil {
+		return book, err
+	}
+	logging.With(ctx, s.logger).Info("Book transferred", zap.Uint64("book_id", bookID), zap.Uint64("from_user_id", book.UserID), zap.Uint64("to_user_id", newOwnerID))
+	return s.bookRepository.GetByID(ctx, bookID), nil
+}
+
+// answer sets the status of the invitation when it waits for the answer of the user
+func (s *collaboratorService) answer(ctx context.Context, collaboratorID uint64, userID uint64, status string) (entity.BookCollaborator, error) {
+	collaborator, err := s.collaboratorRepository.Answer(ctx, collaboratorID, userID, status)
+	if err != nil {
+		return collaborator, err
+	}
+	logging.With(ctx, s.logger).Info("Invitation answered", zap.Uint64("book_id", collaborator.BookID), zap.Uint64("user_id", userID), zap.String("status", status))
+	return collaborator, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/storage"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// Errors returned by CoverService.UploadCover for invalid images
+var (
+	ErrCoverTooLarge      = errors.New("cover image is too large")
+	ErrUnsupportedImage   = errors.New("cover must be a JPEG, PNG, GIF or WebP image")
+	ErrImageTooManyPixels = errors.New("cover image has too many pixels")
+)
+
+// Sizes of the cover variants, the longest side is scaled down to these pixels
+const (
+	coverMediumSize    = 600
+	coverThumbnailSize = 150
+	coverMaxPixels     = 40_000_000 // refuse to decode larger images
+)
+
+// coverExtensions are the accepted sniffed content types with the extension of the stored original
+var coverExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// CoverService is a contract about what cover service can do
+type CoverService interface {
+	UploadCover(ctx context.Context, book entity.Book, r io.Reader) (entity.Book, error) // Store a new cover of the book with its variants
+	DeleteCover(ctx context.Context, book entity.Book) (entity.Book, error)              // Remove the cover of the book
+	MaxSize() int64                                                                      // Largest accepted image in bytes
+}
+
+// Create a coverService struct to implement CoverService interface
+type coverService struct {
+	bookRepository repository.BookRepository
+	store          storage.BlobStore
+	maxSize        int64
+	logger         *zap.Logger
+}
+
+// NewCoverService method is used to create a new instance of coverService
+func NewCoverService(bookRepo repository.BookRepository, store storage.BlobStore, maxSize int64, logger *zap.Logger) CoverService {
+	return &coverService{bookRepository: bookRepo, store: store, maxSize: maxSize, logger: logger}
+}
+
+// MaxSize method is used to get the largest accepted image in bytes
+func (s *coverService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadCover method is used to check the image by its content, store it with a medium and a thumbnail variant and replace the previous cover
+func (s *coverService) UploadCover(ctx context.Context, book entity.Book, r io.Reader) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "CoverService.UploadCover")
+	defer span.End()
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return book, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return book, ErrCoverTooLarge
+	}
+
+	// Trust the content, not the file name or the declared content type
+	contentType := http.DetectContentType(data)
+	ext, ok := coverExtensions[contentType]
+	if !ok {
+		return book, ErrUnsupportedImage
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return book, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > coverMaxPixels {
+		return book, ErrImageTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return book, ErrUnsupportedImage
+	}
+
+	medium, err := helper.EncodeJPEG(helper.ResizeImage(img, coverMediumSize), 85)
+	if err != nil {
+		return book, err
+	}
+	thumbnail, err := helper.EncodeJPEG(helper.ResizeImage(img, coverThumbnailSize), 80)
+	if err != nil {
+		return book, err
+	}
+
+	// A new prefix for every upload, so cached URLs of the previous cover are never reused
+	prefix, err := coverPrefix(book.ID)
+	if err != nil {
+		return book, err
+	}
+	blobs := []struct {
+		key         string
+		data        []byte
+		contentType string
+	}{
+		{prefix + "/original" + ext, data, contentType},
+		{prefix + "/medium.jpg", medium, "image/jpeg"},
+		{prefix + "/thumbnail.jpg", thumbnail, "image/jpeg"},
+	}
+	for _, blob := range blobs {
+		if err := s.store.Put(ctx, blob.key, bytes.NewReader(blob.data), int64(len(blob.data)), blob.contentType); err != nil {
+			return book, err
+		}
+	}
+
+	cover := entity.BookCover{
+		Key:          blobs[0].key,
+		URL:          s.store.URL(blobs[0].key),
+		MediumURL:    s.store.URL(blobs[1].key),
+		ThumbnailURL: s.store.URL(blobs[2].key),
+	}
+	if err := s.bookRepository.UpdateCover(ctx, book.ID, cover); err != nil {
+		return book, err
+	}
+	s.deleteBlobs(ctx, book.Cover) // the previous cover is not referenced anymore
+
+	logging.With(ctx, s.logger).Info("Cover uploaded", zap.Uint64("book_id", book.ID), zap.String("content_type", contentType), zap.Int("size", len(data)))
+	return s.bookRepository.GetByID(ctx, book.ID), nil
+}
+
+// DeleteCover method is used to remove the cover of the book and its stored images
+func (s *coverService) DeleteCover(ctx context.Context, book entity.Book) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "CoverService.DeleteCover")
+	defer span.End()
+	if err := s.bookRepository.UpdateCover(ctx, book.ID, entity.BookCover{}); err != nil {
+		return book, err
+	}
+	s.deleteBlobs(ctx, book.Cover)
+	logging.With(ctx, s.logger).Info("Cover deleted", zap.Uint64("book_id", book.ID))
+	return s.bookRepository.GetByID(ctx, book.ID), nil
+}
+
+// deleteBlobs removes the stored images of the cover, failures are only logged as the cover is already unlinked
+func (s *coverService) deleteBlobs(ctx context.Context, cover entity.BookCover) {
+	if cover.Key == "" {
+		return
+	}
+	prefix := path.Dir(cover.Key)
+	for _, key := range []string{cover.Key, prefix + "/medium.jpg", prefix + "/thumbnail.jpg"} {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logging.With(ctx, s.logger).Warn("Failed to delete cover image", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// coverPrefix returns a new random storage prefix for a cover of the book
+func coverPrefix(bookID uint64) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("covers/%d/%s", bookID, hex.EncodeToString(b)), nil
+}
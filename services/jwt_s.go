@@ -1,23 +1,30 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/auth"
 )
 
 // JWT Service is a contract of what a JWT Service should be able to do.
 type JWTService interface {
-	GenerateToken(userID string) string             // Generate a new token
-	ValidateToken(token string) (*jwt.Token, error) // Validate the token
+	GenerateToken(userID string, roles []string) string      // Generate a new token
+	ValidateToken(token string) (*jwt.Token, error)          // Validate the token
+	ParsePrincipal(token string) (auth.AuthPrincipal, error) // Validate the token and return its principal
 }
 
 // jwtCustomClaims is a struct that contains the custom claims for the JWT
 type jwtCustomClaim struct {
-	UserID             string `json:"user_id"` // The userId is the only required field
-	jwt.StandardClaims        // This is a standard JWT claim
+	UserID             string   `json:"user_id"` // The userId is the only required field
+	Roles              []string `json:"roles,omitempty"`
+	jwt.StandardClaims          // This is a standard JWT claim
 }
 
 // jwtService is a struct that implements the JWTService interface
@@ -44,12 +51,14 @@ func getSecretKey() string {
 }
 
 // Create a new token object, specifying signing method and the claims
-func (s *jwtService) GenerateToken(userID string) string {
+func (s *jwtService) GenerateToken(userID string, roles []string) string {
 
 	// Create the Claims struct with the required claims for the JWT
 	claims := &jwtCustomClaim{
 		userID, // userId is the only required field
+		roles,  // roles granted to the user
 		jwt.StandardClaims{
+			Id:        newTokenID(),                       // unique token id (jti)
 			ExpiresAt: time.Now().AddDate(1, 0, 0).Unix(), // 1 year expiration
 			Issuer:    s.issuer,                           // Who creates the token
 			IssuedAt:  time.Now().Unix(),                  // when the token was issued/created (now)
@@ -66,10 +75,41 @@ func (s *jwtService) GenerateToken(userID string) string {
 // ValidateToken validates the token and returns the claims
 func (s *jwtService) ValidateToken(token string) (*jwt.Token, error) {
 	// Parse the token
-	return jwt.Parse(token, func(t_ *jwt.Token) (interface{}, error) {
-		if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok { // Check the signing method
-			return nil, fmt.Errorf("Unexpected signing method %v", t_.Header["alg"]) // Return an error if the signing method isn't HMAC
-		}
-		return []byte(s.secretKey), nil // Return the key
-	})
+	return jwt.Parse(token, s.keyFunc)
+}
+
+// ParsePrincipal validates the token (with or without the Bearer prefix) and returns the principal it identifies
+func (s *jwtService) ParsePrincipal(token string) (auth.AuthPrincipal, error) {
+	token = strings.TrimPrefix(token, "Bearer ") // Accept "Authorization: Bearer <token>" as well
+
+	claims := &jwtCustomClaim{}
+	t, err := jwt.ParseWithClaims(token, claims, s.keyFunc) // Parse the token once into the typed claims
+	if err != nil {
+		return auth.AuthPrincipal{}, err
+	}
+	if !t.Valid {
+		return auth.AuthPrincipal{}, fmt.Errorf("Token is not valid")
+	}
+
+	userID, err := strconv.ParseUint(claims.UserID, 10, 64) // Parse the user_id claim
+	if err != nil {
+		return auth.AuthPrincipal{}, fmt.Errorf("Invalid user_id claim: %v", err)
+	}
+
+	return auth.AuthPrincipal{UserID: userID, Roles: claims.Roles, TokenID: claims.Id}, nil
+}
+
+// keyFunc checks the signing method and returns the key used to verify the token
+func (s *jwtService) keyFunc(t_ *jwt.Token) (interface{}, error) {
+	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok { // Check the signing method
+		return nil, fmt.Errorf("Unexpected signing method %v", t_.Header["alg"]) // Return an error if the signing method isn't HMAC
+	}
+	return []byte(s.secretKey), nil // Return the key
+}
+
+// newTokenID creates a random token id
+func newTokenID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/rand never returns an error on supported platforms
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// LoanService is a contract about what loan service can do
+type LoanService interface {
+	GetByID(ctx context.Context, loanID uint64) entity.Loan                                             // Get a loan
+	GetBorrowed(ctx context.Context, userID uint64, f dto.LoanFilterDTORequest) []entity.Loan           // Get the loans of the user as borrower
+	GetLent(ctx context.Context, userID uint64, f dto.LoanFilterDTORequest) []entity.Loan               // Get the loans of the user as lender
+	GetByBook(ctx context.Context, bookID uint64) []entity.Loan                                         // Get the loan history of a book
+	RequestLoan(ctx context.Context, book entity.Book, l dto.LoanCreateDTORequest) (entity.Loan, error) // Ask the owner to lend the book
+	ApproveLoan(ctx context.Context, l entity.Loan, dueAt time.Time) (entity.Loan, error)               // Lend the book until dueAt
+	RejectLoan(ctx context.Context, l entity.Loan) (entity.Loan, error)                                 // Decline the request
+	CancelLoan(ctx context.Context, l entity.Loan) (entity.Loan, error)                                 // Withdraw the request
+	ReturnLoan(ctx context.Context, l entity.Loan) (entity.Loan, error)                                 // Record the return of the book
+	HasOpenLoan(ctx context.Context, bookID uint64, borrowerID uint64) bool                             // Check the borrower already asked for or has the book
+}
+
+// Create a loanService struct to implement LoanService interface
+type loanService struct {
+	loanRepository repository.LoanRepository
+	logger         *zap.Logger
+}
+
+// NewLoanService method is used to create a new instance of loanService
+func NewLoanService(loanRepo repository.LoanRepository, logger *zap.Logger) LoanService {
+	return &loanService{loanRepository: loanRepo, logger: logger}
+}
+
+// GetByID method is used to get a loan with its book, borrower and lender
+func (s *loanService) GetByID(ctx context.Context, loanID uint64) entity.Loan {
+	ctx, span := tracing.Start(ctx, "LoanService.GetByID")
+	defer span.End()
+	return s.loanRepository.GetByID(ctx, loanID)
+}
+
+// GetBorrowed method is used to get the loans of the user as borrower, newest first
+func (s *loanService) GetBorrowed(ctx context.Context, userID uint64, f dto.LoanFilterDTORequest) []entity.Loan {
+	ctx, span := tracing.Start(ctx, "LoanService.GetBorrowed")
+	defer span.End()
+	filter := loanFilter(f)
+	filter.BorrowerID = userID
+	return s.loanRepository.GetLoans(ctx, filter)
+}
+
+// GetLent method is used to get the loans of the user as lender, newest first
+func (s *loanService) GetLent(ctx context.Context, userID uint64, f dto.LoanFilterDTORequest) []entity.Loan {
+	ctx, span := tracing.Start(ctx, "LoanService.GetLent")
+	defer span.End()
+	filter := loanFilter(f)
+	filter.LenderID = userID
+	return s.loanRepository.GetLoans(ctx, filter)
+}
+
+// GetByBook method is used to get the loan history of a book, newest first
+func (s *loanService) GetByBook(ctx context.Context, bookID uint64) []entity.Loan {
+	ctx, span := tracing.Start(ctx, "LoanService.GetByBook")
+	defer span.End()
+	return s.loanRepository.GetLoans(ctx, repository.LoanFilter{BookID: bookID})
+}
+
+// RequestLoan method is used to ask the owner of the book to lend it to the borrower
+func (s *loanService) RequestLoan(ctx context.Context, book entity.Book, l dto.LoanCreateDTORequest) (entity.Loan, error) {
+	ctx, span := tracing.Start(ctx, "LoanService.RequestLoan")
+	defer span.End()
+	loan := entity.Loan{
+		BookID:     book.ID,
+		BorrowerID: l.BorrowerID,
+		LenderID:   book.UserID,
+		Status:     entity.LoanRequested,
+		Note:       l.Note,
+		DueAt:      l.DueAt,
+	}
+	result, err := s.loanRepository.InsertLoan(ctx, loan)
+	if err != nil {
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Loan requested", zap.Uint64("loan_id", result.ID), zap.Uint64("book_id", book.ID), zap.Uint64("borrower_id", l.BorrowerID))
+	return result, nil
+}
+
+// ApproveLoan method is used to lend the book of the requested loan until dueAt
+func (s *loanService) ApproveLoan(ctx context.Context, l entity.Loan, dueAt time.Time) (entity.Loan, error) {
+	ctx, span := tracing.Start(ctx, "LoanService.ApproveLoan")
+	defer span.End()
+	if err := s.loanRepository.ApproveLoan(ctx, l.ID, l.BookID, dueAt); err != nil {
+		return l, err
+	}
+	logging.With(ctx, s.logger).Info("Loan approved", zap.Uint64("loan_id", l.ID), zap.Time("due_at", dueAt))
+	return s.loanRepository.GetByID(ctx, l.ID), nil
+}
+
+// RejectLoan method is used to decline the requested loan
+func (s *loanService) RejectLoan(ctx context.Context, l entity.Loan) (entity.Loan, error) {
+	ctx, span := tracing.Start(ctx, "LoanService.RejectLoan")
+	defer span.End()
+	return s.changeStatus(ctx, l, entity.LoanRequested, entity.LoanRejected)
+}
+
+// CancelLoan method is used to withdraw the requested loan
+func (s *loanService) CancelLoan(ctx context.Context, l entity.Loan) (entity.Loan, error) {
+	ctx, span := tracing.Start(ctx, "LoanService.CancelLoan")
+	defer span.End()
+	return s.changeStatus(ctx, l, entity.LoanRequested, entity.LoanCancelled)
+}
+
+// ReturnLoan method is used to record the return of the book of the active loan
+func (s *loanService) ReturnLoan(ctx context.Context, l entity.Loan) (entity.Loan, error) {
+	ctx, span := tracing.Start(ctx, "LoanService.ReturnLoan")
+	defer span.End()
+	if err := s.loanRepository.ReturnLoan(ctx, l.ID, l.BookID); err != nil {
+		return l, err
+	}
+	logging.With(ctx, s.logger).Info("Loan returned", zap.Uint64("loan_id", l.ID), zap.Bool("overdue", l.Overdue))
+	return s.loanRepository.GetByID(ctx, l.ID), nil
+}
+
+// HasOpenLoan method is used to check if the borrower already asked for or has the book
+func (s *loanService) HasOpenLoan(ctx context.Context, bookID uint64, borrowerID uint64) bool {
+	ctx, span := tracing.Start(ctx, "LoanService.HasOpenLoan")
+	defer span.End()
+	res := s.loanRepository.HasOpenLoan(ctx, bookID, borrowerID)
+	return res.Error == nil // A loan was found
+}
+
+// changeStatus moves the loan from one status to another
+func (s *loanService) changeStatus(ctx context.Context, l entity.Loan, from string, to string) (entity.Loan, error) {
+	if err := s.loanRepository.ChangeStatus(ctx, l.ID, from, to); err != nil {
+		return l, err
+	}
+	logging.With(ctx, s.logger).Info("Loan status changed", zap.Uint64("loan_id", l.ID), zap.String("status", to))
+	return s.loanRepository.GetByID(ctx, l.ID), nil
+}
+
+// loanFilter returns the repository filter of the list request
+func loanFilter(f dto.LoanFilterDTORequest) repository.LoanFilter {
+	filter := repository.LoanFilter{Status: f.Status}
+	if f.Overdue {
+		filter.OverdueAt = time.Now()
+	}
+	return filter
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// OrderService is a contract about what order service can do
+type OrderService interface {
+	GetByID(ctx context.Context, orderID uint64) entity.Order                                    // Get an order
+	GetPurchases(ctx context.Context, userID uint64, f dto.OrderFilterDTORequest) []entity.Order // Get the orders of the user as buyer
+	GetSales(ctx context.Context, userID uint64, f dto.OrderFilterDTORequest) []entity.Order     // Get the orders of the user as seller
+	Checkout(ctx context.Context, userID uint64) ([]entity.Order, error)                         // Turn the cart of the user into orders
+	ChangeStatus(ctx context.Context, o entity.Order, status string) (entity.Order, error)       // Move the order to the status
+}
+
+// Create an orderService struct to implement OrderService interface
+type orderService struct {
+	orderRepository repository.OrderRepository
+	logger          *zap.Logger
+}
+
+// NewOrderService method is used to create a new instance of orderService
+func NewOrderService(orderRepo repository.OrderRepository, logger *zap.Logger) OrderService {
+	return &orderService{orderRepository: orderRepo, logger: logger}
+}
+
+// GetByID method is used to get an order with its items, buyer and seller
+func (s *orderService) GetByID(ctx context.Context, orderID uint64) entity.Order {
+	ctx, span := tracing.Start(ctx, "OrderService.GetByID")
+	defer span.End()
+	return s.orderRepository.GetByID(ctx, orderID)
+}
+
+// GetPurchases method is used to get the orders of the user as buyer, newest first
+func (s *orderService) GetPurchases(ctx context.Context, userID uint64, f dto.OrderFilterDTORequest) []entity.Order {
+	ctx, span := tracing.Start(ctx, "OrderService.GetPurchases")
+	defer span.End()
+	return s.orderRepository.GetOrders(ctx, repository.OrderFilter{BuyerID: userID, Status: f.Status})
+}
+
+// GetSales method is used to get the orders of the user as seller, newest first
+func (s *orderService) GetSales(ctx context.Context, userID uint64, f dto.OrderFilterDTORequest) []entity.Order {
+	ctx, span := tracing.Start(ctx, "OrderService.GetSales")
+	defer span.End()
+	return s.orderRepository.GetOrders(ctx, repository.OrderFilter{SellerID: userID, Status: f.Status})
+}
+
+// Checkout method is used to turn the cart of the user into one pending order per seller, the cart is emptied
+func (s *orderService) Checkout(ctx context.Context, userID uint64) ([]entity.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.Checkout")
+	defer span.End()
+	orders, err := s.orderRepository.Checkout(ctx, userID)
+	if err != nil {
+		logging.With(ctx, s.logger).Info("Checkout refused", zap.Uint64("user_id", userID), zap.Error(err))
+		return orders, err
+	}
+	for _, order := range orders {
+		logging.With(ctx, s.logger).Info("Order created", zap.Uint64("order_id", order.ID), zap.Uint64("seller_id", order.SellerID), zap.Stringer("total", order.Total))
+	}
+	return orders, nil
+}
+
+// ChangeStatus method is used to move the order from its status to the status, the caller checks the transition is allowed
+func (s *orderService) ChangeStatus(ctx context.Context, o entity.Order, status string) (entity.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.ChangeStatus")
+	defer span.End()
+	result, err := s.orderRepository.ChangeStatus(ctx, o.ID, o.Status, status)
+	if err != nil {
+		return o, err
+	}
+	logging.With(ctx, s.logger).Info("Order status changed", zap.Uint64("order_id", o.ID), zap.String("from", o.Status), zap.String("status", status))
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/payment"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// ErrPaymentFailed is returned when the payment gateway did not take the payment of a purchase
+var ErrPaymentFailed = errors.New("payment failed")
+
+// ErrNotRefundable is returned when an order cannot be refunded through the payment gateway
+var ErrNotRefundable = errors.New("order was not paid through the payment gateway")
+
+// PaymentService is a contract about what payment service can do
+type PaymentService interface {
+	Purchase(ctx context.Context, book entity.Book, buyerID uint64) (entity.Order, payment.Intent, error) // Order the book and pay it through the gateway
+	Refund(ctx context.Context, o entity.Order) (entity.Order, error)                                     // Ask the gateway to pay the order back
+	HandleEvent(ctx context.Context, e payment.Event) (entity.Order, bool, error)                         // Apply an event sent by the gateway to the webhook
+}
+
+// Create a paymentService struct to implement PaymentService interface
+type paymentService struct {
+	orderRepository repository.OrderRepository
+	gateway         payment.PaymentGateway
+	logger          *zap.Logger
+}
+
+// NewPaymentService method is used to create a new instance of paymentService
+func NewPaymentService(orderRepo repository.OrderRepository, gateway payment.PaymentGateway, logger *zap.Logger) PaymentService {
+	return &paymentService{orderRepository: orderRepo, gateway: gateway, logger: logger}
+}
+
+// Purchase method is used to order the book alone at its current price and take the payment through the gateway.
+// The order stays pending until the gateway reports the payment to the webhook, it is cancelled when the gateway refuses the payment.
+// A free book is paid at once without going through the gateway
+func (s *paymentService) Purchase(ctx context.Context, book entity.Book, buyerID uint64) (entity.Order, payment.Intent, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.Purchase")
+	defer span.End()
+	order, err := s.orderRepository.Purchase(ctx, buyerID, book.ID)
+	if err != nil {
+		return order, payment.Intent{}, err
+	}
+	if order.Total.Amount == 0 {
+		order, err = s.orderRepository.ChangeStatus(ctx, order.ID, entity.OrderPending, entity.OrderPaid)
+		if err == nil {
+			logging.With(ctx, s.logger).Info("Free book ordered", zap.Uint64("order_id", order.ID), zap.Uint64("book_id", book.ID))
+		}
+		return order, payment.Intent{}, err
+	}
+
+	intent, err := s.gateway.CreateIntent(ctx, order.Total.Amount, order.Total.Currency, "order-"+strconv.FormatUint(order.ID, 10))
+	if err == nil {
+		err = s.orderRepository.SetPaymentIntent(ctx, order.ID, intent.ID)
+	}
+	if err == nil {
+		intent, err = s.gateway.Capture(ctx, intent.ID)
+	}
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Payment failed", zap.Uint64("order_id", order.ID), zap.Error(err))
+		if _, cancelErr := s.orderRepository.ChangeStatus(ctx, order.ID, entity.OrderPending, entity.OrderCancelled); cancelErr != nil {
+			logging.With(ctx, s.logger).Error("Failed to cancel unpaid order", zap.Uint64("order_id", order.ID), zap.Error(cancelErr))
+		}
+		return order, intent, ErrPaymentFailed
+	}
+
+	logging.With(ctx, s.logger).Info("Book purchased", zap.Uint64("order_id", order.ID), zap.Uint64("book_id", book.ID), zap.String("intent_id", intent.ID))
+	return s.orderRepository.GetByID(ctx, order.ID), intent, nil
+}
+
+// Refund method is used to ask the gateway to pay the order back, the order is refunded when the gateway reports it to the webhook
+func (s *paymentService) Refund(ctx context.Context, o entity.Order) (entity.Order, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.Refund")
+	defer span.End()
+	if o.PaymentIntentID == nil || (o.Status != entity.OrderPaid && o.Status != entity.OrderShipped) {
+		return o, ErrNotRefundable
+	}
+	if _, err := s.gateway.Refund(ctx, *o.PaymentIntentID); err != nil {
+		logging.With(ctx, s.logger).Error("Refund failed", zap.Uint64("order_id", o.ID), zap.Error(err))
+		return o, err
+	}
+	logging.With(ctx, s.logger).Info("Refund requested", zap.Uint64("order_id", o.ID), zap.String("intent_id", *o.PaymentIntentID))
+	return o, nil
+}
+
+// HandleEvent method is used to apply an event of the gateway to its order once, false when the event was already applied.
+// A payment that succeeds after its order was cancelled is refunded
+func (s *paymentService) HandleEvent(ctx context.Context, e payment.Event) (entity.Order, bool, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.HandleEvent")
+	defer span.End()
+	order, applied, err := s.orderRepository.ApplyPaymentEvent(ctx, entity.PaymentEvent{ID: e.ID, Type: e.Type, IntentID: e.IntentID})
+	if err != nil || !applied {
+		return order, applied, err
+	}
+	logging.With(ctx, s.logger).Info("Payment event applied", zap.String("event_id", e.ID), zap.String("type", e.Type), zap.Uint64("order_id", order.ID), zap.String("status", order.Status))
+
+	if e.Type == payment.EventPaymentSucceeded && order.Status == entity.OrderCancelled {
+		if _, err := s.gateway.Refund(ctx, e.IntentID); err != nil {
+			logging.With(ctx, s.logger).Error("Failed to refund the payment of a cancelled order", zap.Uint64("order_id", order.ID), zap.Error(err))
+		}
+	}
+	return order, true, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// maxReportPeriods is the most days or weeks a report counts at once
+const maxReportPeriods = 366
+
+// ErrInvalidRange is returned when the first day of a report is after its last day or the report has too many periods
+var ErrInvalidRange = errors.New("from must be before to and the range at most 366 periods")
+
+// ReportService is a contract about what report service can do
+type ReportService interface {
+	BooksCreated(ctx context.Context, q dto.ReportPeriodDTORequest) ([]repository.PeriodCount, error) // Count the books created per day or week
+	ActiveUsers(ctx context.Context, q dto.ReportPeriodDTORequest) ([]repository.PeriodCount, error)  // Count the active users per day or week
+	TopAuthors(ctx context.Context, q dto.ReportRankingDTORequest) []repository.AuthorReport          // Get the authors with the most books
+	PriceDistribution(ctx context.Context, q dto.ReportPriceDTORequest) []repository.PriceBucket      // Count the books per price range
+	BooksPerOwner(ctx context.Context, q dto.ReportRankingDTORequest) []repository.OwnerReport        // Get the users owning the most books
+}
+
+// Create a reportService struct to implement ReportService interface
+type reportService struct {
+	reportRepository repository.ReportRepository
+	location         *time.Location // time zone the days and weeks are cut in
+	logger           *zap.Logger
+}
+
+// NewReportService method is used to create a new instance of reportService
+func NewReportService(reportRepo repository.ReportRepository, location *time.Location, logger *zap.Logger) ReportService {
+	return &reportService{reportRepository: reportRepo, location: location, logger: logger}
+}
+
+// BooksCreated method is used to count the books created in every day or week of the range
+func (s *reportService) BooksCreated(ctx context.Context, q dto.ReportPeriodDTORequest) ([]repository.PeriodCount, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.BooksCreated")
+	defer span.End()
+	bounds, err := s.periodBounds(q, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.reportRepository.BooksCreated(ctx, bounds), nil
+}
+
+// ActiveUsers method is used to count the users active in every day or week of the range
+func (s *reportService) ActiveUsers(ctx context.Context, q dto.ReportPeriodDTORequest) ([]repository.PeriodCount, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.ActiveUsers")
+	defer span.End()
+	bounds, err := s.periodBounds(q, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.reportRepository.ActiveUsers(ctx, bounds), nil
+}
+
+// TopAuthors method is used to get the authors with the most books
+func (s *reportService) TopAuthors(ctx context.Context, q dto.ReportRankingDTORequest) []repository.AuthorReport {
+	ctx, span := tracing.Start(ctx, "ReportService.TopAuthors")
+	defer span.End()
+	return s.reportRepository.TopAuthors(ctx, rankingLimit(q.Limit))
+}
+
+// PriceDistribution method is used to count the books of every price range
+func (s *reportService) PriceDistribution(ctx context.Context, q dto.ReportPriceDTORequest) []repository.PriceBucket {
+	ctx, span := tracing.Start(ctx, "ReportService.PriceDistribution")
+	defer span.End()
+	width := q.Width
+	if width == 0 {
+		width = 1000
+	}
+	return s.reportRepository.PriceDistribution(ctx, q.Currency, width)
+}
+
+// BooksPerOwner method is used to get the users owning the most books
+func (s *reportService) BooksPerOwner(ctx context.Context, q dto.ReportRankingDTORequest) []repository.OwnerReport {
+	ctx, span := tracing.Start(ctx, "ReportService.BooksPerOwner")
+	defer span.End()
+	return s.reportRepository.BooksPerOwner(ctx, rankingLimit(q.Limit))
+}
+
+/*
+periodBounds returns the starts of the days or weeks (from Monday) of the range in the time zone of the reports,
+followed by the end of the last one. The range ends today and covers 30 days or 12 weeks when not given
+*/
+func (s *reportService) periodBounds(q dto.ReportPeriodDTORequest, now time.Time) ([]time.Time, error) {
+	now = now.In(s.location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	if q.To != "" {
+		to, _ = time.ParseInLocation("2006-01-02", q.To, s.location) // the DTO has checked the format
+	}
+
+	days := 1
+	from := to.AddDate(0, 0, -29)
+	if q.Period == "week" {
+		days = 7
+		to = to.AddDate(0, 0, -(int(to.Weekday())+6)%7) // Monday of the week of to
+		from = to.AddDate(0, 0, -7*11)
+	}
+	if q.From != "" {
+		from, _ = time.ParseInLocation("2006-01-02", q.From, s.location)
+		if q.Period == "week" {
+			from = from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+		}
+	}
+	if from.After(to) {
+		return nil, ErrInvalidRange
+	}
+
+	var bounds []time.Time
+	for start := from; !start.After(to); start = start.AddDate(0, 0, days) {
+		if len(bounds) == maxReportPeriods {
+			return nil, ErrInvalidRange
+		}
+		bounds = append(bounds, start)
+	}
+	return append(bounds, to.AddDate(0, 0, days)), nil
+}
+
+// rankingLimit returns the number of rows of a ranking, 10 when not given
+func rankingLimit(limit int) int {
+	if limit == 0 {
+		return 10
+	}
+	return limit
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// ReviewService is a contract about what review service can do
+type ReviewService interface {
+	GetByBook(ctx context.Context, bookID uint64) []entity.Review                          // Get all review of a book
+	GetByID(ctx context.Context, reviewID uint64) entity.Review                            // Get a review by reviewID
+	CreateReview(ctx context.Context, r dto.ReviewCreateDTORequest) (entity.Review, error) // Create a new review
+	UpdateReview(ctx context.Context, r dto.ReviewUpdateDTORequest) (entity.Review, error) // Update a review
+	DeleteReview(ctx context.Context, r entity.Review) error                               // Delete a review
+	IsDuplicateReview(ctx context.Context, bookID uint64, userID uint64) bool              // Check if the user already reviewed the book
+}
+
+// Create a reviewService struct to implement ReviewService interface
+type reviewService struct {
+	reviewRepository repository.ReviewRepository
+	logger           *zap.Logger
+}
+
+// NewReviewService method is used to create a new instance of reviewService
+func NewReviewService(reviewRepo repository.ReviewRepository, logger *zap.Logger) ReviewService {
+	return &reviewService{reviewRepository: reviewRepo, logger: logger}
+}
+
+// GetByBook method is used to get all review of a book, newest first
+func (s *reviewService) GetByBook(ctx context.Context, bookID uint64) []entity.Review {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetByBook")
+	defer span.End()
+	return s.reviewRepository.GetByBook(ctx, bookID)
+}
+
+// GetByID method is used to get a review by reviewID
+func (s *reviewService) GetByID(ctx context.Context, reviewID uint64) entity.Review {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetByID")
+	defer span.End()
+	return s.reviewRepository.GetByID(ctx, reviewID)
+}
+
+// CreateReview method is used to create a new review, the rating of the book is refreshed in the same transaction
+func (s *reviewService) CreateReview(ctx context.Context, r dto.ReviewCreateDTORequest) (entity.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.CreateReview")
+	defer span.End()
+	review := entity.Review{BookID: r.BookID, UserID: r.UserID, Rating: r.Rating, Text: r.Text}
+	result, err := s.reviewRepository.InsertReview(ctx, review)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to create review", zap.Uint64("book_id", r.BookID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Review created", zap.Uint64("review_id", result.ID), zap.Uint64("book_id", result.BookID))
+	return result, nil
+}
+
+// UpdateReview method is used to update rating and text of a review
+func (s *reviewService) UpdateReview(ctx context.Context, r dto.ReviewUpdateDTORequest) (entity.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.UpdateReview")
+	defer span.End()
+	review := s.reviewRepository.GetByID(ctx, r.ID) // Keep the book and user of the review
+	review.Rating = r.Rating
+	review.Text = r.Text
+	result, err := s.reviewRepository.UpdateReview(ctx, review)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to update review", zap.Uint64("review_id", r.ID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Review updated", zap.Uint64("review_id", result.ID), zap.Uint64("book_id", result.BookID))
+	return result, nil
+}
+
+// DeleteReview method is used to delete a review
+func (s *reviewService) DeleteReview(ctx context.Context, r entity.Review) error {
+	ctx, span := tracing.Start(ctx, "ReviewService.DeleteReview")
+	defer span.End()
+	if err := s.reviewRepository.DeleteReview(ctx, r); err != nil {
+		logging.With(ctx, s.logger).Error("Failed to delete review", zap.Uint64("review_id", r.ID), zap.Error(err))
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Review deleted", zap.Uint64("review_id", r.ID), zap.Uint64("book_id", r.BookID))
+	return nil
+}
+
+// IsDuplicateReview method is used to check if the user already reviewed the book
+func (s *reviewService) IsDuplicateReview(ctx context.Context, bookID uint64, userID uint64) bool {
+	ctx, span := tracing.Start(ctx, "ReviewService.IsDuplicateReview")
+	defer span.End()
+	res := s.reviewRepository.IsDuplicateReview(ctx, bookID, userID)
+	return res.Error == nil // A review was found
+}
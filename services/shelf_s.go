@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// ShelfService is a contract about what shelf service can do
+type ShelfService interface {
+	GetByUser(ctx context.Context, userID uint64, publicOnly bool) []entity.Shelf                             // Get the shelves of a user
+	GetByID(ctx context.Context, shelfID uint64) entity.Shelf                                                 // Get a shelf with its books
+	CreateShelf(ctx context.Context, s dto.ShelfCreateDTORequest) entity.Shelf                                // Create a new shelf
+	UpdateShelf(ctx context.Context, s dto.ShelfUpdateDTORequest) entity.Shelf                                // Update name and privacy of a shelf
+	DeleteShelf(ctx context.Context, s entity.Shelf)                                                          // Delete a shelf
+	IsDuplicateName(ctx context.Context, userID uint64, name string, shelfID uint64) bool                     // Check if the user has another shelf with the name
+	GetEntry(ctx context.Context, shelfID uint64, bookID uint64) entity.ShelfEntry                            // Get the entry of a book on a shelf
+	AddBook(ctx context.Context, shelfID uint64, e dto.ShelfEntryCreateDTORequest) (entity.ShelfEntry, error) // Put a book on a shelf
+	UpdateEntry(ctx context.Context, e entity.ShelfEntry, u dto.ShelfEntryUpdateDTORequest) entity.ShelfEntry // Update the reading progress of a book
+	RemoveBook(ctx context.Context, e entity.ShelfEntry)                                                      // Remove a book from a shelf
+}
+
+// Create a shelfService struct to implement ShelfService interface
+type shelfService struct {
+	shelfRepository repository.ShelfRepository
+	logger          *zap.Logger
+}
+
+// NewShelfService method is used to create a new instance of shelfService
+func NewShelfService(shelfRepo repository.ShelfRepository, logger *zap.Logger) ShelfService {
+	return &shelfService{shelfRepository: shelfRepo, logger: logger}
+}
+
+// GetByUser method is used to get the shelves of a user with their book count, only the public ones when publicOnly is set
+func (s *shelfService) GetByUser(ctx context.Context, userID uint64, publicOnly bool) []entity.Shelf {
+	ctx, span := tracing.Start(ctx, "ShelfService.GetByUser")
+	defer span.End()
+	return s.shelfRepository.GetByUser(ctx, userID, publicOnly)
+}
+
+// GetByID method is used to get a shelf with the books the caller may see
+func (s *shelfService) GetByID(ctx context.Context, shelfID uint64) entity.Shelf {
+	ctx, span := tracing.Start(ctx, "ShelfService.GetByID")
+	defer span.End()
+	return s.shelfRepository.GetByID(ctx, shelfID, viewerID(ctx))
+}
+
+// CreateShelf method is used to create a new shelf, private unless asked otherwise
+func (s *shelfService) CreateShelf(ctx context.Context, sh dto.ShelfCreateDTORequest) entity.Shelf {
+	ctx, span := tracing.Start(ctx, "ShelfService.CreateShelf")
+	defer span.End()
+	shelf := entity.Shelf{UserID: sh.UserID, Name: sh.Name, Privacy: sh.Privacy}
+	if shelf.Privacy == "" {
+		shelf.Privacy = entity.ShelfPrivate
+	}
+	result := s.shelfRepository.InsertShelf(ctx, shelf) // Create the shelf
+	logging.With(ctx, s.logger).Info("Shelf created", zap.Uint64("shelf_id", result.ID), zap.Uint64("user_id", result.UserID))
+	return result
+}
+
+// UpdateShelf method is used to update name and privacy of a shelf
+func (s *shelfService) UpdateShelf(ctx context.Context, sh dto.ShelfUpdateDTORequest) entity.Shelf {
+	ctx, span := tracing.Start(ctx, "ShelfService.UpdateShelf")
+	defer span.End()
+	// Only the owner updates their shelf, the updated shelf shows the books listed to them
+	shelf := entity.Shelf{ID: sh.ID, UserID: viewerID(ctx), Name: sh.Name, Privacy: sh.Privacy}
+	result := s.shelfRepository.UpdateShelf(ctx, shelf) // Update the shelf
+	logging.With(ctx, s.logger).Info("Shelf updated", zap.Uint64("shelf_id", result.ID), zap.String("privacy", result.Privacy))
+	return result
+}
+
+// DeleteShelf method is used to delete a shelf and its entries, the books are kept
+func (s *shelfService) DeleteShelf(ctx context.Context, sh entity.Shelf) {
+	ctx, span := tracing.Start(ctx, "ShelfService.DeleteShelf")
+	defer span.End()
+	s.shelfRepository.DeleteShelf(ctx, sh) // delete shelf
+	logging.With(ctx, s.logger).Info("Shelf deleted", zap.Uint64("shelf_id", sh.ID))
+}
+
+// IsDuplicateName method is used to check if the user has another shelf with the name
+func (s *shelfService) IsDuplicateName(ctx context.Context, userID uint64, name string, shelfID uint64) bool {
+	ctx, span := tracing.Start(ctx, "ShelfService.IsDuplicateName")
+	defer span.End()
+	res := s.shelfRepository.IsDuplicateName(ctx, userID, name, shelfID)
+	return res.Error == nil // A shelf was found
+}
+
+// GetEntry method is used to get the entry of a book on a shelf
+func (s *shelfService) GetEntry(ctx context.Context, shelfID uint64, bookID uint64) entity.ShelfEntry {
+	ctx, span := tracing.Start(ctx, "ShelfService.GetEntry")
+	defer span.End()
+	return s.shelfRepository.GetEntry(ctx, shelfID, bookID)
+}
+
+// AddBook method is used to put a book on a shelf
+func (s *shelfService) AddBook(ctx context.Context, shelfID uint64, e dto.ShelfEntryCreateDTORequest) (entity.ShelfEntry, error) {
+	ctx, span := tracing.Start(ctx, "ShelfService.AddBook")
+	defer span.End()
+	entry := entity.ShelfEntry{ShelfID: shelfID, BookID: e.BookID, Progress: e.Progress, StartedAt: e.StartedAt, FinishedAt: e.FinishedAt}
+	result, err := s.shelfRepository.InsertEntry(ctx, entry)
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to add book to shelf", zap.Uint64("shelf_id", shelfID), zap.Uint64("book_id", e.BookID), zap.Error(err))
+		return result, err
+	}
+	logging.With(ctx, s.logger).Info("Book added to shelf", zap.Uint64("shelf_id", shelfID), zap.Uint64("book_id", e.BookID))
+	return result, nil
+}
+
+// UpdateEntry method is used to update the reading progress and dates of a book on a shelf
+func (s *shelfService) UpdateEntry(ctx context.Context, e entity.ShelfEntry, u dto.ShelfEntryUpdateDTORequest) entity.ShelfEntry {
+	ctx, span := tracing.Start(ctx, "ShelfService.UpdateEntry")
+	defer span.End()
+	e.Progress = u.Progress
+	e.StartedAt = u.StartedAt
+	e.FinishedAt = u.FinishedAt
+	return s.shelfRepository.UpdateEntry(ctx, e)
+}
+
+// RemoveBook method is used to remove a book from a shelf
+func (s *shelfService) RemoveBook(ctx context.Context, e entity.ShelfEntry) {
+	ctx, span := tracing.Start(ctx, "ShelfService.RemoveBook")
+	defer span.End()
+	s.shelfRepository.DeleteEntry(ctx, e) // delete entry
+	logging.With(ctx, s.logger).Info("Book removed from shelf", zap.Uint64("shelf_id", e.ShelfID), zap.Uint64("book_id", e.BookID))
+}
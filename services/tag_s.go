@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/helper"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// TagService is a contract about what tag service can do
+type TagService interface {
+	Autocomplete(ctx context.Context, prefix string, limit int) []entity.Tag // Get the most used tags starting with prefix
+}
+
+// Create a tagService struct to implement TagService interface
+type tagService struct {
+	tagRepository repository.TagRepository
+	logger        *zap.Logger
+}
+
+// NewTagService method is used to create a new instance of tagService
+func NewTagService(tagRepo repository.TagRepository, logger *zap.Logger) TagService {
+	return &tagService{tagRepository: tagRepo, logger: logger}
+}
+
+// Autocomplete method is used to get the most used tags starting with prefix
+func (s *tagService) Autocomplete(ctx context.Context, prefix string, limit int) []entity.Tag {
+	ctx, span := tracing.Start(ctx, "TagService.Autocomplete")
+	defer span.End()
+	return s.tagRepository.Search(ctx, helper.NormalizeTag(prefix), limit)
+}
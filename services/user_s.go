@@ -1,43 +1,56 @@
 package services
 
 import (
-	"log"
+	"context"
 
 	"github.com/mashingan/smapping"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/dto"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
 	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
 )
 
 // Create User Service Interface for User Service Implementation
 type UserService interface {
-	UpdateUser(user dto.UserUpdateDTORequest) entity.User
-	GetUser(userID int64) entity.User
+	UpdateUser(ctx context.Context, user dto.UserUpdateDTORequest) (entity.User, error)
+	GetUser(ctx context.Context, userID int64) entity.User
 }
 
 // Create userService struct to implement UserService interface
 type userService struct {
 	userRepository repository.UserRepository
+	logger         *zap.Logger
 }
 
 // NewUserService method is used to create a new instance of UserService
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepository: userRepo}
+func NewUserService(userRepo repository.UserRepository, logger *zap.Logger) UserService {
+	return &userService{userRepository: userRepo, logger: logger}
 }
 
 // UpdateUser method is used to update user
-func (s *userService) UpdateUser(user dto.UserUpdateDTORequest) entity.User {
+func (s *userService) UpdateUser(ctx context.Context, user dto.UserUpdateDTORequest) (entity.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
 	userToUpdate := entity.User{}                                        // userToUpdate is a new instance of User
 	err := smapping.FillStruct(&userToUpdate, smapping.MapFields(&user)) // Fill the userToUpdate with the user data
 	if err != nil {
-		log.Fatalf("Error while mapping user update dto to entity: %v", err)
+		logging.With(ctx, s.logger).Fatal("Error while mapping user update dto to entity", zap.Error(err))
 	}
-	updatedUser := s.userRepository.UpdateUser(userToUpdate) // Update the user
-	return updatedUser
+	updatedUser, err := s.userRepository.UpdateUser(ctx, userToUpdate) // Update the user
+	if err != nil {
+		logging.With(ctx, s.logger).Error("Failed to update user", zap.Uint64("user_id", userToUpdate.ID), zap.Error(err))
+		return updatedUser, err
+	}
+	logging.With(ctx, s.logger).Info("User updated", zap.Uint64("user_id", updatedUser.ID))
+	return updatedUser, nil
 }
 
 // GetUser method is used to get user by userID
-func (s *userService) GetUser(userID int64) entity.User {
-	user := s.userRepository.ProfileUser(userID) // Get the user by userID
+func (s *userService) GetUser(ctx context.Context, userID int64) entity.User {
+	ctx, span := tracing.Start(ctx, "UserService.GetUser")
+	defer span.End()
+	user := s.userRepository.ProfileUser(ctx, userID) // Get the user by userID
 	return user
 }
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/entity"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/logging"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/repository"
+	"github.com/sumitroajiprabowo/gin-gorm-jwt-mysql/tracing"
+	"go.uber.org/zap"
+)
+
+// WaitlistService is a contract about what waitlist service can do
+type WaitlistService interface {
+	GetQueue(ctx context.Context, bookID uint64) ([]entity.WaitlistEntry, error)                     // Get the queue of a book in order
+	Join(ctx context.Context, bookID uint64, userID uint64) (entity.WaitlistEntry, error)            // Add the user at the end of the queue
+	Leave(ctx context.Context, bookID uint64, userID uint64) error                                   // Remove the user from the queue
+	Accept(ctx context.Context, bookID uint64, userID uint64) (entity.WaitlistEntry, error)          // Take the book offered to the user
+	SetAvailability(ctx context.Context, book entity.Book, availability string) (entity.Book, error) // Mark the book available or in use
+}
+
+// Create a waitlistService struct to implement WaitlistService interface
+type waitlistService struct {
+	waitlistRepository repository.WaitlistRepository
+	bookRepository     repository.BookRepository
+	offerTTL           time.Duration // how long the book stays offered to the first person in line
+	logger             *zap.Logger
+}
+
+// NewWaitlistService method is used to create a new instance of waitlistService
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, bookRepo repository.BookRepository, offerTTL time.Duration, logger *zap.Logger) WaitlistService {
+	return &waitlistService{waitlistRepository: waitlistRepo, bookRepository: bookRepo, offerTTL: offerTTL, logger: logger}
+}
+
+// GetQueue method is used to get the waiting and offered entries of the book in order, expired offers move on to the next person first
+func (s *waitlistService) GetQueue(ctx context.Context, bookID uint64) ([]entity.WaitlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "WaitlistService.GetQueue")
+	defer span.End()
+	return s.waitlistRepository.GetQueue(ctx, bookID, s.offerTTL)
+}
+
+// Join method is used to add the user at the end of the queue of the book
+func (s *waitlistService) Join(ctx context.Context, bookID uint64, userID uint64) (entity.WaitlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "WaitlistService.Join")
+	defer span.End()
+	entry, err := s.waitlistRepository.Join(ctx, bookID, userID, s.offerTTL)
+	if err != nil {
+		return entry, err
+	}
+	logging.With(ctx, s.logger).Info("Waitlist joined", zap.Uint64("book_id", bookID), zap.Uint64("user_id", userID), zap.Int("position", entry.Position))
+	return entry, nil
+}
+
+// Leave method is used to remove the user from the queue of the book, an offer they held moves on to the next person
+func (s *waitlistService) Leave(ctx context.Context, bookID uint64, userID uint64) error {
+	ctx, span := tracing.Start(ctx, "WaitlistService.Leave")
+	defer span.End()
+	if err := s.waitlistRepository.Leave(ctx, bookID, userID, s.offerTTL); err != nil {
+		return err
+	}
+	logging.With(ctx, s.logger).Info("Waitlist left", zap.Uint64("book_id", bookID), zap.Uint64("user_id", userID))
+	return nil
+}
+
+// Accept method is used to take the book offered to the user, the book is in use afterwards
+func (s *waitlistService) Accept(ctx context.Context, bookID uint64, userID uint64) (entity.WaitlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "WaitlistService.Accept")
+	defer span.End()
+	entry, err := s.waitlistRepository.Accept(ctx, bookID, userID, s.offerTTL)
+	if err != nil {
+		return entry, err
+	}
+	logging.With(ctx, s.logger).Info("Waitlist offer accepted", zap.Uint64("book_id", bookID), zap.Uint64("user_id", userID))
+	return entry, nil
+}
+
+// SetAvailability method is used to mark the book available, which offers it to the first person in line, or in use
+func (s *waitlistService) SetAvailability(ctx context.Context, book entity.Book, availability string) (entity.Book, error) {
+	ctx, span := tracing.Start(ctx, "WaitlistService.SetAvailability")
+	defer span.End()
+	if err := s.waitlistRepository.SetAvailability(ctx, book.ID, availability, s.offerTTL); err != nil {
+		return book, err
+	}
+	logging.With(ctx, s.logger).Info("Book availability changed", zap.Uint64("book_id", book.ID), zap.String("availability", availability))
+	return s.bookRepository.GetByID(ctx, book.ID), nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localStore keeps the blobs as files below a directory, public blobs are served under a base URL
+// and private ones by the download handler of the application
+type localStore struct {
+	dir         string
+	baseURL     string
+	downloadURL string
+	signer      *Signer
+}
+
+/*
+NewLocalStore method is used to create a BlobStore writing to dir, the public files are expected to be
+served under baseURL and the signed URLs point to downloadURL, which must check them with the signer
+*/
+func NewLocalStore(dir string, baseURL string, downloadURL string, signer *Signer) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &localStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/"), downloadURL: downloadURL, signer: signer}, nil
+}
+
+// Put writes the blob to a temporary file first, so readers never see a partial file
+func (s *localStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := io.Copy(tmp, io.LimitReader(r, size)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Delete removes the file of the blob
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Get opens the file of the blob
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// URL returns the base URL followed by the key
+func (s *localStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// SignedURL returns the download URL with the signed key, file name and expiry
+func (s *localStore) SignedURL(ctx context.Context, key string, fileName string, ttl time.Duration) (string, error) {
+	return s.downloadURL + "?" + s.signer.Sign(key, fileName, ttl).Encode(), nil
+}
+
+// path returns the file name of the key, keys escaping the directory are rejected
+func (s *localStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", errors.New("invalid blob key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
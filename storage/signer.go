@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidSignature is returned when a signed URL was altered or has expired
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+
+// Signer signs the download URLs of the blobs served by the application itself
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner method is used to create a Signer with the secret key
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret), now: time.Now}
+}
+
+// Sign returns the query of a download of the key as fileName until ttl has passed
+func (s *Signer) Sign(key string, fileName string, ttl time.Duration) url.Values {
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	return url.Values{
+		"key":       {key},
+		"name":      {fileName},
+		"expires":   {expires},
+		"signature": {s.signature(key, fileName, expires)},
+	}
+}
+
+// Verify checks the signature and expiry of the query and returns the key and file name
+func (s *Signer) Verify(query url.Values) (key string, fileName string, err error) {
+	key, fileName, expires := query.Get("key"), query.Get("name"), query.Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return "", "", ErrInvalidSignature
+	}
+	expected := s.signature(key, fileName, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+		return "", "", ErrInvalidSignature
+	}
+	return key, fileName, nil
+}
+
+// signature returns the hex HMAC-SHA256 of the signed fields
+func (s *Signer) signature(key string, fileName string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + fileName + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
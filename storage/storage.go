@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when the store has no blob with the key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore is a contract of what a file storage backend should be able to do
+type BlobStore interface {
+	// Put stores size bytes of r under the key, replacing any previous blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete removes the blob with the key, deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// Get opens the blob with the key, or returns ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// URL returns the public URL of the blob with the key
+	URL(key string) string
+	// SignedURL returns a URL downloading the private blob as fileName until ttl has passed
+	SignedURL(ctx context.Context, key string, fileName string, ttl time.Duration) (string, error)
+}
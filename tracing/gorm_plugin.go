@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the key used to store the active span in the gorm statement settings
+const spanKey = "tracing:span"
+
+// GormPlugin is a gorm plugin that creates a span for every SQL statement
+type GormPlugin struct{}
+
+// NewGormPlugin method is used to create a new instance of GormPlugin
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+// Name returns the name of the plugin
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the before and after callbacks for every gorm operation
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	// register callbacks for create statements
+	if err := cb.Create().Before("gorm:create").Register("tracing:before_create", before("gorm.create")); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("tracing:after_create", after); err != nil {
+		return err
+	}
+
+	// register callbacks for query statements (including Preload)
+	if err := cb.Query().Before("gorm:query").Register("tracing:before_query", before("gorm.query")); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("tracing:after_query", after); err != nil {
+		return err
+	}
+
+	// register callbacks for update statements
+	if err := cb.Update().Before("gorm:update").Register("tracing:before_update", before("gorm.update")); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("tracing:after_update", after); err != nil {
+		return err
+	}
+
+	// register callbacks for delete statements
+	if err := cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("gorm.delete")); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("tracing:after_delete", after); err != nil {
+		return err
+	}
+
+	// register callbacks for row statements
+	if err := cb.Row().Before("gorm:row").Register("tracing:before_row", before("gorm.row")); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("tracing:after_row", after); err != nil {
+		return err
+	}
+
+	// register callbacks for raw statements
+	if err := cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("gorm.raw")); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("tracing:after_raw", after)
+}
+
+// before returns a callback that starts a span with the given name
+func before(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := Start(db.Statement.Context, name, trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx    // child statements (preload) are nested in this span
+		db.InstanceSet(spanKey, span) // keep the span for the after callback
+	}
+}
+
+// after ends the span started by the before callback and records the statement
+func after(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system", db.Dialector.Name()),
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	// record the error, gorm.ErrRecordNotFound is not a failure of the statement
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}